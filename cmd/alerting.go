@@ -0,0 +1,129 @@
+package cmd
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"fmt"
+	"github.com/bcdevtools/consvp/aos"
+	"github.com/bcdevtools/consvp/config"
+	"github.com/bcdevtools/consvp/engine/alerting"
+	"github.com/bcdevtools/consvp/engine/broadcast_worker"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"github.com/bcdevtools/consvp/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+const flagAlertWebhook = "alert-webhook"
+
+// readAlertEngine returns the alerting engine built from the configuration, nil if no alert rule configured.
+// Webhook URL is taken from flag, fallback to the configuration.
+// Exit the app if the alerting configuration is invalid.
+func readAlertEngine(cmd *cobra.Command, cfg *config.Config, chainId string, watchlist enginetypes.Watchlist) *alerting.Engine {
+	webhookUrl := cfg.Alerting.WebhookUrl
+	if cmd.Flags().Changed(flagAlertWebhook) {
+		webhookUrl, _ = cmd.Flags().GetString(flagAlertWebhook)
+		webhookUrl = strings.TrimSpace(webhookUrl)
+	}
+
+	if len(cfg.Alerting.Rules) < 1 {
+		if len(webhookUrl) > 0 {
+			utils.PrintlnStdErr("WARN: alert webhook is provided but no alert rule configured in the config file")
+		}
+		return nil
+	}
+
+	exitOnErr := func(err error) {
+		if err != nil {
+			utils.PrintlnStdErr("ERR: failed to initialize alerting")
+			utils.PrintlnStdErr(err)
+			aos.Exit(1)
+		}
+	}
+
+	if len(webhookUrl) < 1 {
+		exitOnErr(fmt.Errorf("alert webhook is required, provide via --%s or the config file", flagAlertWebhook))
+	}
+
+	var rules []alerting.Rule
+	for _, ruleConfig := range cfg.Alerting.Rules {
+		ruleFor, _ := ruleConfig.GetFor() // validated when loading
+
+		validators := enginetypes.Watchlist(ruleConfig.Validators)
+		if len(validators) < 1 {
+			validators = watchlist
+		}
+
+		rules = append(rules, alerting.Rule{
+			Name:       ruleConfig.Name,
+			Type:       alerting.RuleType(ruleConfig.Type),
+			Threshold:  ruleConfig.Threshold,
+			For:        ruleFor,
+			Validators: validators,
+		})
+	}
+
+	notifier, err := alerting.NewWebhookNotifier(webhookUrl, cfg.Alerting.Headers, cfg.Alerting.BodyTemplate, nil)
+	exitOnErr(err)
+
+	repeatInterval, _ := cfg.Alerting.GetRepeatInterval() // validated when loading
+	engine, err := alerting.NewEngine(rules, chainId, &loggingNotifier{notifier: notifier}, repeatInterval)
+	exitOnErr(err)
+
+	return engine
+}
+
+// newAlertWorker returns the worker evaluating the alert rules in background,
+// so the slow webhook does not block fetching.
+// Fetching errors must be offered as well, they are evaluated as the height has not changed.
+func newAlertWorker(engine *alerting.Engine) *broadcast_worker.Worker {
+	var fetchFailureNotifyFailing bool
+
+	return broadcast_worker.NewWorker(
+		func(information *enginetypes.NextBlockVotingInformation) (error, bool) {
+			return joinAlertNotifyErrors(engine.Evaluate(information)), false
+		},
+		func(result broadcast_worker.Result) {
+			// result handler is called from the worker goroutine, so the engine is never evaluated concurrently
+			if result.FetchingIssue {
+				err := joinAlertNotifyErrors(engine.EvaluateFetchFailure())
+				if err != nil && !fetchFailureNotifyFailing { // prevent flooding
+					utils.StdHelper.PrintlnErr(err, nil)
+				}
+				fetchFailureNotifyFailing = err != nil
+				return
+			}
+			if result.Err == nil {
+				return
+			}
+			if result.Stats.ConsecutiveFailures == 1 { // prevent flooding
+				utils.StdHelper.PrintlnErr(result.Err, nil)
+			}
+		},
+		0, 0, // default backoff
+	)
+}
+
+func joinAlertNotifyErrors(errs []error) error {
+	if len(errs) < 1 {
+		return nil
+	}
+	return errors.Wrap(errs[0], fmt.Sprintf("failed to deliver %d alert notifications", len(errs)))
+}
+
+var _ alerting.Notifier = (*loggingNotifier)(nil)
+
+// loggingNotifier prints the delivered alert notifications to stderr.
+type loggingNotifier struct {
+	notifier alerting.Notifier
+}
+
+func (n *loggingNotifier) Notify(alert alerting.Alert) error {
+	if err := n.notifier.Notify(alert); err != nil {
+		return err
+	}
+
+	// stderr, to keep stdout machine-readable in the plain & jsonl output modes
+	utils.StdHelper.PrintlnInfoStdErr(fmt.Sprintf("ALERT [%s] %s: %s", alert.Status, alert.Rule, alert.Summary))
+	return nil
+}
@@ -0,0 +1,102 @@
+package cmd
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"fmt"
+	"github.com/bcdevtools/consvp/aos"
+	"github.com/bcdevtools/consvp/config"
+	pvssi "github.com/bcdevtools/consvp/engine/prevote_streaming_service/prevote_ss_impl"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"github.com/bcdevtools/consvp/utils"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+const (
+	flagConfig           = "config"
+	flagStreamingServer  = "streaming-server"
+	flagStreamingTimeout = "streaming-timeout"
+	flagStreamingProxy   = "streaming-proxy"
+	flagStreamingCaFile  = "streaming-ca-file"
+	flagStreamingHeader  = "streaming-header"
+	flagWatchlist        = "watchlist"
+)
+
+// readConfig loads the configuration from the file provided via flag,
+// or from the default location if exists.
+func readConfig(cmd *cobra.Command) *config.Config {
+	configFilePath, _ := cmd.Flags().GetString(flagConfig)
+	optional := len(configFilePath) < 1
+	if optional {
+		var err error
+		configFilePath, err = config.DefaultConfigFilePath()
+		if err != nil {
+			return &config.Config{}
+		}
+	}
+
+	cfg, err := config.LoadConfig(configFilePath, optional)
+	if err != nil {
+		utils.PrintlnStdErr("ERR: failed to load config file " + configFilePath)
+		utils.PrintlnStdErr(err)
+		aos.Exit(1)
+	}
+
+	return cfg
+}
+
+// readStreamingTransportOptions returns the streaming server URL, empty if not provided,
+// and the HTTP transport options, from flags, fallback to the configuration.
+func readStreamingTransportOptions(cmd *cobra.Command, cfg *config.Config) (streamingServerUrl string, options pvssi.HttpTransportOptions) {
+	streamingServerUrl = cfg.Streaming.ServerUrl
+	if cmd.Flags().Changed(flagStreamingServer) {
+		streamingServerUrl, _ = cmd.Flags().GetString(flagStreamingServer)
+		streamingServerUrl = strings.TrimSpace(streamingServerUrl)
+		if !strings.HasPrefix(streamingServerUrl, "http://") && !strings.HasPrefix(streamingServerUrl, "https://") {
+			utils.PrintlnStdErr("ERR: streaming server URL must start with http:// or https://")
+			aos.Exit(1)
+		}
+	}
+	streamingServerUrl = strings.TrimSuffix(streamingServerUrl, "/")
+
+	options.Timeout, _ = cfg.Streaming.GetTimeout() // validated when loading
+	if cmd.Flags().Changed(flagStreamingTimeout) {
+		options.Timeout, _ = cmd.Flags().GetDuration(flagStreamingTimeout)
+	}
+
+	options.ProxyUrl = cfg.Streaming.ProxyUrl
+	if cmd.Flags().Changed(flagStreamingProxy) {
+		options.ProxyUrl, _ = cmd.Flags().GetString(flagStreamingProxy)
+	}
+
+	options.CaFile = cfg.Streaming.CaFile
+	if cmd.Flags().Changed(flagStreamingCaFile) {
+		options.CaFile, _ = cmd.Flags().GetString(flagStreamingCaFile)
+	}
+
+	options.Headers = make(map[string]string)
+	for key, value := range cfg.Streaming.Headers {
+		options.Headers[key] = value
+	}
+	headers, _ := cmd.Flags().GetStringArray(flagStreamingHeader)
+	for _, header := range headers {
+		key, value, found := strings.Cut(header, ":")
+		key = strings.TrimSpace(key)
+		if !found || len(key) < 1 {
+			utils.PrintlnStdErr(fmt.Sprintf("ERR: bad header '%s', expected format 'Name: Value'", header))
+			aos.Exit(1)
+		}
+		options.Headers[key] = strings.TrimSpace(value)
+	}
+
+	return
+}
+
+// readWatchlist returns the watchlist from flag, fallback to the configuration.
+func readWatchlist(cmd *cobra.Command, cfg *config.Config) enginetypes.Watchlist {
+	if cmd.Flags().Changed(flagWatchlist) {
+		watchlist, _ := cmd.Flags().GetStringSlice(flagWatchlist)
+		return watchlist
+	}
+	return cfg.Watchlist
+}
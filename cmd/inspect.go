@@ -0,0 +1,105 @@
+package cmd
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"fmt"
+	"github.com/bcdevtools/consvp/aos"
+	"github.com/bcdevtools/consvp/engine/consensus_service/default_conss_impl"
+	"github.com/bcdevtools/consvp/engine/rpc_client/offline_rpc_impl"
+	"github.com/bcdevtools/consvp/utils"
+	"github.com/spf13/cobra"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const flagValidators = "validators"
+
+// GetInspectCommand returns the command to render a saved consensus state without any live node.
+func GetInspectCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inspect [consensus_state.json]",
+		Short: "Render a saved '/consensus_state' or '/dump_consensus_state' response offline",
+		Long: `Render a saved '/consensus_state' or '/dump_consensus_state' response offline, without any live node.
+The '/validators' response at the same height must be provided via '--validators' when inspecting '/consensus_state',
+repeat the flag for each page when the validator set does not fit in a single page,
+it is optional for '/dump_consensus_state' because the validator set is already included.
+Monikers are not available offline, validators are named by their fingerprint address.
+`,
+		Args: cobra.ExactArgs(1),
+		Run:  inspectHandler,
+	}
+
+	cmd.Flags().StringArray(flagValidators, []string{}, "saved response of '/validators' at the same height as the consensus state, required for '/consensus_state'. Repeat the flag to provide all the pages.")
+
+	return cmd
+}
+
+func inspectHandler(cmd *cobra.Command, args []string) {
+	outputMode := readOutputMode(cmd)
+	readRenderingStyle(cmd)
+
+	consensusStateJson, err := os.ReadFile(args[0])
+	if err != nil {
+		utils.PrintlnStdErr("ERR: failed to read consensus state file")
+		utils.PrintlnStdErr(err)
+		aos.Exit(1)
+	}
+
+	var validatorsJsonPages [][]byte
+	validatorsFilePaths, _ := cmd.Flags().GetStringArray(flagValidators)
+	for _, validatorsFilePath := range validatorsFilePaths {
+		validatorsJson, err := os.ReadFile(validatorsFilePath)
+		if err != nil {
+			utils.PrintlnStdErr("ERR: failed to read validators file", validatorsFilePath)
+			utils.PrintlnStdErr(err)
+			aos.Exit(1)
+		}
+		validatorsJsonPages = append(validatorsJsonPages, validatorsJson)
+	}
+
+	rpcClient, err := offline_rpc_impl.NewOfflineRpcClient(consensusStateJson, validatorsJsonPages)
+	if err != nil {
+		utils.PrintlnStdErr("ERR: failed to load saved responses")
+		utils.PrintlnStdErr(err)
+		aos.Exit(1)
+	}
+
+	lightValidators, _ := rpcClient.LightValidators()
+
+	consensusService := default_conss_impl.NewDefaultConsensusServiceClientImpl(rpcClient)
+	nextBlockVotingInfo, err := consensusService.GetNextBlockVotingInformation(lightValidators)
+	if err != nil {
+		utils.PrintlnStdErr("ERR: failed to get next block voting information")
+		utils.PrintlnStdErr(err)
+		aos.Exit(1)
+	}
+
+	switch outputMode {
+	case outputPlain:
+		fmt.Println(formatPlainVotingInfo(nextBlockVotingInfo, time.Now()))
+		return
+	case outputJsonl:
+		if err := writeJsonLine(os.Stdout, nextBlockVotingInfo, time.Now()); err != nil {
+			utils.PrintlnStdErr("ERR: failed to write JSON line")
+			utils.PrintlnStdErr(err)
+			aos.Exit(1)
+		}
+		return
+	}
+
+	chainId, _, _ := rpcClient.NodeInfo()
+	if len(chainId) < 1 {
+		chainId = filepath.Base(args[0])
+	}
+
+	renderVotingInfoChan := make(chan interface{})
+	startRendering(outputMode, screenOptions{
+		chainId:          chainId,
+		consensusVersion: "?",
+	}, renderVotingInfoChan, nil)
+
+	renderVotingInfoChan <- nextBlockVotingInfo
+
+	select {} // wait for user to quit the terminal UI
+}
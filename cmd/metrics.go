@@ -0,0 +1,56 @@
+package cmd
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"context"
+	"github.com/bcdevtools/consvp/engine/broadcast_worker"
+	"github.com/bcdevtools/consvp/engine/metrics"
+	pvss "github.com/bcdevtools/consvp/engine/prevote_streaming_service"
+	"github.com/pkg/errors"
+	"net"
+	"net/http"
+	"time"
+)
+
+// serveMetrics serves the metrics at '/metrics' on the given address in background,
+// returns the function to shut down the server.
+func serveMetrics(listenAddress string, metricsExporter *metrics.Metrics) (shutdown func(), err error) {
+	listener, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metricsExporter.Handler())
+
+	httpServer := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		_ = httpServer.Serve(listener)
+	}()
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(ctx)
+	}, nil
+}
+
+// observeBroadcastResult records the result of a broadcast attempt to the sink.
+// Results of paused broadcasting due to fetching issue are not recorded.
+func observeBroadcastResult(metricsExporter *metrics.Metrics, sinkName string, result broadcast_worker.Result) {
+	if result.FetchingIssue {
+		return
+	}
+
+	if result.Err == nil {
+		metricsExporter.ObserveBroadcast(sinkName, metrics.BroadcastResultSuccess)
+	} else if errors.Is(result.Err, pvss.ErrSnapshotUnchanged) {
+		metricsExporter.ObserveBroadcast(sinkName, metrics.BroadcastResultSkipped)
+	} else {
+		metricsExporter.ObserveBroadcast(sinkName, metrics.BroadcastResultFailure)
+	}
+}
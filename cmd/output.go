@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/bcdevtools/consvp/aos"
+	"github.com/bcdevtools/consvp/utils"
+	"github.com/spf13/cobra"
+	"io"
+	"os"
+	"strings"
+)
+
+const flagOutput = "output"
+
+const (
+	outputTui   = "tui"
+	outputPlain = "plain"
+	outputJsonl = "jsonl"
+)
+
+// infoWriter is where the informational messages to be written into.
+var infoWriter io.Writer = os.Stdout
+
+// printlnInfo prints informational messages, which are not part of the output data.
+func printlnInfo(a ...any) {
+	_, _ = fmt.Fprintln(infoWriter, a...)
+}
+
+// readOutputMode reads the output mode from flag, automatically fallback to plain text when no TTY.
+// Informational messages will be redirected to stderr if the output is machine-readable.
+func readOutputMode(cmd *cobra.Command) string {
+	outputMode, _ := cmd.Flags().GetString(flagOutput)
+	outputMode = strings.ToLower(strings.TrimSpace(outputMode))
+	if !cmd.Flags().Changed(flagOutput) && !utils.IsTerminal(os.Stdout) {
+		outputMode = outputPlain // automatically fallback to plain text when no TTY
+	}
+
+	switch outputMode {
+	case outputTui, outputPlain, outputJsonl:
+		break
+	default:
+		utils.PrintlnStdErr("ERR: bad output mode: " + outputMode)
+		aos.Exit(1)
+	}
+
+	if outputMode == outputJsonl {
+		infoWriter = os.Stderr // keep stdout machine-readable
+	}
+
+	return outputMode
+}
+
+// startRendering starts rendering the voting information and status, in background, using the given output mode.
+func startRendering(outputMode string, opts screenOptions, votingInfoChan <-chan interface{}, statusChan <-chan string) {
+	switch outputMode {
+	case outputPlain:
+		go printPlain(votingInfoChan, statusChan)
+	case outputJsonl:
+		go printJsonLines(votingInfoChan, statusChan)
+	default:
+		go drawScreen(opts, votingInfoChan, statusChan)
+	}
+}
@@ -6,13 +6,19 @@ import (
 	"fmt"
 	"github.com/bcdevtools/consvp/aos"
 	"github.com/bcdevtools/consvp/constants"
+	"github.com/bcdevtools/consvp/engine/broadcast_sink/streaming_sink_impl"
+	"github.com/bcdevtools/consvp/engine/broadcast_worker"
 	conss "github.com/bcdevtools/consvp/engine/consensus_service"
 	dconsi "github.com/bcdevtools/consvp/engine/consensus_service/default_conss_impl"
+	"github.com/bcdevtools/consvp/engine/metrics"
 	pvss "github.com/bcdevtools/consvp/engine/prevote_streaming_service"
 	mpvssi "github.com/bcdevtools/consvp/engine/prevote_streaming_service/mock_local_prevote_ss_impl"
 	pvssi "github.com/bcdevtools/consvp/engine/prevote_streaming_service/prevote_ss_impl"
+	"github.com/bcdevtools/consvp/engine/recording"
 	"github.com/bcdevtools/consvp/engine/rpc_client"
 	drpci "github.com/bcdevtools/consvp/engine/rpc_client/default_rpc_impl"
+	"github.com/bcdevtools/consvp/engine/session_store"
+	"github.com/bcdevtools/consvp/engine/timeline"
 	enginetypes "github.com/bcdevtools/consvp/engine/types"
 	"github.com/bcdevtools/consvp/utils"
 	corecodec "github.com/bcdevtools/cvp-streaming-core/codec"
@@ -38,11 +44,21 @@ const (
 	flagResumeStreaming     = "resume-streaming"
 	flagMockStreamingServer = "mock-streaming-server"
 	flagCodec               = "codec"
+	flagSignedBlocksWindow  = "signed-blocks-window"
+	flagRecord              = "record"
+	flagSessionId           = "session-id"
+	flagMetricsAddr         = "metrics-addr"
 )
 
 const defaultRefreshInterval = 3 * time.Second
 const rapidRefreshInterval = 1 * time.Second
 
+// lightValidatorsRefreshInterval is the interval to re-fetch light validators, to keep signing information up to date.
+const lightValidatorsRefreshInterval = 1 * time.Minute
+
+// streamingSessionDuration is the duration of a streaming session, since registered, as enforced by the streaming server.
+const streamingSessionDuration = 12 * time.Hour
+
 func pvtopHandler(cmd *cobra.Command, args []string) {
 	defer utils.AppExitHelper.ExecuteFunctionsUponAppExit()
 
@@ -51,8 +67,11 @@ func pvtopHandler(cmd *cobra.Command, args []string) {
 		aos.Exit(1)
 	}
 
-	fmt.Println(constants.APP_INTRO)
-	fmt.Println()
+	outputMode := readOutputMode(cmd)
+	readRenderingStyle(cmd)
+
+	printlnInfo(constants.APP_INTRO)
+	printlnInfo()
 
 	consumerUrl, err := readPvTopArg(args, 0, true)
 	if err != nil {
@@ -62,7 +81,7 @@ func pvtopHandler(cmd *cobra.Command, args []string) {
 
 	if consumerUrl == "" {
 		consumerUrl = "http://localhost:26657"
-		fmt.Println("No port/host/consumer provided, using default:", consumerUrl)
+		printlnInfo("No port/host/consumer provided, using default:", consumerUrl)
 	}
 
 	providerUrl, err := readPvTopArg(args, 1, true)
@@ -77,6 +96,19 @@ func pvtopHandler(cmd *cobra.Command, args []string) {
 	streamingMode := cmd.Flags().Changed(flagStreaming)
 	resumeStreaming := cmd.Flags().Changed(flagResumeStreaming)
 	codecVersion, _ := cmd.Flags().GetString(flagCodec)
+	signedBlocksWindowSize, _ := cmd.Flags().GetInt(flagSignedBlocksWindow)
+	if signedBlocksWindowSize < 0 {
+		utils.PrintlnStdErr("ERR: signed blocks window size must not be negative")
+		aos.Exit(1)
+	}
+	watchMissingPreVoteAfter, _ := cmd.Flags().GetDuration(flagWatchMissingPreVoteAfter)
+	sessionIdOverride, _ := cmd.Flags().GetString(flagSessionId)
+	if len(sessionIdOverride) < 1 {
+		sessionIdOverride = os.Getenv(constants.ENV_STREAMING_SESSION_ID)
+	}
+	if cmd.Flags().Changed(flagSessionId) {
+		resumeStreaming = true
+	}
 	if resumeStreaming {
 		streamingMode = true
 	}
@@ -114,17 +146,58 @@ func pvtopHandler(cmd *cobra.Command, args []string) {
 		if !streamingMode {
 			panic(fmt.Errorf("cannot mock streaming server if not in streaming mode, requires --%s or --%s", flagStreaming, flagResumeStreaming))
 		}
+		if cmd.Flags().Changed(flagStreamingServer) {
+			utils.PrintlnStdErr(fmt.Sprintf("ERR: --%s cannot be used together with --%s", flagStreamingServer, flagMockStreamingServer))
+			aos.Exit(1)
+		}
+	}
+
+	cfg := readConfig(cmd)
+	streamingServerUrl, streamingTransportOptions := readStreamingTransportOptions(cmd, cfg)
+	if len(streamingServerUrl) < 1 {
+		streamingServerUrl = coreconstants.STREAMING_BASE_URL
+	}
+	if strings.EqualFold(mockStreamingServer, "local") {
+		streamingServerUrl = coreconstants.STREAMING_BASE_URL_LOCAL
 	}
 
 	utils.AppExitHelper.RegisterFuncUponAppExit(func() {
 		utils.StdHelper.PrintQueuedMessages()
 	})
 
+	if logFile := readLogFile(cmd); logFile != nil {
+		utils.StdHelper.SetLogWriter(logFile)
+		utils.AppExitHelper.RegisterFuncUponAppExit(func() {
+			utils.StdHelper.SetLogWriter(nil)
+			_ = logFile.Close()
+		})
+	}
+
 	var shouldExit bool
 	utils.AppExitHelper.RegisterFuncUponAppExit(func() {
 		shouldExit = true
 	})
 
+	var recorder *recording.Recorder
+	utils.AppExitHelper.RegisterFuncUponAppExit(func() {
+		if recorder != nil {
+			_ = recorder.Close()
+		}
+	})
+
+	var metricsExporter *metrics.Metrics
+	if metricsAddress, _ := cmd.Flags().GetString(flagMetricsAddr); len(metricsAddress) > 0 {
+		metricsExporter = metrics.NewMetrics()
+		shutdownMetricsServer, errServe := serveMetrics(metricsAddress, metricsExporter)
+		if errServe != nil {
+			utils.PrintlnStdErr("ERR: failed to serve metrics on " + metricsAddress)
+			utils.PrintlnStdErr(errServe)
+			aos.Exit(1)
+		}
+		utils.AppExitHelper.RegisterFuncUponAppExit(shutdownMetricsServer)
+		printlnInfo("Serving metrics at", metricsAddress+"/metrics")
+	}
+
 	var rpcClient rpc_client.RpcClient
 	var consensusService conss.ConsensusService
 	var preVoteStreamingService pvss.PreVoteStreamingService
@@ -138,22 +211,48 @@ func pvtopHandler(cmd *cobra.Command, args []string) {
 		}
 	})
 
-	rpcClient = drpci.NewDefaultRpcClient(consumerUrl, providerUrl, !useHttp)
+	defaultRpcClient := drpci.NewDefaultRpcClient(consumerUrl, providerUrl, !useHttp)
+	if metricsExporter != nil {
+		defaultRpcClient.SetObserver(metricsExporter.ObserveRpc)
+	}
+	rpcClient = defaultRpcClient
 	consensusService = dconsi.NewDefaultConsensusServiceClientImpl(rpcClient)
 
-	mod5 := rand.Uint32() % 5
-	if mod5 == 0 {
-		fmt.Println("Tips: press 'Q' or 'Ctrl+C' to exit")
-	} else if mod5 == 1 {
-		fmt.Println("Tips: press 'K' / '↑' to scroll up and 'J' / '↓' to scroll down")
+	if outputMode == outputTui {
+		mod5 := rand.Uint32() % 5
+		if mod5 == 0 {
+			printlnInfo("Tips: press 'Q' or 'Ctrl+C' to exit")
+		} else if mod5 == 1 {
+			printlnInfo(fmt.Sprintf("Tips: press 'k' / '%s' and 'j' / '%s' to move the cursor, 'Enter' to view details of the selected validator", symbols.scrollUp, symbols.scrollDown))
+		}
 	}
 
 	var chainId, consensusVersion, moniker string = rpcClient.NodeInfo()
 	var lightValidators enginetypes.LightValidators
 	var preVoteStreamingShareViewUrl string
+	var renewedShareViewUrl string // new share view URL when session has been renewed automatically
 
-	fmt.Println("Please wait, getting validators information...")
+	if recordFilePath, _ := cmd.Flags().GetString(flagRecord); len(recordFilePath) > 0 {
+		recorder, err = recording.NewRecorder(recordFilePath, chainId, consensusVersion, moniker)
+		if err != nil {
+			utils.PrintlnStdErr("ERR: failed to start recording")
+			utils.PrintlnStdErr(err)
+			aos.Exit(1)
+		}
+		printlnInfo("Recording session into", recordFilePath)
+	}
+
+	printlnInfo("Please wait, getting validators information...")
 	lightValidators, _ = rpcClient.LightValidators()
+	recordLightValidators(recorder, lightValidators)
+
+	watchlist := readWatchlist(cmd, cfg)
+	if noAutoWatch, _ := cmd.Flags().GetBool(flagNoAutoWatch); !noAutoWatch {
+		if ownValidatorAddress := detectOwnValidatorAddress(rpcClient); len(ownValidatorAddress) > 0 {
+			printlnInfo("Watching validator of the node", ownValidatorAddress)
+			watchlist = append(watchlist, ownValidatorAddress)
+		}
+	}
 
 	if streamingMode { // light validators is required to start a streaming session
 		for len(lightValidators) < 1 {
@@ -165,48 +264,58 @@ func pvtopHandler(cmd *cobra.Command, args []string) {
 		}
 
 		if len(lightValidators) > coreconstants.MAX_VALIDATORS {
-			utils.PrintlnStdErr(fmt.Sprintf("ERR: too many validators %d/%d, cannot start streaming session\n", len(lightValidators), coreconstants.MAX_VALIDATORS))
-			aos.Exit(1)
+			printlnInfo(fmt.Sprintf("Validator set %d exceeds the streaming limit %d, streaming the watchlist & the top validators by voting power, the remaining are aggregated into an 'others' bucket", len(lightValidators), coreconstants.MAX_VALIDATORS))
 		}
 
-		fmt.Println("Initializing pre-vote streaming service...")
+		printlnInfo("Initializing pre-vote streaming service...")
 		if strings.EqualFold(mockStreamingServer, "mock") {
+			streamingServerUrl = ""
 			preVoteStreamingService = mpvssi.NewMockLocalPreVoteStreamingService(chainId, 2*time.Minute)
-		} else if strings.EqualFold(mockStreamingServer, "local") {
-			preVoteStreamingService = pvssi.NewPreVoteStreamingService(chainId, coreconstants.STREAMING_BASE_URL_LOCAL, codec)
 		} else {
-			preVoteStreamingService = pvssi.NewPreVoteStreamingService(chainId, coreconstants.STREAMING_BASE_URL, codec)
+			httpClient, errHttpClient := pvssi.NewHttpClient(streamingTransportOptions)
+			if errHttpClient != nil {
+				utils.PrintlnStdErr("ERR: failed to initialize HTTP transport for streaming")
+				utils.PrintlnStdErr(errHttpClient)
+				aos.Exit(1)
+			}
+			if streamingServerUrl != coreconstants.STREAMING_BASE_URL {
+				printlnInfo("Streaming server:", streamingServerUrl)
+			}
+			preVoteStreamingService = pvssi.NewPreVoteStreamingService(chainId, streamingServerUrl, codec, httpClient)
 		}
 
-		if resumeStreaming {
-			reader := bufio.NewReader(os.Stdin)
+		preVoteStreamingService.SetWatchlist(watchlist)
+
+		// session credentials are persisted, except for mock streaming server
+		var sessionStore *session_store.SessionStore
+		if len(streamingServerUrl) > 0 {
+			stateFilePath, errStateFilePath := session_store.DefaultStateFilePath()
+			if errStateFilePath != nil {
+				utils.PrintlnStdErr("WARN: streaming session credentials will not be saved")
+				utils.PrintlnStdErr(errStateFilePath)
+			} else {
+				sessionStore = session_store.NewSessionStore(stateFilePath)
+			}
+		}
 
-			sessionIdStr := readUntilValid(reader, "Enter session ID:", func(input string) error {
-				if len(input) < 1 {
-					return fmt.Errorf("must not be empty")
-				}
-				return coretypes.PreVoteStreamingSessionId(input).ValidateBasic()
-			}, "bad session ID, please check")
-			sessionKeyStr := readUntilValid(reader, "Enter session key:", func(input string) error {
-				if len(input) < 1 {
-					return fmt.Errorf("must not be empty")
-				}
-				return coretypes.PreVoteStreamingSessionKey(input).ValidateBasic()
-			}, "bad session ID, please check")
+		if resumeStreaming {
+			sessionId, sessionKey := getResumeStreamingSessionCredentials(chainId, streamingServerUrl, sessionIdOverride, sessionStore)
 
-			if !strings.HasPrefix(sessionIdStr, chainId) {
+			if !sessionId.ForChainId(chainId) {
 				utils.PrintlnStdErr("ERR: supplied session ID is not for chain " + chainId)
 				aos.Exit(1)
 			}
 
-			err = preVoteStreamingService.ResumeSession(coretypes.PreVoteStreamingSessionId(sessionIdStr), coretypes.PreVoteStreamingSessionKey(sessionKeyStr))
+			err = preVoteStreamingService.ResumeSession(sessionId, sessionKey)
 			if err != nil {
-				utils.PrintlnStdErr("ERR: failed to resume streaming session id " + sessionIdStr)
+				utils.PrintlnStdErr("ERR: failed to resume streaming session id " + string(sessionId))
 				utils.PrintlnStdErr(err)
 				aos.Exit(1)
 			}
+
+			printlnInfo("Resumed streaming session", sessionId)
 		} else {
-			fmt.Println("Registering streaming session...")
+			printlnInfo("Registering streaming session...")
 			var errOpenSession error
 			for {
 				preVoteStreamingShareViewUrl, errOpenSession = preVoteStreamingService.OpenSession(lightValidators)
@@ -218,45 +327,107 @@ func pvtopHandler(cmd *cobra.Command, args []string) {
 				time.Sleep(1 * time.Second)
 			}
 
-			fmt.Println("Streaming session registered successfully")
-			fmt.Println("use the following session ID and key to resume streaming the session if needed:")
+			printlnInfo("Streaming session registered successfully, codec version", preVoteStreamingService.CodecVersion())
+			printlnInfo("use the following session ID and key to resume streaming the session if needed:")
 			sessionId, sessionKey := preVoteStreamingService.ExposeSessionIdAndKey()
-			fmt.Println("Session ID :", sessionId)
-			fmt.Println("Session Key:", sessionKey)
+			printlnInfo("Session ID :", sessionId)
+			printlnInfo("Session Key:", sessionKey)
+
+			if sessionStore != nil {
+				errSave := saveStreamingSession(sessionStore, preVoteStreamingService, chainId, streamingServerUrl)
+				if errSave != nil {
+					utils.PrintlnStdErr("WARN: failed to save streaming session credentials")
+					utils.PrintlnStdErr(errSave)
+				} else {
+					printlnInfo(fmt.Sprintf("(saved, resume later using '--%s')", flagResumeStreaming))
+				}
+			}
 
-			fmt.Println("*** Share the following URL to others to join:")
-			fmt.Println(preVoteStreamingShareViewUrl)
+			printlnInfo("*** Share the following URL to others to join:")
+			printlnInfo(preVoteStreamingShareViewUrl)
 			if len(mockStreamingServer) < 1 {
 				const sleepTime = 20 * time.Second
-				fmt.Println("Start streaming next block pre-vote information in", sleepTime, "...")
+				printlnInfo("Start streaming next block pre-vote information in", sleepTime, "...")
 				time.Sleep(sleepTime)
 			}
 		}
+
+		preVoteStreamingService.UpdateLightValidators(lightValidators)
+		preVoteStreamingService.SetSessionRenewedHandler(func(shareViewUrl string, reason string) {
+			renewedShareViewUrl = shareViewUrl
+
+			// session key is never printed, since messages are mirrored into the log panel & log file
+			sessionId, _ := preVoteStreamingService.ExposeSessionIdAndKey()
+			utils.StdHelper.PrintlnInfoStdErr(fmt.Sprintf("Streaming session renewed (%s), new session ID: %s", reason, sessionId))
+			utils.StdHelper.PrintlnInfoStdErr("*** New URL to share: " + shareViewUrl)
+
+			if sessionStore != nil {
+				if errSave := saveStreamingSession(sessionStore, preVoteStreamingService, chainId, streamingServerUrl); errSave != nil {
+					utils.StdHelper.PrintlnWarn("failed to save renewed streaming session credentials, the new session can not be resumed", errSave)
+				} else {
+					utils.StdHelper.PrintlnInfoStdErr(fmt.Sprintf("Credentials of the new session are saved into %s, to be used for resuming", sessionStore.FilePath()))
+				}
+			}
+		})
 	}
 
 	isStoppedAcceptingNextBlockVotingInfo := false
 	renderVotingInfoChan := make(chan interface{}) // accept both voting info and error
-	var broadcastingPreVoteInfoChan chan interface{}
-	var broadcastingStatusChan chan string
+	broadcaster := newSinkBroadcaster()
 	if streamingMode {
-		broadcastingPreVoteInfoChan = make(chan interface{})
-		broadcastingStatusChan = make(chan string)
+		broadcaster.AddSink(streaming_sink_impl.NewStreamingSink(preVoteStreamingService), func(result broadcast_worker.Result) string {
+			return formatStreamingBroadcastResult(preVoteStreamingService, result, renewedShareViewUrl)
+		})
+	}
+	for _, sink := range readBroadcastSinks(cmd) {
+		printlnInfo("Broadcasting to", sink.Name())
+		broadcaster.AddSink(sink, nil)
+	}
+	if metricsExporter != nil {
+		broadcaster.SetResultObserver(func(sinkName string, result broadcast_worker.Result) {
+			observeBroadcastResult(metricsExporter, sinkName, result)
+		})
+	}
+	var alertWorker *broadcast_worker.Worker
+	if alertEngine := readAlertEngine(cmd, cfg, chainId, watchlist); alertEngine != nil {
+		printlnInfo("Alerting is enabled")
+		alertWorker = newAlertWorker(alertEngine)
+	}
+	var broadcastingStatusChan <-chan string
+	if broadcaster.HasSinks() {
+		broadcastingStatusChan = broadcaster.StatusChan()
 	}
 
 	utils.AppExitHelper.RegisterFuncUponAppExit(func() {
 		isStoppedAcceptingNextBlockVotingInfo = true
 		close(renderVotingInfoChan)
-		if broadcastingPreVoteInfoChan != nil {
-			close(broadcastingPreVoteInfoChan)
-		}
-		if broadcastingStatusChan != nil {
-			close(broadcastingStatusChan)
+		broadcaster.Shutdown()
+		if alertWorker != nil {
+			alertWorker.Stop()
 		}
 	})
 
-	go drawScreen(chainId, consensusVersion, moniker, renderVotingInfoChan, broadcastingStatusChan)
-	if streamingMode {
-		go broadcastPreVoteInfo(preVoteStreamingService, broadcastingPreVoteInfoChan, broadcastingStatusChan)
+	startRendering(outputMode, screenOptions{
+		chainId:          chainId,
+		consensusVersion: consensusVersion,
+		moniker:          moniker,
+		statusPanelTitle: " Broadcast Status ",
+
+		watchlist:                watchlist,
+		watchMissingPreVoteAfter: watchMissingPreVoteAfter,
+	}, renderVotingInfoChan, broadcastingStatusChan)
+	broadcaster.Run()
+	if alertWorker != nil {
+		go alertWorker.Run()
+	}
+
+	refreshedLightValidatorsChan := make(chan enginetypes.LightValidators)
+	go refreshLightValidatorsPeriodically(rpcClient, refreshedLightValidatorsChan)
+
+	var signedBlocksFetcher *signedBlocksWindowFetcher
+	if signedBlocksWindowSize > 0 {
+		signedBlocksFetcher = newSignedBlocksWindowFetcher(consensusService, signedBlocksWindowSize)
+		go signedBlocksFetcher.run()
 	}
 
 	refreshTicker := time.NewTicker(func() time.Duration {
@@ -272,13 +443,28 @@ func pvtopHandler(cmd *cobra.Command, args []string) {
 			break
 		}
 
+		select {
+		case refreshedLightValidators := <-refreshedLightValidatorsChan:
+			if streamingMode {
+				// streaming session will be re-registered if validator set changed
+				preVoteStreamingService.UpdateLightValidators(refreshedLightValidators)
+			}
+			lightValidators = refreshedLightValidators
+			recordLightValidators(recorder, lightValidators)
+		default:
+			break
+		}
+
 		if len(lightValidators) < 1 {
 			lightValidators, err = rpcClient.LightValidators()
 			if err != nil {
-				utils.StdHelper.PrintlnStdErr("ERR: failed to fetch light validators")
-				utils.StdHelper.PrintlnStdErr(err)
+				utils.StdHelper.PrintlnErr("failed to fetch light validators", err)
+				if alertWorker != nil {
+					alertWorker.Offer(errors.Wrap(err, "failed to fetch light validators"))
+				}
 				continue
 			}
+			recordLightValidators(recorder, lightValidators)
 		}
 
 		var nextBlockVotingInfo *enginetypes.NextBlockVotingInformation
@@ -287,8 +473,26 @@ func pvtopHandler(cmd *cobra.Command, args []string) {
 		nextBlockVotingInfo, err = consensusService.GetNextBlockVotingInformation(lightValidators)
 		if err != nil {
 			newUpdateContent = errors.Wrap(err, "failed to get next block voting information")
+
+			if metricsExporter != nil {
+				metricsExporter.ObserveFetchFailure()
+			}
+			if alertWorker != nil {
+				alertWorker.Offer(newUpdateContent)
+			}
 		} else {
+			if signedBlocksFetcher != nil {
+				nextBlockVotingInfo.SignedBlocksWindow = signedBlocksFetcher.offer(lightValidators, nextBlockVotingInfo)
+			}
+
 			newUpdateContent = nextBlockVotingInfo
+
+			if metricsExporter != nil {
+				metricsExporter.UpdateVotingInformation(nextBlockVotingInfo)
+			}
+			if alertWorker != nil {
+				alertWorker.Offer(nextBlockVotingInfo)
+			}
 		}
 
 		if newUpdateContent != nil {
@@ -296,32 +500,163 @@ func pvtopHandler(cmd *cobra.Command, args []string) {
 				continue
 			}
 
-			renderVotingInfoChan <- newUpdateContent
-			if streamingMode {
-				if !preVoteStreamingService.IsStopped() { // prevent memory stacking due to no consumer
-					broadcastingPreVoteInfoChan <- newUpdateContent
+			if recorder != nil {
+				if errRecord := recorder.RecordVotingInfo(newUpdateContent); errRecord != nil {
+					utils.StdHelper.PrintlnErr("failed to record voting information", errRecord)
 				}
 			}
+
+			renderVotingInfoChan <- newUpdateContent
+			// never blocks, outdated content which has not been broadcast yet will be dropped
+			broadcaster.Offer(newUpdateContent)
 		}
 	}
 }
 
-const terminalColumnsCount = 3
+// getResumeStreamingSessionCredentials returns the credentials of the streaming session to be resumed.
+// Session ID is taken from the override (flag or env) if provided,
+// otherwise the latest valid session of the chain and streaming server saved in the state file.
+// Session key is taken from env, otherwise the state file.
+// Fallback to ask from stdin when not found.
+func getResumeStreamingSessionCredentials(chainId, streamingServerUrl, sessionIdOverride string, sessionStore *session_store.SessionStore) (coretypes.PreVoteStreamingSessionId, coretypes.PreVoteStreamingSessionKey) {
+	now := time.Now().UTC()
+	sessionId := coretypes.PreVoteStreamingSessionId(strings.TrimSpace(sessionIdOverride))
+	sessionKey := coretypes.PreVoteStreamingSessionKey(strings.TrimSpace(os.Getenv(constants.ENV_STREAMING_SESSION_KEY)))
+
+	if sessionStore != nil && (len(sessionId) < 1 || len(sessionKey) < 1) {
+		var savedSession *session_store.StreamingSession
+		var err error
+		if len(sessionId) > 0 {
+			savedSession, err = sessionStore.Get(sessionId, now)
+		} else if len(sessionKey) < 1 {
+			savedSession, err = sessionStore.Latest(chainId, streamingServerUrl, now)
+		}
+
+		if err != nil {
+			utils.PrintlnStdErr("WARN: failed to read saved streaming sessions")
+			utils.PrintlnStdErr(err)
+		} else if savedSession != nil {
+			printlnInfo("Using saved streaming session, expires at", savedSession.ExpiresAt.Local().Format("2006-01-02 15:04:05"))
+			sessionId = savedSession.SessionId
+			sessionKey = savedSession.SessionKey
+		}
+	}
+
+	if len(sessionId) > 0 {
+		if err := sessionId.ValidateBasic(); err != nil {
+			utils.PrintlnStdErr("ERR: bad session ID")
+			utils.PrintlnStdErr(err)
+			aos.Exit(1)
+		}
+	}
+	if len(sessionKey) > 0 {
+		if err := sessionKey.ValidateBasic(); err != nil {
+			utils.PrintlnStdErr("ERR: bad session key")
+			utils.PrintlnStdErr(err)
+			aos.Exit(1)
+		}
+	}
+
+	if len(sessionId) > 0 && len(sessionKey) > 0 {
+		return sessionId, sessionKey
+	}
+
+	if !utils.IsTerminal(os.Stdin) {
+		utils.PrintlnStdErr(fmt.Sprintf("ERR: no saved streaming session found, provide via --%s (or env %s) and env %s", flagSessionId, constants.ENV_STREAMING_SESSION_ID, constants.ENV_STREAMING_SESSION_KEY))
+		aos.Exit(1)
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+
+	if len(sessionId) < 1 {
+		sessionId = coretypes.PreVoteStreamingSessionId(readUntilValid(reader, "Enter session ID:", func(input string) error {
+			if len(input) < 1 {
+				return fmt.Errorf("must not be empty")
+			}
+			return coretypes.PreVoteStreamingSessionId(input).ValidateBasic()
+		}, "bad session ID, please check"))
+	}
+	if len(sessionKey) < 1 {
+		sessionKey = coretypes.PreVoteStreamingSessionKey(readUntilValid(reader, "Enter session key:", func(input string) error {
+			if len(input) < 1 {
+				return fmt.Errorf("must not be empty")
+			}
+			return coretypes.PreVoteStreamingSessionKey(input).ValidateBasic()
+		}, "bad session key, please check"))
+	}
+
+	return sessionId, sessionKey
+}
+
+// recordLightValidators records the validator set if recording is enabled.
+func recordLightValidators(recorder *recording.Recorder, lightValidators enginetypes.LightValidators) {
+	if recorder == nil || len(lightValidators) < 1 {
+		return
+	}
+
+	if err := recorder.RecordLightValidators(lightValidators); err != nil {
+		utils.StdHelper.PrintlnErr("failed to record validators", err)
+	}
+}
+
+// refreshLightValidatorsPeriodically re-fetches light validators periodically and sends them to the given channel.
+func refreshLightValidatorsPeriodically(rpcClient rpc_client.RpcClient, refreshedLightValidatorsChan chan<- enginetypes.LightValidators) {
+	refreshTicker := time.NewTicker(lightValidatorsRefreshInterval)
+	defer refreshTicker.Stop()
+
+	for range refreshTicker.C {
+		lightValidators, err := rpcClient.LightValidators()
+		if err != nil {
+			utils.StdHelper.PrintlnErr("failed to refresh light validators", err)
+			continue
+		}
+
+		if len(lightValidators) < 1 {
+			continue
+		}
+
+		refreshedLightValidatorsChan <- lightValidators
+	}
+}
+
+// signedBlocksSparklineWidth is the number of characters used to render signed blocks sparkline of each validator.
+const signedBlocksSparklineWidth = 8
+
+// screenOptions holds the information to be rendered and customizations of the terminal UI.
+type screenOptions struct {
+	chainId          string
+	consensusVersion string
+	moniker          string
+
+	// statusPanelTitle is the title of the status panel, the panel is only rendered when status channel is provided.
+	statusPanelTitle string
+
+	// keyEventHandler is optional, to handle additional key events those are not handled by the terminal UI.
+	keyEventHandler func(eventId string)
+
+	// watchlist is the validators to be pinned to the top and highlighted.
+	watchlist enginetypes.Watchlist
+
+	// watchMissingPreVoteAfter is the duration into a round, after which the watched validators those have not pre-voted
+	// are alerted by terminal bell, zero to disable.
+	watchMissingPreVoteAfter time.Duration
+}
 
 // drawScreen render pre-vote information into screen.
-func drawScreen(chainId, consensusVersion, moniker string, votingInfoChan <-chan interface{}, broadcastingStatusChan <-chan string) {
+func drawScreen(opts screenOptions, votingInfoChan <-chan interface{}, broadcastingStatusChan <-chan string) {
 	defer utils.AppExitHelper.ExecuteFunctionsUponAppExit()
 
+	logsChan := utils.StdHelper.SubscribeLogs()
 	utils.StdHelper.EnableQueue()
 	if err := ui.Init(); err != nil {
 		//goland:noinspection SpellCheckingInspection
-		utils.StdHelper.PrintlnStdErr(fmt.Sprintf("failed to initialize termui: %v\n", err))
+		utils.StdHelper.PrintlnErr("failed to initialize termui", err)
 	}
 
 	pSummary := widgets.NewParagraph()
-	summaryTitle := fmt.Sprintf(" %s, tm v%s", chainId, consensusVersion)
-	if len(moniker) > 0 {
-		summaryTitle += fmt.Sprintf(", %s", moniker)
+	summaryTitle := fmt.Sprintf(" %s, tm v%s", opts.chainId, opts.consensusVersion)
+	if len(opts.moniker) > 0 {
+		summaryTitle += fmt.Sprintf(", %s", opts.moniker)
 	}
 	summaryTitle += " "
 	pSummary.Title = summaryTitle
@@ -330,16 +665,7 @@ func drawScreen(chainId, consensusVersion, moniker string, votingInfoChan <-chan
 	preCommitVotePctGauge := widgets.NewGauge()
 
 	pBroadcastStatus := widgets.NewParagraph()
-	pBroadcastStatus.Title = " Broadcast Status "
-
-	lists := make([]*widgets.List, terminalColumnsCount)
-	for i := range lists {
-		lists[i] = widgets.NewList()
-		lists[i].Border = false
-	}
-	grid := ui.NewGrid()
-	termWidth, termHeight := ui.TerminalDimensions()
-	grid.SetRect(0, 0, termWidth, termHeight)
+	pBroadcastStatus.Title = opts.statusPanelTitle
 
 	var gridHeader ui.GridItem
 	if broadcastingStatusChan != nil {
@@ -357,21 +683,214 @@ func drawScreen(chainId, consensusVersion, moniker string, votingInfoChan <-chan
 		)
 	}
 
-	grid.Set(
-		gridHeader,
-		ui.NewRow(0.9,
-			ui.NewCol(.96/terminalColumnsCount, lists[0]),
-			ui.NewCol(.96/terminalColumnsCount, lists[1]),
-			ui.NewCol(1.08/terminalColumnsCount, lists[2]),
-		),
-	)
-	ui.Render(grid)
+	pDetails := widgets.NewParagraph()
+
+	logs := &logPanel{}
+	lLogs := widgets.NewList()
+	lLogs.Title = " Logs [L] close "
+
+	history := newVotingHistory(chartWindows[len(chartWindows)-1])
+	chart := newVotingHistoryChart(history)
+
+	tl := timeline.NewTimeline(maxTimelineEvents)
+	lTimeline := widgets.NewList()
+	lTimelineStatus := "[e] close | [x] export"
+	// changedValidators is the validators those have any vote event in the latest refresh, to be highlighted.
+	changedValidators := make(map[string]bool)
+
+	var latestVotingInfo *enginetypes.NextBlockVotingInformation
+	var grid *ui.Grid
+	var lists []*widgets.List
+	var withSparkline bool
+	var showDetails bool
+	var showChart bool
+	var showTimeline bool
+	var showLogs bool
+
+	// renderTimeline renders the most recent events fit into the timeline panel, the latest at the bottom.
+	renderTimeline := func() {
+		events := tl.Events()
+		if visibleRowsCount := lTimeline.Inner.Dy(); visibleRowsCount >= 0 && len(events) > visibleRowsCount {
+			events = events[len(events)-visibleRowsCount:]
+		}
+
+		lTimeline.Rows = make([]string, len(events))
+		for i, event := range events {
+			lTimeline.Rows[i] = formatTimelineEvent(event)
+		}
+		lTimeline.Title = fmt.Sprintf(" Timeline %s ", lTimelineStatus)
+	}
+
+	// renderLogs renders the most recent messages fit into the log panel, the latest at the bottom.
+	renderLogs := func() {
+		lLogs.Rows = logs.rows(lLogs.Inner.Dy())
+	}
+
+	// setLayout re-creates the grid, with number of validator columns fit the terminal width.
+	setLayout := func(termWidth, termHeight int) {
+		columnsCount := getColumnsCount(termWidth, getRowWidth(withSparkline)+2 /*padding*/)
+
+		lists = make([]*widgets.List, columnsCount)
+		cols := make([]interface{}, columnsCount)
+		for i := range lists {
+			lists[i] = widgets.NewList()
+			lists[i].Border = false
+			cols[i] = ui.NewCol(1.0/float64(columnsCount), lists[i])
+		}
+
+		rows := []interface{}{gridHeader}
+		listsRowRatio := 0.9
+		if showChart && showTimeline {
+			rows = append(rows, ui.NewRow(0.25, ui.NewCol(0.5, chart), ui.NewCol(0.5, lTimeline)))
+		} else if showChart {
+			rows = append(rows, ui.NewRow(0.25, chart))
+		} else if showTimeline {
+			rows = append(rows, ui.NewRow(0.25, lTimeline))
+		}
+		if showChart || showTimeline {
+			listsRowRatio -= 0.25
+		}
+		if showDetails || showLogs {
+			listsRowRatio -= 0.3
+		}
+		rows = append(rows, ui.NewRow(listsRowRatio, cols...))
+		if showDetails && showLogs {
+			rows = append(rows, ui.NewRow(0.3, ui.NewCol(0.5, pDetails), ui.NewCol(0.5, lLogs)))
+		} else if showDetails {
+			rows = append(rows, ui.NewRow(0.3, pDetails))
+		} else if showLogs {
+			rows = append(rows, ui.NewRow(0.3, lLogs))
+		}
+
+		grid = ui.NewGrid()
+		grid.SetRect(0, 0, termWidth, termHeight)
+		grid.Set(rows...)
+		ui.Clear()
+		ui.Render(grid) // compute the size of the widgets
+
+		if showChart {
+			chart.update(capturedTimeOf(latestVotingInfo))
+		}
+		if showTimeline {
+			renderTimeline()
+		}
+		if showLogs {
+			renderLogs()
+		}
+	}
+	setLayout(ui.TerminalDimensions())
 
 	utils.AppExitHelper.RegisterFuncUponAppExit(func() {
 		ui.Clear()
 		ui.Close()
 	})
 
+	watchedMonitor := newWatchedMissingPreVoteMonitor(opts.watchlist, opts.watchMissingPreVoteAfter)
+
+	view := &tableView{}
+	scroll := &tableScroll{}
+	cursor := &tableCursor{}
+	// followCursor is set to scroll the table to make the cursor visible on the next rendering.
+	var followCursor bool
+
+	// renderRows renders the validator rows of the latest voting information, using the current view.
+	renderRows := func() {
+		if latestVotingInfo == nil {
+			return
+		}
+		votingInfo := latestVotingInfo
+
+		voteStates := pinWatchedValidators(view.apply(votingInfo.SortedValidatorVoteStates, votingInfo.StartTimeUTC), opts.watchlist)
+
+		batches, rowsCount := splitVotesIntoColumnsForRendering(voteStates, len(lists))
+		cursor.update(voteStates)
+		scroll.update(rowsCount, lists[0].Inner.Dy()-1 /*header*/)
+		if followCursor {
+			scroll.ensureVisible(cursor.index / len(lists))
+			followCursor = false
+		}
+		from, to := scroll.visibleRange()
+
+		for i := range lists {
+			lists[i].Title = ""
+			if len(voteStates) > 0 && i == cursor.index%len(lists) {
+				lists[i].SelectedRow = cursor.index/len(lists) - from + 1 /*header*/
+				lists[i].SelectedRowStyle = ui.NewStyle(ui.ColorClear, ui.ColorClear, ui.ModifierReverse)
+			} else {
+				lists[i].SelectedRow = 0
+				lists[i].SelectedRowStyle = lists[i].TextStyle
+			}
+			lists[i].Rows = make([]string, 1, to-from+1)
+			lists[i].Rows[0] = renderRowsHeader(votingInfo.SignedBlocksWindow != nil)
+
+			for j, voter := range batches[i] {
+				if j < from || j >= to {
+					continue
+				}
+
+				valMoniker := string(coreutils.TruncateStringUntilBufferLessThanXBytesOrFillWithSpaceSuffix(voter.Validator.Moniker, 15))
+				valMoniker = strings.TrimSpace(valMoniker)
+				var jailed, closeToJail bool
+				if signingInfo := voter.Validator.SigningInfo; signingInfo != nil {
+					jailed = signingInfo.Tombstoned || signingInfo.IsJailed(time.Now().UTC())
+					closeToJail = !jailed && signingInfo.IsCloseToDowntimeJail()
+				}
+				if valMonikerStyle := getMonikerStyle(jailed, closeToJail, opts.watchlist.Contains(voter.Validator)); len(valMonikerStyle) > 0 {
+					valMoniker = fmt.Sprintf("[%s](%s)", valMoniker, valMonikerStyle)
+				}
+
+				if votingInfo.SignedBlocksWindow != nil {
+					valMoniker = renderSignedBlocksSparkline(votingInfo.SignedBlocksWindow.GetSignedBlocks(voter.Validator.Address), signedBlocksSparklineWidth) + " " + valMoniker
+				}
+
+				lists[i].Rows = append(lists[i].Rows, fmt.Sprintf(
+					"%s %s %s %-3d %s%% %-15s ",
+					renderVote(voter.PreVoted, voter.VotedZeroes),
+					renderVote(voter.PreCommitVoted, voter.PreCommitVotedZeroes()),
+					func() string {
+						blockHash := "----"
+						if len(voter.VotingBlockHash) >= 4 {
+							blockHash = voter.VotingBlockHash[:4]
+						}
+						if changedValidators[voter.Validator.Address] && len(theme.changed) > 0 {
+							blockHash = fmt.Sprintf("[%s](%s)", blockHash, theme.changed)
+						}
+						return blockHash
+					}(),
+					voter.Validator.Index+1,
+					func() string {
+						str := fmt.Sprintf("%-.2f", voter.Validator.VotingPowerDisplayPercent)
+						if strings.Index(str, ".") == 1 { // VP percent < 10
+							str = "0" + str
+						}
+						return str
+					}(),
+					valMoniker,
+				))
+			}
+		}
+
+		lists[0].Title = view.describe(len(voteStates), len(votingInfo.SortedValidatorVoteStates))
+		if position := scroll.describe(); len(position) > 0 {
+			if len(lists) > 1 {
+				lists[len(lists)-1].Title = position
+			} else {
+				lists[0].Title += position
+			}
+		}
+
+		if showDetails {
+			if len(voteStates) > 0 {
+				selected := voteStates[cursor.index]
+				pDetails.Title = fmt.Sprintf(" %s [Enter] close ", selected.Validator.Moniker)
+				pDetails.Text = formatValidatorDetails(selected, votingInfo, time.Now().UTC())
+			} else {
+				pDetails.Title = " [Enter] close "
+				pDetails.Text = "no validator selected"
+			}
+		}
+	}
+
 	refresh := false
 	tick := time.NewTicker(100 * time.Millisecond)
 	uiEvents := ui.PollEvents()
@@ -387,32 +906,96 @@ func drawScreen(chainId, consensusVersion, moniker string, votingInfoChan <-chan
 
 			break
 		case e := <-uiEvents:
+			if view.handleKeyEvent(e.ID) {
+				renderRows()
+				refresh = true
+				break
+			}
+			if cursor.handleKeyEvent(e.ID, len(lists)) {
+				followCursor = true
+				renderRows()
+				refresh = true
+				break
+			}
+			if chart.handleKeyEvent(e.ID) {
+				chart.update(capturedTimeOf(latestVotingInfo))
+				refresh = true
+				break
+			}
+			if scroll.handleKeyEvent(e.ID) {
+				from, to := scroll.visibleRange()
+				cursor.moveIntoRows(from, to, len(lists))
+				renderRows()
+				refresh = true
+				break
+			}
+
 			switch e.ID {
 			case "q", "<C-c>":
 
 				aos.Exit(0)
-			case "j", "<Down>":
-				for _, list := range lists {
-					if len(list.Rows) > 0 {
-						list.ScrollBottom()
-					}
+			case "<Enter>", "<Escape>":
+				if e.ID == "<Escape>" && !showDetails {
+					break
+				}
+				showDetails = !showDetails
+				setLayout(ui.TerminalDimensions())
+				followCursor = true
+				renderRows()
+				refresh = true
+
+				break
+			case "e":
+				showTimeline = !showTimeline
+				setLayout(ui.TerminalDimensions())
+				followCursor = true
+				renderRows()
+				refresh = true
+
+				break
+			case "x":
+				if filePath, err := exportTimeline(tl, opts.chainId, time.Now()); err != nil {
+					lTimelineStatus = fmt.Sprintf("[e] close | export failed: %s", err)
+				} else {
+					lTimelineStatus = fmt.Sprintf("[e] close | exported to %s", filePath)
 				}
+				renderTimeline()
+				refresh = true
 
 				break
-			case "k", "<Up>":
-				for _, list := range lists {
-					if len(list.Rows) > 0 {
-						list.ScrollTop()
-					}
+			case "L":
+				showLogs = !showLogs
+				if showLogs {
+					logs.markSeen()
+					pSummary.Title = summaryTitle
 				}
+				setLayout(ui.TerminalDimensions())
+				followCursor = true
+				renderRows()
+				refresh = true
+
+				break
+			case "t":
+				showChart = !showChart
+				setLayout(ui.TerminalDimensions())
+				followCursor = true
+				renderRows()
+				refresh = true
 
 				break
 			case "<Resize>":
 				payload := e.Payload.(ui.Resize)
-				grid.SetRect(0, 0, payload.Width, payload.Height)
-				ui.Clear()
+				setLayout(payload.Width, payload.Height)
+				followCursor = true
+				renderRows()
 				ui.Render(grid)
 
+				break
+			default:
+				if opts.keyEventHandler != nil {
+					opts.keyEventHandler(e.ID)
+				}
+
 				break
 			}
 
@@ -443,60 +1026,40 @@ func drawScreen(chainId, consensusVersion, moniker string, votingInfoChan <-chan
 				votingInfo.PreCommitPercent,
 				duration,
 			)
+			if jailWarning := getDowntimeJailWarning(votingInfo.SortedValidatorVoteStates); len(jailWarning) > 0 {
+				pSummary.Text += "\n" + jailWarning
+			}
+			if watchedWarning, shouldAlert := watchedMonitor.check(votingInfo, time.Now()); len(watchedWarning) > 0 {
+				pSummary.Text += "\n" + watchedWarning
+				if shouldAlert {
+					_, _ = os.Stdout.WriteString("\a") // terminal bell
+				}
+			}
+
+			latestVotingInfo = votingInfo
+			history.add(votingInfo, capturedTimeOf(votingInfo))
+			changedValidators = getChangedValidators(tl.Update(votingInfo, time.Now()))
+			if showTimeline {
+				renderTimeline()
+			}
+			if showChart {
+				chart.update(capturedTimeOf(latestVotingInfo))
+			}
+			if hasSparkline := votingInfo.SignedBlocksWindow != nil; hasSparkline != withSparkline {
+				withSparkline = hasSparkline
+				setLayout(ui.TerminalDimensions())
+			}
+			renderRows()
 
-			batches, rowsCount := splitVotesIntoColumnsForRendering(votingInfo.SortedValidatorVoteStates)
 			totalVoteCount := len(votingInfo.SortedValidatorVoteStates)
 			preVotedCount := totalVoteCount
 			preCommitVotedCount := totalVoteCount
-			for i := 0; i < terminalColumnsCount; i++ {
-				lists[i].Rows = make([]string, rowsCount+1)
-
-				lists[i].Rows[0] = fmt.Sprintf("%-3s %-3s %-4s %-3s %-6s %-15s ", "PV", "PC", "Hash", "Ord", "VPwr", "Moniker")
-
-				for j, voter := range batches[i] {
-					rowIndex := j + 1
-
-					var preVote, preCommitVote string
-
-					if voter.VotedZeroes {
-						preVote = "🤷"
-					} else if voter.PreVoted {
-						preVote = "✅"
-					} else {
-						preVote = "❌"
-						preVotedCount--
-					}
-					if voter.PreCommitVoted {
-						preCommitVote = "✅"
-					} else {
-						preCommitVote = "❌"
-						preCommitVotedCount--
-					}
-
-					valMoniker := string(coreutils.TruncateStringUntilBufferLessThanXBytesOrFillWithSpaceSuffix(voter.Validator.Moniker, 15))
-					valMoniker = strings.TrimSpace(valMoniker)
-
-					lists[i].Rows[rowIndex] = fmt.Sprintf(
-						"%-2s %-2s %s %-3d %s%% %-15s ",
-						preVote,
-						preCommitVote,
-						func() string {
-							if len(voter.VotingBlockHash) >= 4 {
-								return voter.VotingBlockHash[:4]
-							} else {
-								return "----"
-							}
-						}(),
-						voter.Validator.Index+1,
-						func() string {
-							str := fmt.Sprintf("%-.2f", voter.Validator.VotingPowerDisplayPercent)
-							if strings.Index(str, ".") == 1 { // VP percent < 10
-								str = "0" + str
-							}
-							return str
-						}(),
-						valMoniker,
-					)
+			for _, voteState := range votingInfo.SortedValidatorVoteStates {
+				if !voteState.PreVoted {
+					preVotedCount--
+				}
+				if !voteState.PreCommitVoted {
+					preCommitVotedCount--
 				}
 			}
 
@@ -505,6 +1068,17 @@ func drawScreen(chainId, consensusVersion, moniker string, votingInfoChan <-chan
 			preCommitVotePctGauge.Title = fmt.Sprintf(" Pre-commit: %d/%d ", preCommitVotedCount, totalVoteCount)
 			preCommitVotePctGauge.Percent = int(votingInfo.PreCommitPercent)
 
+			break
+		case entry := <-logsChan:
+			refresh = true
+
+			logs.add(entry, showLogs)
+			if showLogs {
+				renderLogs()
+			} else {
+				pSummary.Title = summaryTitle + logs.describeUnseen()
+			}
+
 			break
 		case broadcastStatus := <-broadcastingStatusChan:
 			refresh = true
@@ -516,61 +1090,76 @@ func drawScreen(chainId, consensusVersion, moniker string, votingInfoChan <-chan
 	}
 }
 
-func broadcastPreVoteInfo(pvs pvss.PreVoteStreamingService, votingInfoChan <-chan interface{}, broadcastingStatusChan chan<- string) {
-	defer utils.AppExitHelper.ExecuteFunctionsUponAppExit()
-
-	for {
-		select {
-		case vi := <-votingInfoChan:
-			if vi == nil {
-				// TODO: investigate why this happens while channel is not closed
-				// utils.TbpMessages.AddMessage("ERR: un-expected nil voting info", true)
-				continue
-			}
-
-			if _, ok := vi.(error); ok {
-				broadcastingStatusChan <- "💢 broadcast has been paused temporary due to fetching issue"
-				continue
-			}
+// saveStreamingSession persists credentials of the active streaming session, so it can be resumed later.
+func saveStreamingSession(sessionStore *session_store.SessionStore, pvs pvss.PreVoteStreamingService, chainId, streamingServerUrl string) error {
+	sessionId, sessionKey := pvs.ExposeSessionIdAndKey()
+	now := time.Now().UTC()
+	return sessionStore.Save(session_store.StreamingSession{
+		SessionId:  sessionId,
+		SessionKey: sessionKey,
+		ChainId:    chainId,
+		ServerUrl:  streamingServerUrl,
+		CreatedAt:  now,
+		ExpiresAt:  now.Add(streamingSessionDuration),
+
+		CodecVersion: string(pvs.CodecVersion()),
+	}, now)
+}
 
-			votingInfo := vi.(*enginetypes.NextBlockVotingInformation)
+// formatStreamingBroadcastResult formats the result of a broadcast attempt of the streaming sink, to be displayed in the status panel.
+func formatStreamingBroadcastResult(pvs pvss.PreVoteStreamingService, result broadcast_worker.Result, renewedShareViewUrl string) string {
+	var status string
 
-			err, shouldStop := pvs.BroadcastPreVote(votingInfo)
-			if shouldStop {
-				if err == nil {
-					broadcastingStatusChan <- "🔴 Broadcasting stopped"
-					utils.StdHelper.PrintlnStdErr("ERR: broadcasting stopped")
-				} else {
-					broadcastingStatusChan <- fmt.Sprintf("🔴 Broadcasting stopped: %s", err)
-					utils.StdHelper.PrintlnStdErr("ERR: broadcasting stopped, reason: " + err.Error())
-				}
-				pvs.Stop()
-				return
-			}
+	if result.FetchingIssue {
+		status = symbols.paused + "broadcast has been paused temporary due to fetching issue"
+	} else if result.Stopped {
+		if result.Err == nil {
+			status = symbols.stopped + "Broadcasting stopped"
+		} else {
+			status = fmt.Sprintf("%sBroadcasting stopped: %s", symbols.stopped, result.Err)
+		}
+	} else if result.Err != nil {
+		errMsg := result.Err.Error()
+		if errors.Is(result.Err, pvss.ErrSnapshotUnchanged) || strings.Contains(errMsg, "upstream status has not changed") {
+			status = symbols.ok + "Pre-Vote streaming in progress, no change"
+		} else if strings.Contains(errMsg, "connection refused") {
+			status = symbols.err + "Broadcasting err: upstream server unavailable"
+		} else {
+			status = fmt.Sprintf("%sBroadcasting err: %s", symbols.err, result.Err)
+		}
+	} else {
+		status = symbols.ok + "Pre-Vote streaming in progress, updated"
+	}
 
-			if err != nil {
-				errMsg := err.Error()
-				if strings.Contains(errMsg, "upstream status has not changed") {
-					broadcastingStatusChan <- "🟢 Pre-Vote streaming in progress, no change"
-				} else if strings.Contains(errMsg, "connection refused") {
-					broadcastingStatusChan <- "❗Broadcasting err: upstream server unavailable"
-				} else {
-					broadcastingStatusChan <- fmt.Sprintf("❗Broadcasting err: %s", err)
-				}
-				continue
-			}
+	status += "\n" + formatBroadcastStats(result.Stats) + ", codec " + string(pvs.CodecVersion())
+	if len(renewedShareViewUrl) > 0 {
+		status += "\nNew URL: " + renewedShareViewUrl
+	}
 
-			broadcastingStatusChan <- "🟢 Pre-Vote streaming in progress, updated"
+	return status
+}
 
-			break
+// formatBroadcastStats returns a single line summary of the broadcast statistic.
+func formatBroadcastStats(stats broadcast_worker.Stats) string {
+	line := fmt.Sprintf(
+		"sent %d, skipped %d, dropped %d, failed %d, latency %s (avg %s)",
+		stats.Sent, stats.Skipped, stats.Dropped, stats.Failed,
+		stats.LastLatency.Round(time.Millisecond), stats.AverageLatency.Round(time.Millisecond),
+	)
+	if !stats.BackoffUntil.IsZero() {
+		if retryIn := time.Until(stats.BackoffUntil); retryIn > 0 {
+			line += fmt.Sprintf(", retry in %s", retryIn.Round(100*time.Millisecond))
 		}
 	}
+	return line
 }
 
-func splitVotesIntoColumnsForRendering(votes []enginetypes.ValidatorVoteState) (batches [][]enginetypes.ValidatorVoteState, rowsCount int) {
-	rowsCount = int(math.Ceil(float64(len(votes)) / float64(terminalColumnsCount)))
+// splitVotesIntoColumnsForRendering distributes the votes into columns, row by row,
+// so validators next to each other in the order are rendered in the same row.
+func splitVotesIntoColumnsForRendering(votes []enginetypes.ValidatorVoteState, columnsCount int) (batches [][]enginetypes.ValidatorVoteState, rowsCount int) {
+	rowsCount = int(math.Ceil(float64(len(votes)) / float64(columnsCount)))
 
-	batches = make([][]enginetypes.ValidatorVoteState, terminalColumnsCount)
+	batches = make([][]enginetypes.ValidatorVoteState, columnsCount)
 
 	colIndex := 0
 
@@ -578,7 +1167,7 @@ func splitVotesIntoColumnsForRendering(votes []enginetypes.ValidatorVoteState) (
 		batches[colIndex] = append(batches[colIndex], votes[i])
 
 		colIndex++
-		if colIndex >= terminalColumnsCount {
+		if colIndex >= columnsCount {
 			colIndex = 0
 		}
 	}
@@ -586,6 +1175,85 @@ func splitVotesIntoColumnsForRendering(votes []enginetypes.ValidatorVoteState) (
 	return
 }
 
+// renderRowsHeader returns the header row of each validator column.
+func renderRowsHeader(withSparkline bool) string {
+	if withSparkline {
+		return fmt.Sprintf("%-3s %-3s %-4s %-3s %-6s %-*s %-15s ", "PV", "PC", "Hash", "Ord", "VPwr", signedBlocksSparklineWidth, "Signed", "Moniker")
+	}
+	return fmt.Sprintf("%-3s %-3s %-4s %-3s %-6s %-15s ", "PV", "PC", "Hash", "Ord", "VPwr", "Moniker")
+}
+
+// getRowWidth returns the width of each validator row on terminal UI.
+func getRowWidth(withSparkline bool) int {
+	return len(renderRowsHeader(withSparkline))
+}
+
+// getDowntimeJailWarning returns a warning message if any validator is close to the downtime jail threshold,
+// or is jailed/tombstoned. Returns empty if nothing to warn.
+func getDowntimeJailWarning(voteStates []enginetypes.ValidatorVoteState) string {
+	var closeToJailCount, jailedCount int
+	for _, voteState := range voteStates {
+		signingInfo := voteState.Validator.SigningInfo
+		if signingInfo == nil {
+			continue
+		}
+
+		if signingInfo.Tombstoned || signingInfo.IsJailed(time.Now().UTC()) {
+			jailedCount++
+		} else if signingInfo.IsCloseToDowntimeJail() {
+			closeToJailCount++
+		}
+	}
+
+	var warnings []string
+	if closeToJailCount > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d close to downtime jail", closeToJailCount))
+	}
+	if jailedCount > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d jailed/tombstoned", jailedCount))
+	}
+	if len(warnings) < 1 {
+		return ""
+	}
+
+	return symbols.warning + strings.Join(warnings, ", ")
+}
+
+// renderSignedBlocksSparkline renders the signing status (ordered ascending by height) into a sparkline of the given width.
+// When there are more blocks than width, each character represents the signing rate of a group of consecutive blocks.
+// The most recent blocks are on the right side.
+func renderSignedBlocksSparkline(signedBlocks []bool, width int) string {
+	if len(signedBlocks) <= width {
+		sb := strings.Builder{}
+		sb.WriteString(strings.Repeat(" ", width-len(signedBlocks)))
+		for _, signed := range signedBlocks {
+			if signed {
+				sb.WriteRune(symbols.sparklineLevels[len(symbols.sparklineLevels)-1])
+			} else {
+				sb.WriteRune(symbols.sparklineLevels[0])
+			}
+		}
+		return sb.String()
+	}
+
+	sparkline := make([]rune, width)
+	for i := 0; i < width; i++ {
+		from := i * len(signedBlocks) / width
+		to := (i + 1) * len(signedBlocks) / width
+
+		var signedCount int
+		for _, signed := range signedBlocks[from:to] {
+			if signed {
+				signedCount++
+			}
+		}
+
+		level := int(math.Round(float64(signedCount) / float64(to-from) * float64(len(symbols.sparklineLevels)-1)))
+		sparkline[i] = symbols.sparklineLevels[level]
+	}
+	return string(sparkline)
+}
+
 // readPvTopArg reads the argument at the given index, and returns an error if it is missing but required.
 // If the argument is a number, it is assumed to be a port and the default host is localhost will be used.
 func readPvTopArg(args []string, index int, optional bool) (arg string, err error) {
@@ -608,15 +1276,14 @@ func readPvTopArg(args []string, index int, optional bool) (arg string, err erro
 
 func readUntilValid(reader *bufio.Reader, question string, validateFn func(t string) error, malformedErrMsg string) string {
 	for {
-		fmt.Println(question)
+		printlnInfo(question)
 		line, _ := reader.ReadString('\n')
 		line = strings.TrimSpace(line)
 
 		err := validateFn(line)
 		if err != nil {
-			utils.StdHelper.PrintlnStdErr("ERR: " + malformedErrMsg)
-			utils.StdHelper.PrintlnStdErr(err)
-			fmt.Println("----")
+			utils.StdHelper.PrintlnErr(malformedErrMsg, err)
+			printlnInfo("----")
 			continue
 		}
 
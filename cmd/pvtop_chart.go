@@ -0,0 +1,208 @@
+package cmd
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"fmt"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	ui "github.com/gizak/termui/v3"
+	"github.com/gizak/termui/v3/widgets"
+	"image"
+	"strings"
+	"time"
+)
+
+// chartWindows is the selectable time windows of the voting history chart, cycled by 'w'.
+var chartWindows = []time.Duration{time.Minute, 5 * time.Minute, 15 * time.Minute, 30 * time.Minute}
+
+// defaultChartWindowIndex is the index of the default time window in chartWindows.
+const defaultChartWindowIndex = 1
+
+// votingSample is the voting information at a refresh.
+type votingSample struct {
+	time             time.Time
+	height           int64
+	round            int
+	step             int
+	preVotePercent   float64
+	preCommitPercent float64
+}
+
+// votingHistory is the time series of pre-vote & pre-commit percent, height and round, sampled per refresh.
+type votingHistory struct {
+	samples []votingSample
+
+	// retention is the duration samples are kept.
+	retention time.Duration
+}
+
+func newVotingHistory(retention time.Duration) *votingHistory {
+	return &votingHistory{
+		retention: retention,
+	}
+}
+
+// add appends a sample of the voting information captured at the given time, then drops the samples older than the retention.
+// The sample is skipped if nothing changed since the latest sample, eg: the same snapshot is re-rendered.
+func (h *votingHistory) add(votingInfo *enginetypes.NextBlockVotingInformation, now time.Time) {
+	height, round, step, err := enginetypes.ParseHeightRoundStep(votingInfo.HeightRoundStep)
+	if err != nil {
+		return
+	}
+
+	sample := votingSample{
+		time:             now,
+		height:           height,
+		round:            round,
+		step:             step,
+		preVotePercent:   votingInfo.PreVotePercent,
+		preCommitPercent: votingInfo.PreCommitPercent,
+	}
+	if latest, found := h.latest(); found {
+		latest.time = sample.time
+		if latest == sample {
+			return
+		}
+	}
+	h.samples = append(h.samples, sample)
+
+	var expired int
+	for expired < len(h.samples) && now.Sub(h.samples[expired].time) > h.retention {
+		expired++
+	}
+	if expired > 0 {
+		h.samples = append(h.samples[:0], h.samples[expired:]...)
+	}
+}
+
+// series resamples the history within the time window ending at now into the given number of columns,
+// each column takes the latest sample at the end of the column, the columns before the first sample are omitted.
+//
+// Markers are indexed by column: "R<round>" when the round changed, "|" when the height changed.
+func (h *votingHistory) series(now time.Time, window time.Duration, columnsCount int) (preVotes, preCommits []float64, markers map[int]string) {
+	markers = make(map[int]string)
+	if columnsCount < 1 || window <= 0 {
+		return
+	}
+
+	start := now.Add(-window)
+	var prev *votingSample
+	next := 0
+	for i := 0; i < columnsCount; i++ {
+		columnEnd := start.Add(window * time.Duration(i+1) / time.Duration(columnsCount))
+
+		var sample *votingSample
+		for next < len(h.samples) && !h.samples[next].time.After(columnEnd) {
+			sample = &h.samples[next]
+			next++
+		}
+		if sample == nil {
+			if prev == nil {
+				continue // not started
+			}
+			sample = prev // no refresh within the column
+		}
+
+		column := len(preVotes)
+		if prev != nil && sample != prev {
+			if sample.height != prev.height {
+				markers[column] = "|"
+			} else if sample.round != prev.round {
+				markers[column] = fmt.Sprintf("R%d", sample.round)
+			}
+		}
+
+		preVotes = append(preVotes, sample.preVotePercent)
+		preCommits = append(preCommits, sample.preCommitPercent)
+		prev = sample
+	}
+
+	return
+}
+
+// latest returns the latest sample, false if no sample.
+func (h *votingHistory) latest() (votingSample, bool) {
+	if len(h.samples) < 1 {
+		return votingSample{}, false
+	}
+	return h.samples[len(h.samples)-1], true
+}
+
+// capturedTimeOf returns the time the voting information was captured, so the history follows the recorded time when replaying.
+// Fallback to the wall clock if unknown.
+func capturedTimeOf(votingInfo *enginetypes.NextBlockVotingInformation) time.Time {
+	if votingInfo == nil || votingInfo.CapturedAtUTC.IsZero() {
+		return time.Now()
+	}
+	return votingInfo.CapturedAtUTC
+}
+
+// votingHistoryChart renders the pre-vote & pre-commit percent history as line chart,
+// with markers of the height & round changes at the top row.
+type votingHistoryChart struct {
+	*widgets.Plot
+
+	history     *votingHistory
+	windowIndex int
+	markers     map[int]string
+}
+
+func newVotingHistoryChart(history *votingHistory) *votingHistoryChart {
+	plot := widgets.NewPlot()
+	plot.ShowAxes = false
+	plot.MaxVal = 100
+
+	return &votingHistoryChart{
+		Plot:        plot,
+		history:     history,
+		windowIndex: defaultChartWindowIndex,
+	}
+}
+
+// handleKeyEvent handles the key event, returns true if the event is consumed.
+func (c *votingHistoryChart) handleKeyEvent(eventId string) bool {
+	if eventId != "w" {
+		return false
+	}
+
+	c.windowIndex = (c.windowIndex + 1) % len(chartWindows)
+	return true
+}
+
+func (c *votingHistoryChart) window() time.Duration {
+	return chartWindows[c.windowIndex]
+}
+
+// update updates the chart data using the history, the chart must be rendered once before so the size is known.
+func (c *votingHistoryChart) update(now time.Time) {
+	if symbols.chartDot != 0 {
+		c.Marker = widgets.MarkerDot
+		c.DotMarkerRune = symbols.chartDot
+	}
+	c.LineColors = []ui.Color{theme.chartPreVote, theme.chartPreCommit}
+
+	preVotes, preCommits, markers := c.history.series(now, c.window(), c.Inner.Dx())
+	c.Data = [][]float64{preVotes, preCommits}
+	c.markers = markers
+
+	c.Title = fmt.Sprintf(" Pre-vote/pre-commit %%, last %s [w] ", strings.TrimSuffix(c.window().String(), "0s"))
+	if sample, found := c.history.latest(); found {
+		c.Title += fmt.Sprintf("| v: %.0f%% c: %.0f%% round %d ", sample.preVotePercent, sample.preCommitPercent, sample.round)
+	}
+}
+
+func (c *votingHistoryChart) Draw(buf *ui.Buffer) {
+	if len(c.Data) < 1 || len(c.Data[0]) < 2 { // line chart requires at least 2 points
+		c.Block.Draw(buf)
+		return
+	}
+
+	// reserve the top row for markers
+	if dy := c.Inner.Dy(); dy > 2 {
+		c.MaxVal = 100 * float64(dy-1) / float64(dy-2)
+	}
+	c.Plot.Draw(buf)
+
+	for column, marker := range c.markers {
+		buf.SetString(marker, ui.NewStyle(ui.ColorWhite), image.Pt(c.Inner.Min.X+column, c.Inner.Min.Y))
+	}
+}
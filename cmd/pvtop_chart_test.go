@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func Test_votingHistory(t *testing.T) {
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	history := newVotingHistory(10 * time.Second)
+	add := func(second int, heightRoundStep string, preVotePercent float64) {
+		history.add(&enginetypes.NextBlockVotingInformation{
+			HeightRoundStep:  heightRoundStep,
+			PreVotePercent:   preVotePercent,
+			PreCommitPercent: preVotePercent / 2,
+		}, startTime.Add(time.Duration(second)*time.Second))
+	}
+
+	add(0, "100/0/1", 10)
+	add(1, "100/0/6", 20)
+	add(2, "bad", 99) // ignored
+	add(3, "100/1/1", 30)
+	add(4, "101/0/1", 40)
+	add(7, "101/0/6", 70)
+	add(7, "101/0/6", 70) // re-rendered
+	add(8, "101/0/6", 70) // unchanged
+	require.Len(t, history.samples, 5, "unchanged samples must be skipped")
+
+	// 1 column per second, columns before the first sample are omitted
+	preVotes, preCommits, markers := history.series(startTime.Add(7*time.Second), 10*time.Second, 10)
+	require.Equal(t, []float64{10, 20, 20, 30, 40, 40, 40, 70}, preVotes)
+	require.Equal(t, []float64{5, 10, 10, 15, 20, 20, 20, 35}, preCommits)
+	require.Equal(t, map[int]string{3: "R1", 4: "|"}, markers)
+
+	// 2 seconds per column, takes the latest sample of each column
+	preVotes, _, markers = history.series(startTime.Add(7*time.Second), 10*time.Second, 5)
+	require.Equal(t, []float64{20, 30, 40, 70}, preVotes)
+	require.Equal(t, map[int]string{1: "R1", 2: "|"}, markers)
+
+	// samples older than the retention are dropped
+	add(12, "102/0/1", 80)
+	require.Len(t, history.samples, 4)
+	sample, found := history.latest()
+	require.True(t, found)
+	require.Equal(t, int64(102), sample.height)
+
+	preVotes, _, _ = history.series(startTime.Add(12*time.Second), 10*time.Second, 0)
+	require.Empty(t, preVotes)
+}
+
+func Test_capturedTimeOf(t *testing.T) {
+	capturedAt := time.Date(2023, 12, 25, 3, 0, 0, 0, time.UTC)
+	require.Equal(t, capturedAt, capturedTimeOf(&enginetypes.NextBlockVotingInformation{CapturedAtUTC: capturedAt}))
+	require.WithinDuration(t, time.Now(), capturedTimeOf(&enginetypes.NextBlockVotingInformation{}), time.Minute, "fallback to wall clock")
+	require.WithinDuration(t, time.Now(), capturedTimeOf(nil), time.Minute)
+}
+
+func Test_votingHistoryChart_handleKeyEvent(t *testing.T) {
+	chart := newVotingHistoryChart(newVotingHistory(time.Minute))
+	require.Equal(t, 5*time.Minute, chart.window())
+
+	require.True(t, chart.handleKeyEvent("w"))
+	require.Equal(t, 15*time.Minute, chart.window())
+	require.True(t, chart.handleKeyEvent("w"))
+	require.True(t, chart.handleKeyEvent("w"))
+	require.Equal(t, time.Minute, chart.window())
+
+	require.False(t, chart.handleKeyEvent("s"))
+}
@@ -0,0 +1,169 @@
+package cmd
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"fmt"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"strings"
+	"time"
+)
+
+// detailsSparklineWidth is the maximum number of characters used to render signed blocks sparkline in the detail pane.
+const detailsSparklineWidth = 50
+
+// tableCursor is the selected validator on terminal UI.
+//
+// Validators are laid out row by row, so moving up/down jumps over the number of columns.
+//
+// Key bindings: 'j'/'Down' and 'k'/'Up' move to the validator below/above, 'l'/'Tab' and 'h' move to the next/previous validator.
+type tableCursor struct {
+	// index is the index of the selected validator within the rendered vote states.
+	index int
+
+	// address is the consensus address of the selected validator, used to keep the selection across refreshes,
+	// when the validator is moved by sorting or new validator set.
+	address string
+
+	addresses []string
+}
+
+// update updates the rendered vote states, then moves the cursor to the previously selected validator if it is still rendered.
+func (c *tableCursor) update(voteStates []enginetypes.ValidatorVoteState) {
+	c.addresses = make([]string, len(voteStates))
+	for i, voteState := range voteStates {
+		c.addresses[i] = voteState.Validator.Address
+		if voteState.Validator.Address == c.address {
+			c.index = i
+		}
+	}
+	c.moveTo(c.index)
+}
+
+// handleKeyEvent handles the key event, returns true if the event is consumed.
+func (c *tableCursor) handleKeyEvent(eventId string, columnsCount int) bool {
+	switch eventId {
+	case "j", "<Down>":
+		if c.index+columnsCount < len(c.addresses) {
+			c.moveTo(c.index + columnsCount)
+		}
+	case "k", "<Up>":
+		if c.index-columnsCount >= 0 {
+			c.moveTo(c.index - columnsCount)
+		}
+	case "l", "<Tab>":
+		c.moveTo(c.index + 1)
+	case "h":
+		c.moveTo(c.index - 1)
+	default:
+		return false
+	}
+
+	return true
+}
+
+// moveIntoRows moves the cursor into the given range of rows [fromRow, toRow), keeping the same column.
+// Used to keep the cursor visible after scrolling.
+func (c *tableCursor) moveIntoRows(fromRow, toRow, columnsCount int) {
+	if toRow <= fromRow {
+		return
+	}
+
+	row, column := c.index/columnsCount, c.index%columnsCount
+	if row < fromRow {
+		row = fromRow
+	} else if row >= toRow {
+		row = toRow - 1
+	}
+
+	index := row*columnsCount + column
+	for index >= len(c.addresses) && index >= columnsCount { // the last row is not full
+		index -= columnsCount
+	}
+	c.moveTo(index)
+}
+
+func (c *tableCursor) moveTo(index int) {
+	if index >= len(c.addresses) {
+		index = len(c.addresses) - 1
+	}
+	if index < 0 {
+		index = 0
+	}
+	c.index = index
+
+	if index < len(c.addresses) {
+		c.address = c.addresses[index]
+	}
+}
+
+// formatValidatorDetails returns the details of the validator to be rendered in the detail pane.
+func formatValidatorDetails(voteState enginetypes.ValidatorVoteState, votingInfo *enginetypes.NextBlockVotingInformation, now time.Time) string {
+	validator := voteState.Validator
+
+	var lines []string
+	lines = append(lines, fmt.Sprintf("Moniker: %s | Order: %d | Voting power: %d (%.2f%%)", validator.Moniker, validator.Index+1, validator.VotingPower, validator.VotingPowerDisplayPercent))
+
+	consensusAddress := validator.Address
+	if details := validator.Details; details != nil {
+		if len(details.ConsensusAddress) > 0 {
+			consensusAddress = fmt.Sprintf("%s (%s)", details.ConsensusAddress, validator.Address)
+		}
+		lines = append(lines, fmt.Sprintf("Operator: %s | Tokens: %s", details.OperatorAddress, details.Tokens))
+		lines = append(lines, fmt.Sprintf(
+			"Commission: %s (max %s, max change %s)",
+			formatRatePercent(details.CommissionRate), formatRatePercent(details.CommissionMaxRate), formatRatePercent(details.CommissionMaxChangeRate),
+		))
+	}
+	lines = append(lines, fmt.Sprintf("Consensus address: %s", consensusAddress))
+	lines = append(lines, fmt.Sprintf("Pubkey: %s", validator.PubKey))
+
+	lines = append(lines, fmt.Sprintf("Pre-vote: %s", formatRawVote(voteState.PreVote)))
+	if voteState.PreVoted && !voteState.PreVoteTime.IsZero() {
+		lines[len(lines)-1] += fmt.Sprintf(" (+%s)", voteState.PreVoteTime.Sub(votingInfo.StartTimeUTC).Round(100*time.Millisecond))
+	}
+	lines = append(lines, fmt.Sprintf("Pre-commit: %s", formatRawVote(voteState.PreCommit)))
+
+	if signingInfo := validator.SigningInfo; signingInfo != nil {
+		line := fmt.Sprintf("Missed blocks: %d", signingInfo.MissedBlocksCounter)
+		if signingInfo.MaxMissedBlocks > 0 {
+			line += fmt.Sprintf(" of max %d", signingInfo.MaxMissedBlocks)
+		}
+		if signingInfo.Tombstoned {
+			line += " | Tombstoned"
+		} else if signingInfo.IsJailed(now) {
+			line += fmt.Sprintf(" | Jailed until %s", signingInfo.JailedUntil.UTC().Format(time.RFC3339))
+		}
+		lines = append(lines, line)
+	}
+
+	if window := votingInfo.SignedBlocksWindow; window != nil && window.Size() > 0 {
+		signedBlocks := window.GetSignedBlocks(validator.Address)
+		width := len(signedBlocks)
+		if width > detailsSparklineWidth {
+			width = detailsSparklineWidth
+		}
+		lines = append(lines, fmt.Sprintf(
+			"Signed blocks %d-%d: %d/%d %s",
+			window.FromHeight, window.ToHeight, window.CountSignedBlocks(validator.Address), window.Size(),
+			renderSignedBlocksSparkline(signedBlocks, width),
+		))
+	} else if validator.SigningInfo != nil {
+		// recent blocks are only fetched with --signed-blocks-window
+		lines = append(lines, "Signed blocks: n/a, participation is the missed blocks counter of signing info above, add --signed-blocks-window to show the recent blocks")
+	} else {
+		lines = append(lines, "Signed blocks: n/a, add --signed-blocks-window to show the recent blocks")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func formatRatePercent(rate float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", rate*100), "0"), ".") + "%"
+}
+
+func formatRawVote(vote string) string {
+	if len(vote) < 1 {
+		return "n/a"
+	}
+	return vote
+}
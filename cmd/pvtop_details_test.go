@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func Test_tableCursor(t *testing.T) {
+	voteStates := newTestVoteStates(true, true, true, true, true, true, true) // 3 columns: 3 rows, last row is not full
+
+	cursor := &tableCursor{}
+	cursor.update(voteStates)
+	require.Equal(t, 0, cursor.index)
+	require.Equal(t, voteStates[0].Validator.Address, cursor.address)
+
+	require.True(t, cursor.handleKeyEvent("j", 3))
+	require.Equal(t, 3, cursor.index)
+	require.True(t, cursor.handleKeyEvent("l", 3))
+	require.Equal(t, 4, cursor.index)
+	require.True(t, cursor.handleKeyEvent("<Down>", 3))
+	require.Equal(t, 4, cursor.index, "must not move below the last validator of the column")
+	require.True(t, cursor.handleKeyEvent("k", 3))
+	require.Equal(t, 1, cursor.index)
+	require.True(t, cursor.handleKeyEvent("h", 3))
+	require.True(t, cursor.handleKeyEvent("h", 3))
+	require.Equal(t, 0, cursor.index)
+	require.False(t, cursor.handleKeyEvent("s", 3))
+
+	// keep the selected validator after re-ordered
+	require.True(t, cursor.handleKeyEvent("<Tab>", 3))
+	reversed := make([]enginetypes.ValidatorVoteState, len(voteStates))
+	for i, voteState := range voteStates {
+		reversed[len(voteStates)-1-i] = voteState
+	}
+	cursor.update(reversed)
+	require.Equal(t, 5, cursor.index)
+	require.Equal(t, voteStates[1].Validator.Address, cursor.address)
+
+	// selected validator is filtered out
+	cursor.update(voteStates[:2])
+	require.Equal(t, 1, cursor.index)
+	cursor.update(nil)
+	require.Equal(t, 0, cursor.index)
+
+	// moved into the visible rows after scrolling
+	cursor.update(voteStates)
+	require.Equal(t, 1, cursor.index)
+	cursor.moveTo(0)
+	cursor.moveIntoRows(2, 3, 3)
+	require.Equal(t, 6, cursor.index)
+	cursor.moveTo(2)
+	cursor.moveIntoRows(2, 3, 3)
+	require.Equal(t, 5, cursor.index, "the last row is not full, keep the cursor at the previous row")
+	cursor.moveIntoRows(0, 1, 3)
+	require.Equal(t, 2, cursor.index)
+}
+
+func Test_formatValidatorDetails(t *testing.T) {
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	voteState := newTestVoteStates(true)[0]
+	voteState.Validator.VotingPower = 1000
+	voteState.Validator.VotingPowerDisplayPercent = 12.5
+	voteState.Validator.PubKey = "PUBKEY"
+	voteState.Validator.Details = &enginetypes.LightValidatorDetails{
+		OperatorAddress:         "cosmosvaloper1xxx",
+		ConsensusAddress:        "cosmosvalcons1yyy",
+		Tokens:                  "1000000000",
+		CommissionRate:          0.05,
+		CommissionMaxRate:       0.2,
+		CommissionMaxChangeRate: 0.015,
+	}
+	voteState.Validator.SigningInfo = &enginetypes.LightValidatorSigningInfo{
+		MissedBlocksCounter: 3,
+		MaxMissedBlocks:     500,
+	}
+	voteState.PreVote = "Vote{0:000000000000 100/00/SIGNED_MSG_TYPE_PREVOTE(Prevote) C0FFEE000000 5C8A2B6F2E6B @ 2024-01-01T00:00:01.5Z}"
+	voteState.PreVoteTime = startTime.Add(1500 * time.Millisecond)
+	voteState.PreCommit = "nil-Vote"
+
+	votingInfo := &enginetypes.NextBlockVotingInformation{
+		StartTimeUTC: startTime,
+		SignedBlocksWindow: &enginetypes.SignedBlocksWindow{
+			FromHeight: 98,
+			ToHeight:   99,
+			SignedBlocks: map[string][]bool{
+				voteState.Validator.Address: {true, false},
+			},
+		},
+	}
+
+	require.Equal(t, `Moniker: val0 | Order: 1 | Voting power: 1000 (12.50%)
+Operator: cosmosvaloper1xxx | Tokens: 1000000000
+Commission: 5% (max 20%, max change 1.5%)
+Consensus address: cosmosvalcons1yyy (0000000000000000000000000000000000000000)
+Pubkey: PUBKEY
+Pre-vote: `+voteState.PreVote+` (+1.5s)
+Pre-commit: nil-Vote
+Missed blocks: 3 of max 500
+Signed blocks 98-99: 1/2 █▁`, formatValidatorDetails(voteState, votingInfo, startTime))
+
+	// without optional information
+	voteState = newTestVoteStates(false)[0]
+	require.Equal(t, `Moniker: val0 | Order: 1 | Voting power: 0 (0.00%)
+Consensus address: 0000000000000000000000000000000000000000
+Pubkey: 
+Pre-vote: n/a
+Pre-commit: n/a
+Signed blocks: n/a, add --signed-blocks-window to show the recent blocks`, formatValidatorDetails(voteState, &enginetypes.NextBlockVotingInformation{}, startTime))
+
+	// fallback to the missed blocks counter of signing info when the signed blocks window is not fetched
+	voteState.Validator.SigningInfo = &enginetypes.LightValidatorSigningInfo{
+		MissedBlocksCounter: 3,
+	}
+	require.Equal(t, `Moniker: val0 | Order: 1 | Voting power: 0 (0.00%)
+Consensus address: 0000000000000000000000000000000000000000
+Pubkey: 
+Pre-vote: n/a
+Pre-commit: n/a
+Missed blocks: 3
+Signed blocks: n/a, participation is the missed blocks counter of signing info above, add --signed-blocks-window to show the recent blocks`, formatValidatorDetails(voteState, &enginetypes.NextBlockVotingInformation{}, startTime))
+}
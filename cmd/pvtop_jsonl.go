@@ -0,0 +1,69 @@
+package cmd
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"encoding/json"
+	"github.com/bcdevtools/consvp/aos"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"github.com/bcdevtools/consvp/utils"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// printJsonLines writes each pre-vote information as a JSON object per line into stdout, follows schema.SnapshotV1.
+// Errors and broadcast status are written into stderr, to keep stdout machine-readable.
+func printJsonLines(votingInfoChan <-chan interface{}, broadcastingStatusChan <-chan string) {
+	defer utils.AppExitHelper.ExecuteFunctionsUponAppExit()
+
+	signalChan := make(chan os.Signal, 1)
+	signal.Notify(signalChan, os.Interrupt, syscall.SIGTERM)
+
+	for {
+		select {
+		case <-signalChan:
+			aos.Exit(0)
+		case votingInfoAny := <-votingInfoChan:
+			if votingInfoAny == nil {
+				continue
+			}
+
+			if err, ok := votingInfoAny.(error); ok {
+				utils.PrintlnStdErr("ERR: " + err.Error())
+				continue
+			}
+
+			err := writeJsonLine(os.Stdout, votingInfoAny.(*enginetypes.NextBlockVotingInformation), time.Now())
+			if err != nil {
+				utils.PrintlnStdErr("ERR: failed to write JSON line")
+				utils.PrintlnStdErr(err)
+			}
+			break
+		case broadcastStatus := <-broadcastingStatusChan:
+			if len(broadcastStatus) < 1 {
+				continue
+			}
+
+			utils.PrintlnStdErr("broadcast: " + broadcastStatus)
+			break
+		}
+	}
+}
+
+// writeJsonLine writes the voting information as a single-line JSON object of schema.SnapshotV1.
+func writeJsonLine(writer io.Writer, votingInfo *enginetypes.NextBlockVotingInformation, capturedAt time.Time) error {
+	snapshot, err := votingInfo.ToSnapshotV1(capturedAt)
+	if err != nil {
+		return err
+	}
+
+	bz, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.Write(append(bz, '\n'))
+	return err
+}
@@ -0,0 +1,102 @@
+package cmd
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"bytes"
+	"encoding/json"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"github.com/bcdevtools/consvp/schema"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_writeJsonLine(t *testing.T) {
+	capturedAt := time.Date(2023, 12, 25, 3, 0, 5, 0, time.UTC)
+	startTime := capturedAt.Add(-2 * time.Second)
+
+	//goland:noinspection SpellCheckingInspection
+	votingInfo := &enginetypes.NextBlockVotingInformation{
+		SortedValidatorVoteStates: []enginetypes.ValidatorVoteState{
+			{
+				Validator:       enginetypes.LightValidator{Index: 1, Moniker: "Val2", Address: "AA", VotingPower: 40, VotingPowerDisplayPercent: 40},
+				VotingBlockHash: "C0FFEE000000",
+				PreVoted:        true,
+				PreCommitVoted:  true,
+			},
+			{
+				Validator:       enginetypes.LightValidator{Index: 0, Moniker: "Val1", Address: "BB", VotingPower: 35, VotingPowerDisplayPercent: 35},
+				VotingBlockHash: "000000000000",
+				PreVoted:        true,
+				VotedZeroes:     true,
+			},
+			{
+				Validator: enginetypes.LightValidator{Index: 2, Moniker: "Val3", Address: "CC", VotingPower: 25, VotingPowerDisplayPercent: 25},
+			},
+		},
+		PreVotePercent:   75,
+		PreCommitPercent: 40,
+		HeightRoundStep:  "100/2/6",
+		StartTimeUTC:     startTime,
+	}
+
+	buffer := &bytes.Buffer{}
+	require.NoError(t, writeJsonLine(buffer, votingInfo, capturedAt))
+	require.NoError(t, writeJsonLine(buffer, votingInfo, capturedAt))
+
+	lines := strings.Split(strings.TrimSuffix(buffer.String(), "\n"), "\n")
+	require.Len(t, lines, 2, "each snapshot must be written in a single line")
+
+	var snapshot schema.SnapshotV1
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &snapshot))
+
+	//goland:noinspection SpellCheckingInspection
+	require.Equal(t, schema.SnapshotV1{
+		Schema:           schema.SchemaSnapshotV1,
+		Time:             capturedAt,
+		Height:           100,
+		Round:            2,
+		Step:             6,
+		StartTime:        startTime,
+		PreVotePercent:   75,
+		PreCommitPercent: 40,
+		Validators: []schema.ValidatorSnapshotV1{
+			{
+				Index:              1,
+				Address:            "AA",
+				Moniker:            "Val2",
+				VotingPower:        40,
+				VotingPowerPercent: 40,
+				PreVote:            schema.VoteStateV1Voted,
+				PreCommit:          schema.VoteStateV1Voted,
+				PreVoteBlockHash:   "C0FFEE000000",
+			},
+			{
+				Index:              0,
+				Address:            "BB",
+				Moniker:            "Val1",
+				VotingPower:        35,
+				VotingPowerPercent: 35,
+				PreVote:            schema.VoteStateV1VotedZeroes,
+				PreCommit:          schema.VoteStateV1Missing,
+				PreVoteBlockHash:   "000000000000",
+			},
+			{
+				Index:              2,
+				Address:            "CC",
+				Moniker:            "Val3",
+				VotingPower:        25,
+				VotingPowerPercent: 25,
+				PreVote:            schema.VoteStateV1Missing,
+				PreCommit:          schema.VoteStateV1Missing,
+			},
+		},
+	}, snapshot)
+}
+
+func Test_writeJsonLine_malformedHeightRoundStep(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	require.Error(t, writeJsonLine(buffer, &enginetypes.NextBlockVotingInformation{HeightRoundStep: "bad"}, time.Now()))
+	require.Empty(t, buffer.String(), "must not emit snapshot with zero height")
+}
@@ -0,0 +1,122 @@
+package cmd
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"fmt"
+	"github.com/bcdevtools/consvp/aos"
+	"github.com/bcdevtools/consvp/utils"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+const (
+	flagLogFile           = "log-file"
+	flagLogFileMaxSizeMb  = "log-file-max-size-mb"
+	flagLogFileMaxBackups = "log-file-max-backups"
+)
+
+const (
+	defaultLogFileMaxSizeMb  = 10
+	defaultLogFileMaxBackups = 3
+)
+
+// maxLogPanelEntries is the number of recent messages kept for the log panel.
+const maxLogPanelEntries = 500
+
+// readLogFile returns the rolling log file enabled by flags, nil if not enabled.
+// Exit the app if the file could not be opened.
+func readLogFile(cmd *cobra.Command) *utils.RollingFile {
+	filePath, _ := cmd.Flags().GetString(flagLogFile)
+	if len(filePath) < 1 {
+		return nil
+	}
+
+	maxSizeMb, _ := cmd.Flags().GetInt(flagLogFileMaxSizeMb)
+	maxBackups, _ := cmd.Flags().GetInt(flagLogFileMaxBackups)
+	// owner only, messages might contain sensitive information
+	logFile, err := utils.NewRollingFile(filePath, 0o600, int64(maxSizeMb)*1024*1024, maxBackups)
+	if err != nil {
+		utils.PrintlnStdErr("ERR: failed to open log file " + filePath)
+		utils.PrintlnStdErr(err)
+		aos.Exit(1)
+	}
+
+	return logFile
+}
+
+// logPanel holds the recent messages printed via utils.StdHelper, to be rendered in the log panel on terminal UI.
+type logPanel struct {
+	entries []utils.LogEntry
+
+	// unseenCount is the number of warnings and errors received while the panel is hidden.
+	unseenCount int
+}
+
+// add appends the entry, counts it as unseen if it is a warning or error and the panel is hidden.
+func (p *logPanel) add(entry utils.LogEntry, visible bool) {
+	p.entries = append(p.entries, entry)
+	if len(p.entries) > maxLogPanelEntries {
+		p.entries = append([]utils.LogEntry(nil), p.entries[len(p.entries)-maxLogPanelEntries:]...)
+	}
+
+	if !visible && entry.Level >= utils.LogLevelWarn {
+		p.unseenCount++
+	}
+}
+
+// markSeen resets the unseen counter, when the panel is shown.
+func (p *logPanel) markSeen() {
+	p.unseenCount = 0
+}
+
+// describeUnseen returns a short notice of the unseen warnings and errors, empty if none.
+func (p *logPanel) describeUnseen() string {
+	if p.unseenCount < 1 {
+		return ""
+	}
+	return fmt.Sprintf("| %s%d new warnings/errors [L] ", symbols.warning, p.unseenCount)
+}
+
+// rows returns the most recent rows fit into the panel, the latest at the bottom.
+// Multi-line messages are split into multiple rows.
+func (p *logPanel) rows(visibleRowsCount int) []string {
+	var rows []string
+	for i := len(p.entries) - 1; i >= 0 && len(rows) < visibleRowsCount; i-- {
+		entryRows := formatLogEntry(p.entries[i])
+		for j := len(entryRows) - 1; j >= 0 && len(rows) < visibleRowsCount; j-- {
+			rows = append(rows, entryRows[j])
+		}
+	}
+
+	// reverse to have the latest at the bottom
+	for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+		rows[i], rows[j] = rows[j], rows[i]
+	}
+	return rows
+}
+
+// formatLogEntry returns the rows of the entry to be rendered in the log panel, the severity level is styled by the theme.
+func formatLogEntry(entry utils.LogEntry) []string {
+	level := fmt.Sprintf("%-5s", entry.Level)
+	var style string
+	switch entry.Level {
+	case utils.LogLevelWarn:
+		style = theme.logWarn
+	case utils.LogLevelError:
+		style = theme.logError
+	}
+	if len(style) > 0 {
+		level = fmt.Sprintf("[%s](%s)", level, style)
+	}
+
+	lines := strings.Split(entry.Message, "\n")
+	rows := make([]string, len(lines))
+	for i, line := range lines {
+		if i == 0 {
+			rows[i] = fmt.Sprintf("%s %s %s", entry.Time.Local().Format("15:04:05"), level, line)
+		} else {
+			rows[i] = strings.Repeat(" ", len("15:04:05 LEVEL ")) + line
+		}
+	}
+	return rows
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/bcdevtools/consvp/utils"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func Test_logPanel(t *testing.T) {
+	defer func(original colorTheme) {
+		theme = original
+	}(theme)
+	theme = monoTheme
+
+	entryTime := time.Date(2024, 1, 1, 10, 20, 30, 0, time.Local)
+	entry := func(level utils.LogLevel, message string) utils.LogEntry {
+		return utils.LogEntry{
+			Time:    entryTime,
+			Level:   level,
+			Message: message,
+		}
+	}
+
+	logs := &logPanel{}
+	logs.add(entry(utils.LogLevelInfo, "ALERT [firing] low-prevote"), false)
+	require.Empty(t, logs.describeUnseen(), "info is not counted")
+
+	logs.add(entry(utils.LogLevelError, "failed to fetch light validators\nconnection refused"), false)
+	logs.add(entry(utils.LogLevelWarn, "failed to save renewed streaming session credentials"), false)
+	require.Equal(t, fmt.Sprintf("| %s2 new warnings/errors [L] ", symbols.warning), logs.describeUnseen())
+
+	require.Equal(t, []string{
+		"10:20:30 INFO  ALERT [firing] low-prevote",
+		"10:20:30 [ERROR](mod:bold) failed to fetch light validators",
+		"               connection refused",
+		"10:20:30 WARN  failed to save renewed streaming session credentials",
+	}, logs.rows(10))
+	require.Equal(t, []string{
+		"               connection refused",
+		"10:20:30 WARN  failed to save renewed streaming session credentials",
+	}, logs.rows(2), "the latest rows fit into the panel")
+	require.Empty(t, logs.rows(0))
+
+	logs.markSeen()
+	require.Empty(t, logs.describeUnseen())
+	logs.add(entry(utils.LogLevelError, "visible"), true)
+	require.Empty(t, logs.describeUnseen(), "not counted while the panel is shown")
+
+	for i := 0; i < maxLogPanelEntries+10; i++ {
+		logs.add(entry(utils.LogLevelInfo, fmt.Sprint(i)), true)
+	}
+	require.Len(t, logs.entries, maxLogPanelEntries)
+	require.Equal(t, fmt.Sprint(maxLogPanelEntries+9), logs.entries[maxLogPanelEntries-1].Message)
+}
@@ -0,0 +1,89 @@
+package cmd
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"fmt"
+	"github.com/bcdevtools/consvp/aos"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"github.com/bcdevtools/consvp/utils"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// printPlain prints pre-vote information as plain text, one block per refresh.
+// Used when terminal UI is not available, like CI logs, piping output or remote shells without TTY.
+func printPlain(votingInfoChan <-chan interface{}, broadcastingStatusChan <-chan string) {
+	defer utils.AppExitHelper.ExecuteFunctionsUponAppExit()
+
+	signalChan := make(chan os.Signal, 1)
+	signal.Notify(signalChan, os.Interrupt, syscall.SIGTERM)
+
+	for {
+		select {
+		case <-signalChan:
+			aos.Exit(0)
+		case votingInfoAny := <-votingInfoChan:
+			if votingInfoAny == nil {
+				continue
+			}
+
+			if err, ok := votingInfoAny.(error); ok {
+				fmt.Printf("[%s] ERR: %s\n\n", time.Now().Format("15:04:05"), err.Error())
+				continue
+			}
+
+			fmt.Println(formatPlainVotingInfo(votingInfoAny.(*enginetypes.NextBlockVotingInformation), time.Now()))
+			break
+		case broadcastStatus := <-broadcastingStatusChan:
+			if len(broadcastStatus) < 1 {
+				continue
+			}
+
+			fmt.Printf("[%s] broadcast: %s\n\n", time.Now().Format("15:04:05"), broadcastStatus)
+			break
+		}
+	}
+}
+
+// formatPlainVotingInfo formats the given voting information into a concise block of text,
+// includes height/round/step, percentages and validators those have not pre-voted yet, sorted by voting power.
+func formatPlainVotingInfo(votingInfo *enginetypes.NextBlockVotingInformation, now time.Time) string {
+	duration := now.UTC().Sub(votingInfo.StartTimeUTC)
+	if duration < 0 {
+		duration = 0
+	}
+
+	var notPreVoted, notPreCommitVoted []enginetypes.ValidatorVoteState
+	var notPreVotedVotingPowerPercent float64
+	for _, voteState := range votingInfo.SortedValidatorVoteStates {
+		if !voteState.PreVoted {
+			notPreVoted = append(notPreVoted, voteState)
+			notPreVotedVotingPowerPercent += voteState.Validator.VotingPowerDisplayPercent
+		}
+		if !voteState.PreCommitVoted {
+			notPreCommitVoted = append(notPreCommitVoted, voteState)
+		}
+	}
+
+	totalVoteCount := len(votingInfo.SortedValidatorVoteStates)
+
+	sb := strings.Builder{}
+	sb.WriteString(fmt.Sprintf("[%s] height/round/step: %s (%v)\n", now.Format("15:04:05"), votingInfo.HeightRoundStep, duration.Truncate(100*time.Millisecond)))
+	sb.WriteString(fmt.Sprintf(
+		"pre-vote: %.0f%% (%d/%d) pre-commit: %.0f%% (%d/%d)\n",
+		votingInfo.PreVotePercent, totalVoteCount-len(notPreVoted), totalVoteCount,
+		votingInfo.PreCommitPercent, totalVoteCount-len(notPreCommitVoted), totalVoteCount,
+	))
+
+	if len(notPreVoted) > 0 {
+		sb.WriteString(fmt.Sprintf("not pre-voted: %d validators, %.2f%% VP\n", len(notPreVoted), notPreVotedVotingPowerPercent))
+		for _, voteState := range notPreVoted { // already sorted by voting power
+			sb.WriteString(fmt.Sprintf("  %6.2f%% %-3d %s\n", voteState.Validator.VotingPowerDisplayPercent, voteState.Validator.Index+1, voteState.Validator.Moniker))
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func Test_formatPlainVotingInfo(t *testing.T) {
+	now := time.Date(2023, 12, 25, 3, 0, 5, 0, time.UTC)
+
+	votingInfo := &enginetypes.NextBlockVotingInformation{
+		SortedValidatorVoteStates: []enginetypes.ValidatorVoteState{
+			{
+				Validator:      enginetypes.LightValidator{Index: 1, Moniker: "Val2", VotingPowerDisplayPercent: 40},
+				PreVoted:       true,
+				PreCommitVoted: true,
+			},
+			{
+				Validator: enginetypes.LightValidator{Index: 0, Moniker: "Val1", VotingPowerDisplayPercent: 35},
+			},
+			{
+				Validator: enginetypes.LightValidator{Index: 2, Moniker: "Val3", VotingPowerDisplayPercent: 25},
+				PreVoted:  true,
+			},
+		},
+		PreVotePercent:   65,
+		PreCommitPercent: 40,
+		HeightRoundStep:  "100/0/6",
+		StartTimeUTC:     now.Add(-2 * time.Second),
+	}
+
+	want := `[03:00:05] height/round/step: 100/0/6 (2s)
+pre-vote: 65% (2/3) pre-commit: 40% (1/3)
+not pre-voted: 1 validators, 35.00% VP
+   35.00% 1   Val1
+`
+	require.Equal(t, want, formatPlainVotingInfo(votingInfo, now))
+
+	votingInfo.SortedValidatorVoteStates[1].PreVoted = true
+	votingInfo.PreVotePercent = 100
+	require.Equal(t, `[03:00:05] height/round/step: 100/0/6 (2s)
+pre-vote: 100% (3/3) pre-commit: 40% (1/3)
+`, formatPlainVotingInfo(votingInfo, now), "should not list when all pre-voted")
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+)
+
+const (
+	// minColumnsCount and maxColumnsCount are the limits of the number of validator columns on terminal UI.
+	minColumnsCount = 1
+	maxColumnsCount = 8
+
+	// mouseWheelScrollRows is the number of rows to be scrolled per mouse wheel event.
+	mouseWheelScrollRows = 3
+)
+
+// getColumnsCount returns the number of validator columns fit the terminal width, each column has the given width.
+func getColumnsCount(termWidth, columnWidth int) int {
+	if columnWidth < 1 {
+		return minColumnsCount
+	}
+
+	columnsCount := termWidth / columnWidth
+	if columnsCount < minColumnsCount {
+		return minColumnsCount
+	}
+	if columnsCount > maxColumnsCount {
+		return maxColumnsCount
+	}
+	return columnsCount
+}
+
+// tableScroll is the scrolling state of the validator table, shared by all the columns so they are scrolled in sync.
+//
+// Key bindings: 'PageDown'/'Ctrl+F' and 'PageUp'/'Ctrl+B' scroll by page, 'Home'/'g' and 'End'/'G' jump to the top
+// and the bottom, mouse wheel scrolls by 3 rows. Scrolling by row follows the cursor, see ensureVisible.
+type tableScroll struct {
+	// offset is the index of the first visible row.
+	offset int
+
+	rowsCount        int
+	visibleRowsCount int
+}
+
+// update updates the number of rows and visible rows, then keeps the offset in range.
+func (s *tableScroll) update(rowsCount, visibleRowsCount int) {
+	if visibleRowsCount < 0 {
+		visibleRowsCount = 0
+	}
+	s.rowsCount = rowsCount
+	s.visibleRowsCount = visibleRowsCount
+	s.scrollTo(s.offset)
+}
+
+// handleKeyEvent handles the key event, returns true if the event is consumed.
+func (s *tableScroll) handleKeyEvent(eventId string) bool {
+	page := s.visibleRowsCount - 1
+	if page < 1 {
+		page = 1
+	}
+
+	switch eventId {
+	case "<MouseWheelDown>":
+		s.scrollTo(s.offset + mouseWheelScrollRows)
+	case "<MouseWheelUp>":
+		s.scrollTo(s.offset - mouseWheelScrollRows)
+	case "<PageDown>", "<C-f>":
+		s.scrollTo(s.offset + page)
+	case "<PageUp>", "<C-b>":
+		s.scrollTo(s.offset - page)
+	case "<Home>", "g":
+		s.scrollTo(0)
+	case "<End>", "G":
+		s.scrollTo(s.rowsCount)
+	default:
+		return false
+	}
+
+	return true
+}
+
+// ensureVisible scrolls the minimum number of rows to make the given row visible.
+func (s *tableScroll) ensureVisible(row int) {
+	if row < s.offset {
+		s.scrollTo(row)
+	} else if row >= s.offset+s.visibleRowsCount {
+		s.scrollTo(row - s.visibleRowsCount + 1)
+	}
+}
+
+func (s *tableScroll) scrollTo(offset int) {
+	maxOffset := s.rowsCount - s.visibleRowsCount
+	if offset > maxOffset {
+		offset = maxOffset
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	s.offset = offset
+}
+
+// visibleRange returns the range [from, to) of the visible rows.
+func (s *tableScroll) visibleRange() (from, to int) {
+	to = s.offset + s.visibleRowsCount
+	if to > s.rowsCount {
+		to = s.rowsCount
+	}
+	return s.offset, to
+}
+
+// describe returns the position indicator, empty if all rows are visible.
+func (s *tableScroll) describe() string {
+	if s.rowsCount <= s.visibleRowsCount {
+		return ""
+	}
+
+	from, to := s.visibleRange()
+	var arrows string
+	if from > 0 {
+		arrows += symbols.scrollUp
+	}
+	if to < s.rowsCount {
+		arrows += symbols.scrollDown
+	}
+	return fmt.Sprintf(" rows %d-%d of %d %s ", from+1, to, s.rowsCount, arrows)
+}
@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func Test_getColumnsCount(t *testing.T) {
+	require.Equal(t, 3, getColumnsCount(130, 42))
+	require.Equal(t, 1, getColumnsCount(30, 42))
+	require.Equal(t, 1, getColumnsCount(100, 0))
+	require.Equal(t, maxColumnsCount, getColumnsCount(1000, 42))
+}
+
+func Test_tableScroll(t *testing.T) {
+	scroll := &tableScroll{}
+	scroll.update(50, 20)
+	require.Equal(t, " rows 1-20 of 50 ↓ ", scroll.describe())
+
+	scroll.ensureVisible(20)
+	require.Equal(t, 1, scroll.offset)
+	scroll.ensureVisible(5)
+	require.Equal(t, 1, scroll.offset)
+	require.True(t, scroll.handleKeyEvent("<MouseWheelDown>"))
+	require.Equal(t, 4, scroll.offset)
+	require.True(t, scroll.handleKeyEvent("<PageDown>"))
+	require.Equal(t, 23, scroll.offset)
+	require.True(t, scroll.handleKeyEvent("<PageDown>"))
+	require.Equal(t, 30, scroll.offset, "must not scroll beyond the last row")
+	require.Equal(t, " rows 31-50 of 50 ↑ ", scroll.describe())
+
+	require.True(t, scroll.handleKeyEvent("<PageUp>"))
+	require.Equal(t, 11, scroll.offset)
+	require.Equal(t, " rows 12-31 of 50 ↑↓ ", scroll.describe())
+	require.True(t, scroll.handleKeyEvent("<Home>"))
+	require.Equal(t, 0, scroll.offset)
+	require.True(t, scroll.handleKeyEvent("<MouseWheelUp>"))
+	require.Equal(t, 0, scroll.offset)
+	require.True(t, scroll.handleKeyEvent("<End>"))
+	require.Equal(t, 30, scroll.offset)
+
+	scroll.ensureVisible(10)
+	require.Equal(t, 10, scroll.offset)
+
+	require.False(t, scroll.handleKeyEvent("s"))
+	require.False(t, scroll.handleKeyEvent("j"))
+
+	// fewer rows after filtered or terminal enlarged
+	scroll.update(25, 20)
+	require.Equal(t, 5, scroll.offset)
+	from, to := scroll.visibleRange()
+	require.Equal(t, 5, from)
+	require.Equal(t, 25, to)
+
+	scroll.update(10, 20)
+	require.Equal(t, 0, scroll.offset)
+	require.Empty(t, scroll.describe())
+
+	scroll.update(10, -1)
+	from, to = scroll.visibleRange()
+	require.Equal(t, 0, to-from)
+}
@@ -0,0 +1,108 @@
+package cmd
+
+//goland:noinspection SpellCheckingInspection
+import (
+	conss "github.com/bcdevtools/consvp/engine/consensus_service"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"github.com/bcdevtools/consvp/utils"
+	"sync"
+)
+
+// signedBlocksWindowFetcher fetches the signed blocks window in background,
+// so the refresh loop is never blocked by the '/commit' queries.
+// The window is only fetched once per committed height.
+type signedBlocksWindowFetcher struct {
+	mutex *sync.Mutex
+
+	consensusService conss.ConsensusService
+	windowSize       int
+
+	// pending is the latest requested, only the latest one is fetched, outdated requests are dropped.
+	pending    *signedBlocksWindowRequest
+	notifyChan chan struct{}
+
+	// requestedHeight is the latest committed height of the latest request, to avoid re-fetching the same window.
+	requestedHeight int64
+
+	latest *enginetypes.SignedBlocksWindow
+}
+
+type signedBlocksWindowRequest struct {
+	lightValidators enginetypes.LightValidators
+	latestHeight    int64 // latest committed height
+}
+
+// newSignedBlocksWindowFetcher returns a new fetcher, call run in a separated goroutine to start fetching.
+func newSignedBlocksWindowFetcher(consensusService conss.ConsensusService, windowSize int) *signedBlocksWindowFetcher {
+	return &signedBlocksWindowFetcher{
+		mutex:            &sync.Mutex{},
+		consensusService: consensusService,
+		windowSize:       windowSize,
+		notifyChan:       make(chan struct{}, 1),
+	}
+}
+
+// offer requests the window prior to the height currently being voted, never blocks.
+// Returns the latest fetched window, nil if not available yet.
+func (f *signedBlocksWindowFetcher) offer(lightValidators enginetypes.LightValidators, votingInfo *enginetypes.NextBlockVotingInformation) *enginetypes.SignedBlocksWindow {
+	height, _, _, err := enginetypes.ParseHeightRoundStep(votingInfo.HeightRoundStep)
+	if err != nil {
+		utils.StdHelper.PrintlnErr("failed to parse current height", err)
+		return f.getLatest()
+	}
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if height < 2 { // no committed block yet
+		return f.latest
+	}
+
+	if f.requestedHeight != height-1 {
+		f.requestedHeight = height - 1
+		f.pending = &signedBlocksWindowRequest{
+			lightValidators: lightValidators,
+			latestHeight:    height - 1,
+		}
+		select {
+		case f.notifyChan <- struct{}{}:
+		default: // already notified
+		}
+	}
+
+	return f.latest
+}
+
+// run fetches the requested windows, never returns.
+func (f *signedBlocksWindowFetcher) run() {
+	for range f.notifyChan {
+		f.mutex.Lock()
+		request := f.pending
+		f.pending = nil
+		f.mutex.Unlock()
+
+		if request == nil {
+			continue
+		}
+
+		window, err := f.consensusService.GetSignedBlocksWindow(request.lightValidators, request.latestHeight, f.windowSize)
+		f.mutex.Lock()
+		if err == nil {
+			f.latest = window
+		} else if f.requestedHeight == request.latestHeight {
+			f.requestedHeight = 0 // retry on the next offer
+		}
+		f.mutex.Unlock()
+
+		if err != nil {
+			utils.StdHelper.PrintlnErr("failed to fetch signed blocks window", err)
+		}
+	}
+}
+
+func (f *signedBlocksWindowFetcher) getLatest() *enginetypes.SignedBlocksWindow {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.latest
+}
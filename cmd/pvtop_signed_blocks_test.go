@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"github.com/stretchr/testify/require"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testSignedBlocksConsensusService struct {
+	mutex         *sync.Mutex
+	err           error
+	latestHeights []int64
+	release       chan struct{}
+}
+
+func (s *testSignedBlocksConsensusService) GetNextBlockVotingInformation(enginetypes.LightValidators) (*enginetypes.NextBlockVotingInformation, error) {
+	panic("not implemented")
+}
+
+func (s *testSignedBlocksConsensusService) GetSignedBlocksWindow(_ enginetypes.LightValidators, latestHeight int64, windowSize int) (*enginetypes.SignedBlocksWindow, error) {
+	<-s.release
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.latestHeights = append(s.latestHeights, latestHeight)
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &enginetypes.SignedBlocksWindow{
+		FromHeight: latestHeight - int64(windowSize) + 1,
+		ToHeight:   latestHeight,
+	}, nil
+}
+
+func (s *testSignedBlocksConsensusService) Shutdown() error {
+	return nil
+}
+
+func (s *testSignedBlocksConsensusService) fetchedHeights() []int64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return append([]int64{}, s.latestHeights...)
+}
+
+func Test_signedBlocksWindowFetcher(t *testing.T) {
+	consensusService := &testSignedBlocksConsensusService{
+		mutex:   &sync.Mutex{},
+		release: make(chan struct{}),
+	}
+	fetcher := newSignedBlocksWindowFetcher(consensusService, 5)
+	go fetcher.run()
+
+	votingInfo := func(height int64) *enginetypes.NextBlockVotingInformation {
+		return &enginetypes.NextBlockVotingInformation{
+			HeightRoundStep: fmt.Sprintf("%d/0/1", height),
+		}
+	}
+
+	// never blocks while fetching
+	require.Nil(t, fetcher.offer(nil, votingInfo(100)))
+	require.Nil(t, fetcher.offer(nil, votingInfo(100)))
+	consensusService.release <- struct{}{}
+	require.Eventually(t, func() bool {
+		return fetcher.offer(nil, votingInfo(100)) != nil
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, int64(99), fetcher.offer(nil, votingInfo(100)).ToHeight)
+	require.Equal(t, []int64{99}, consensusService.fetchedHeights(), "must fetch once per height")
+
+	// keeps the previous window until the new one is fetched
+	consensusService.mutex.Lock()
+	consensusService.err = fmt.Errorf("rpc down")
+	consensusService.mutex.Unlock()
+	require.Equal(t, int64(99), fetcher.offer(nil, votingInfo(101)).ToHeight)
+	consensusService.release <- struct{}{}
+	require.Eventually(t, func() bool {
+		return len(consensusService.fetchedHeights()) == 2
+	}, time.Second, 10*time.Millisecond)
+
+	// retry after failure
+	consensusService.mutex.Lock()
+	consensusService.err = nil
+	consensusService.mutex.Unlock()
+	require.Eventually(t, func() bool {
+		fetcher.mutex.Lock()
+		defer fetcher.mutex.Unlock()
+		return fetcher.requestedHeight == 0
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, int64(99), fetcher.offer(nil, votingInfo(101)).ToHeight)
+	consensusService.release <- struct{}{}
+	require.Eventually(t, func() bool {
+		return fetcher.offer(nil, votingInfo(101)).ToHeight == 100
+	}, time.Second, 10*time.Millisecond)
+
+	// no committed block yet
+	require.Equal(t, int64(100), fetcher.offer(nil, votingInfo(1)).ToHeight)
+}
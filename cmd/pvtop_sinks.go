@@ -0,0 +1,209 @@
+package cmd
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"fmt"
+	"github.com/bcdevtools/consvp/aos"
+	"github.com/bcdevtools/consvp/engine/broadcast_sink"
+	fsinki "github.com/bcdevtools/consvp/engine/broadcast_sink/file_sink_impl"
+	whsinki "github.com/bcdevtools/consvp/engine/broadcast_sink/webhook_sink_impl"
+	wssinki "github.com/bcdevtools/consvp/engine/broadcast_sink/websocket_sink_impl"
+	"github.com/bcdevtools/consvp/engine/broadcast_worker"
+	"github.com/bcdevtools/consvp/utils"
+	"github.com/spf13/cobra"
+	"strings"
+	"sync"
+)
+
+const (
+	flagWebhook            = "webhook"
+	flagSinkFile           = "sink-file"
+	flagSinkFileMaxSizeMb  = "sink-file-max-size-mb"
+	flagSinkFileMaxBackups = "sink-file-max-backups"
+	flagWebsocketListen    = "websocket-listen"
+)
+
+// readBroadcastSinks returns the broadcast sinks, other than the streaming one, enabled by flags.
+// Exit the app if any sink could not be initialized.
+func readBroadcastSinks(cmd *cobra.Command) []broadcast_sink.BroadcastSink {
+	var sinks []broadcast_sink.BroadcastSink
+
+	exitOnErr := func(err error, msg string) {
+		if err == nil {
+			return
+		}
+		for _, sink := range sinks {
+			_ = sink.Close()
+		}
+		utils.PrintlnStdErr("ERR: " + msg)
+		utils.PrintlnStdErr(err)
+		aos.Exit(1)
+	}
+
+	webhookUrls, _ := cmd.Flags().GetStringArray(flagWebhook)
+	for _, webhookUrl := range webhookUrls {
+		sink, err := whsinki.NewWebhookSink(strings.TrimSpace(webhookUrl), nil)
+		exitOnErr(err, "failed to initialize webhook sink "+utils.RedactUrl(webhookUrl))
+		sinks = append(sinks, sink)
+	}
+
+	if filePath, _ := cmd.Flags().GetString(flagSinkFile); len(filePath) > 0 {
+		maxSizeMb, _ := cmd.Flags().GetInt(flagSinkFileMaxSizeMb)
+		maxBackups, _ := cmd.Flags().GetInt(flagSinkFileMaxBackups)
+		sink, err := fsinki.NewFileSink(filePath, int64(maxSizeMb)*1024*1024, maxBackups)
+		exitOnErr(err, "failed to initialize file sink "+filePath)
+		sinks = append(sinks, sink)
+	}
+
+	if listenAddress, _ := cmd.Flags().GetString(flagWebsocketListen); len(listenAddress) > 0 {
+		sink, err := wssinki.NewWebsocketSink(listenAddress)
+		exitOnErr(err, "failed to initialize websocket sink "+listenAddress)
+		sinks = append(sinks, sink)
+	}
+
+	return sinks
+}
+
+// sinkBroadcaster broadcasts to multiple sinks concurrently, each sink is driven by its own broadcast worker
+// so errors and backoff of a sink do not affect the others.
+// Status of all the sinks is combined, one section per sink, and sent to the status channel.
+type sinkBroadcaster struct {
+	mutex *sync.Mutex
+
+	sinks        []broadcast_sink.BroadcastSink
+	workers      []*broadcast_worker.Worker
+	statuses     []string
+	statusChan   chan string
+	shutdownChan chan struct{}
+
+	resultObserver func(sinkName string, result broadcast_worker.Result) // optional
+}
+
+// sinkStatusFormatter formats the result of a broadcast attempt of a sink, to be displayed in the status panel.
+type sinkStatusFormatter func(broadcast_worker.Result) string
+
+func newSinkBroadcaster() *sinkBroadcaster {
+	return &sinkBroadcaster{
+		mutex:        &sync.Mutex{},
+		statusChan:   make(chan string),
+		shutdownChan: make(chan struct{}),
+	}
+}
+
+// AddSink registers the sink, with the optional formatter, default formatter is used if nil.
+// Must be called before Run.
+func (b *sinkBroadcaster) AddSink(sink broadcast_sink.BroadcastSink, optionalFormatter sinkStatusFormatter) {
+	formatter := optionalFormatter
+	if formatter == nil {
+		formatter = func(result broadcast_worker.Result) string {
+			return formatSinkBroadcastResult(sink, result)
+		}
+	}
+
+	sinkIndex := len(b.sinks)
+	b.sinks = append(b.sinks, sink)
+	b.statuses = append(b.statuses, sink.Name()+": waiting for data")
+	b.workers = append(b.workers, broadcast_worker.NewWorker(
+		sink.Broadcast,
+		func(result broadcast_worker.Result) {
+			if result.Stopped {
+				utils.StdHelper.PrintlnErr(fmt.Sprintf("broadcasting to %s stopped", sink.Name()), result.Err)
+			}
+			if b.resultObserver != nil {
+				b.resultObserver(sink.Name(), result)
+			}
+			b.updateStatus(sinkIndex, formatter(result))
+		},
+		0, 0, // default backoff
+	))
+}
+
+// SetResultObserver sets the observer to be notified the result of each broadcast attempt of every sink.
+// Must be called before Run.
+func (b *sinkBroadcaster) SetResultObserver(observer func(sinkName string, result broadcast_worker.Result)) {
+	b.resultObserver = observer
+}
+
+// HasSinks returns true if any sink has been registered.
+func (b *sinkBroadcaster) HasSinks() bool {
+	return len(b.sinks) > 0
+}
+
+// StatusChan returns the channel which the combined status is sent to.
+func (b *sinkBroadcaster) StatusChan() <-chan string {
+	return b.statusChan
+}
+
+// Run starts the broadcast workers in background.
+func (b *sinkBroadcaster) Run() {
+	for _, worker := range b.workers {
+		go worker.Run()
+	}
+}
+
+// Offer offers the content to all the sinks, never blocks.
+func (b *sinkBroadcaster) Offer(content interface{}) {
+	for _, worker := range b.workers {
+		worker.Offer(content)
+	}
+}
+
+// Shutdown stops the workers, closes the sinks and the status channel.
+func (b *sinkBroadcaster) Shutdown() {
+	close(b.shutdownChan) // release any pending status update
+
+	for _, worker := range b.workers {
+		worker.Stop()
+	}
+	for _, sink := range b.sinks {
+		_ = sink.Close()
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	close(b.statusChan)
+}
+
+func (b *sinkBroadcaster) updateStatus(sinkIndex int, status string) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	select {
+	case <-b.shutdownChan:
+		return
+	default:
+	}
+
+	b.statuses[sinkIndex] = status
+
+	select {
+	case b.statusChan <- strings.Join(b.statuses, "\n"):
+	case <-b.shutdownChan:
+	}
+}
+
+// formatSinkBroadcastResult is the default formatter of the result of a broadcast attempt of a sink.
+func formatSinkBroadcastResult(sink broadcast_sink.BroadcastSink, result broadcast_worker.Result) string {
+	var status string
+
+	if result.FetchingIssue {
+		status = symbols.paused + "paused temporary due to fetching issue"
+	} else if result.Stopped {
+		if result.Err == nil {
+			status = symbols.stopped + "stopped"
+		} else {
+			status = fmt.Sprintf("%sstopped: %s", symbols.stopped, result.Err)
+		}
+	} else if result.Err != nil {
+		status = fmt.Sprintf("%serr: %s", symbols.err, result.Err)
+	} else {
+		status = symbols.ok + "updated"
+	}
+
+	status = sink.Name() + ": " + status + "\n" + formatBroadcastStats(result.Stats)
+	if sinkStatus := sink.Status(); len(sinkStatus) > 0 {
+		status += ", " + sinkStatus
+	}
+
+	return status
+}
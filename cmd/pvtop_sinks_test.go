@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"fmt"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testSink struct {
+	name string
+	err  error
+}
+
+func (s *testSink) Name() string {
+	return s.name
+}
+
+func (s *testSink) Broadcast(*enginetypes.NextBlockVotingInformation) (error, bool) {
+	return s.err, false
+}
+
+func (s *testSink) Status() string {
+	return ""
+}
+
+func (s *testSink) Close() error {
+	return nil
+}
+
+func Test_sinkBroadcaster(t *testing.T) {
+	broadcaster := newSinkBroadcaster()
+	require.False(t, broadcaster.HasSinks())
+
+	broadcaster.AddSink(&testSink{name: "good"}, nil)
+	broadcaster.AddSink(&testSink{name: "bad", err: fmt.Errorf("boom")}, nil)
+	require.True(t, broadcaster.HasSinks())
+
+	broadcaster.Run()
+	broadcaster.Offer(&enginetypes.NextBlockVotingInformation{
+		HeightRoundStep: "1/0/1",
+	})
+
+	// each sink reports its own status, in the registration order
+	var status string
+	require.Eventually(t, func() bool {
+		select {
+		case status = <-broadcaster.StatusChan():
+		default:
+		}
+		return !strings.Contains(status, "waiting for data")
+	}, 5*time.Second, 10*time.Millisecond)
+
+	lines := strings.Split(status, "\n")
+	require.Len(t, lines, 4)
+	require.Equal(t, "good: 🟢 updated", lines[0])
+	require.True(t, strings.HasPrefix(lines[1], "sent 1,"), lines[1])
+	require.Equal(t, "bad: ❗err: boom", lines[2])
+	require.True(t, strings.Contains(lines[3], "failed 1"), lines[3])
+
+	broadcaster.Shutdown()
+	_, open := <-broadcaster.StatusChan()
+	require.False(t, open)
+}
@@ -1,6 +1,10 @@
 package cmd
 
-import "testing"
+import (
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"testing"
+	"time"
+)
 
 func Test_readPvTopArg(t *testing.T) {
 	type args struct {
@@ -98,3 +102,100 @@ func Test_readPvTopArg(t *testing.T) {
 		})
 	}
 }
+
+func Test_renderSignedBlocksSparkline(t *testing.T) {
+	tests := []struct {
+		name         string
+		signedBlocks []bool
+		width        int
+		want         string
+	}{
+		{
+			name:         "empty",
+			signedBlocks: nil,
+			width:        4,
+			want:         "    ",
+		},
+		{
+			name:         "less blocks than width, left padded",
+			signedBlocks: []bool{true, false},
+			width:        4,
+			want:         "  █▁",
+		},
+		{
+			name:         "blocks equals to width",
+			signedBlocks: []bool{true, true, false, true},
+			width:        4,
+			want:         "██▁█",
+		},
+		{
+			name:         "more blocks than width, grouped",
+			signedBlocks: []bool{true, true, false, false, true, false, true, true},
+			width:        4,
+			want:         "█▁▅█",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renderSignedBlocksSparkline(tt.signedBlocks, tt.width); got != tt.want {
+				t.Errorf("renderSignedBlocksSparkline() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_getDowntimeJailWarning(t *testing.T) {
+	voteState := func(signingInfo *enginetypes.LightValidatorSigningInfo) enginetypes.ValidatorVoteState {
+		return enginetypes.ValidatorVoteState{
+			Validator: enginetypes.LightValidator{
+				SigningInfo: signingInfo,
+			},
+		}
+	}
+
+	tests := []struct {
+		name       string
+		voteStates []enginetypes.ValidatorVoteState
+		want       string
+	}{
+		{
+			name: "no signing info",
+			voteStates: []enginetypes.ValidatorVoteState{
+				voteState(nil),
+			},
+			want: "",
+		},
+		{
+			name: "healthy",
+			voteStates: []enginetypes.ValidatorVoteState{
+				voteState(&enginetypes.LightValidatorSigningInfo{MissedBlocksCounter: 10, MaxMissedBlocks: 500}),
+			},
+			want: "",
+		},
+		{
+			name: "close to downtime jail",
+			voteStates: []enginetypes.ValidatorVoteState{
+				voteState(&enginetypes.LightValidatorSigningInfo{MissedBlocksCounter: 400, MaxMissedBlocks: 500}),
+				voteState(&enginetypes.LightValidatorSigningInfo{MissedBlocksCounter: 499, MaxMissedBlocks: 500}),
+				voteState(&enginetypes.LightValidatorSigningInfo{MissedBlocksCounter: 399, MaxMissedBlocks: 500}),
+			},
+			want: "⚠ 2 close to downtime jail",
+		},
+		{
+			name: "jailed or tombstoned",
+			voteStates: []enginetypes.ValidatorVoteState{
+				voteState(&enginetypes.LightValidatorSigningInfo{MissedBlocksCounter: 450, MaxMissedBlocks: 500, Tombstoned: true}),
+				voteState(&enginetypes.LightValidatorSigningInfo{JailedUntil: time.Now().Add(time.Hour)}),
+				voteState(&enginetypes.LightValidatorSigningInfo{MissedBlocksCounter: 450, MaxMissedBlocks: 500}),
+			},
+			want: "⚠ 1 close to downtime jail, 2 jailed/tombstoned",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getDowntimeJailWarning(tt.voteStates); got != tt.want {
+				t.Errorf("getDowntimeJailWarning() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
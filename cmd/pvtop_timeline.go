@@ -0,0 +1,45 @@
+package cmd
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"fmt"
+	"github.com/bcdevtools/consvp/engine/timeline"
+	"github.com/pkg/errors"
+	"os"
+	"time"
+)
+
+// maxTimelineEvents is the number of recent events kept for the timeline panel and exporting.
+const maxTimelineEvents = 1000
+
+// formatTimelineEvent returns a single line of the event to be rendered in the timeline panel.
+func formatTimelineEvent(event timeline.Event) string {
+	return fmt.Sprintf("%s %d/%d %s", event.Time.Local().Format("15:04:05.0"), event.Height, event.Round, event.Message)
+}
+
+// getChangedValidators returns the addresses of the validators those have any vote event.
+func getChangedValidators(events []timeline.Event) map[string]bool {
+	changed := make(map[string]bool)
+	for _, event := range events {
+		if len(event.Address) > 0 {
+			changed[event.Address] = true
+		}
+	}
+	return changed
+}
+
+// exportTimeline writes the recent events of the timeline as JSON lines into a new file in the working directory.
+func exportTimeline(tl *timeline.Timeline, chainId string, now time.Time) (filePath string, err error) {
+	filePath = fmt.Sprintf("cvp-timeline-%s-%s.jsonl", chainId, now.Format("20060102-150405"))
+
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to create file")
+	}
+
+	err = tl.Export(file)
+	if errClose := file.Close(); err == nil && errClose != nil {
+		err = errors.Wrap(errClose, "failed to close file")
+	}
+	return
+}
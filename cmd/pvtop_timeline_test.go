@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"github.com/bcdevtools/consvp/engine/timeline"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_formatTimelineEvent(t *testing.T) {
+	eventTime := time.Date(2024, 1, 1, 10, 20, 30, 450_000_000, time.Local)
+	require.Equal(t, "10:20:30.4 10/1 val0 prevoted BEEF at +2.1s", formatTimelineEvent(timeline.Event{
+		Time:    eventTime,
+		Height:  10,
+		Round:   1,
+		Message: "val0 prevoted BEEF at +2.1s",
+	}))
+}
+
+func Test_getChangedValidators(t *testing.T) {
+	changed := getChangedValidators([]timeline.Event{
+		{Type: timeline.EventTypeNewRound},
+		{Type: timeline.EventTypePreVote, Address: "A"},
+		{Type: timeline.EventTypePreCommit, Address: "A"},
+		{Type: timeline.EventTypePreVote, Address: "B"},
+	})
+	require.Equal(t, map[string]bool{"A": true, "B": true}, changed)
+
+	require.Empty(t, getChangedValidators(nil))
+}
+
+func Test_exportTimeline(t *testing.T) {
+	wd, err := os.Getwd()
+	require.NoError(t, err)
+	require.NoError(t, os.Chdir(t.TempDir()))
+	defer func() {
+		_ = os.Chdir(wd)
+	}()
+
+	now := time.Date(2024, 1, 1, 10, 20, 30, 0, time.Local)
+
+	tl := timeline.NewTimeline(10)
+	filePath, err := exportTimeline(tl, "chain-1", now)
+	require.NoError(t, err)
+	require.Equal(t, "cvp-timeline-chain-1-20240101-102030.jsonl", filepath.Base(filePath))
+
+	content, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	require.Empty(t, strings.TrimSpace(string(content)))
+
+	_, err = exportTimeline(tl, "chain-1", now)
+	require.Error(t, err, "must not overwrite existing file")
+}
@@ -0,0 +1,191 @@
+package cmd
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"fmt"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// sortOrder is the order of the validator rows on terminal UI.
+type sortOrder int
+
+const (
+	sortByVotingPower sortOrder = iota
+	sortByMoniker
+	sortByIndex
+	sortByVoteStatus
+	sortByLatency
+	sortOrdersCount
+)
+
+func (o sortOrder) String() string {
+	switch o {
+	case sortByVotingPower:
+		return "VP"
+	case sortByMoniker:
+		return "moniker"
+	case sortByIndex:
+		return "order"
+	case sortByVoteStatus:
+		return "vote status"
+	case sortByLatency:
+		return "pre-vote latency"
+	default:
+		return "unknown"
+	}
+}
+
+// tableView holds the interactive sorting, filtering and searching state of the validator rows on terminal UI.
+//
+// Key bindings:
+//   - 's' cycles the sort order.
+//   - 'p' toggles showing only validators missing pre-vote.
+//   - 'c' toggles showing only validators missing pre-commit.
+//   - '/' starts searching by moniker or address, 'Enter' to finish typing, 'Escape' to clear.
+type tableView struct {
+	sortOrder            sortOrder
+	onlyMissingPreVote   bool
+	onlyMissingPreCommit bool
+
+	// searching is true while typing the search query, all key events are captured.
+	searching   bool
+	searchQuery string
+}
+
+// handleKeyEvent handles the key event, returns true if the event is consumed and the view changed.
+func (v *tableView) handleKeyEvent(eventId string) bool {
+	if v.searching {
+		switch eventId {
+		case "<Enter>":
+			v.searching = false
+		case "<Escape>":
+			v.searching = false
+			v.searchQuery = ""
+		case "<Backspace>", "<C-<Backspace>>":
+			if len(v.searchQuery) > 0 {
+				_, size := utf8.DecodeLastRuneInString(v.searchQuery)
+				v.searchQuery = v.searchQuery[:len(v.searchQuery)-size]
+			}
+		case "<Space>":
+			v.searchQuery += " "
+		case "<C-c>":
+			return false // let the app exit
+		default:
+			if utf8.RuneCountInString(eventId) != 1 {
+				return true // ignore non-printable keys while typing
+			}
+			v.searchQuery += eventId
+		}
+		return true
+	}
+
+	switch eventId {
+	case "s":
+		v.sortOrder = (v.sortOrder + 1) % sortOrdersCount
+	case "p":
+		v.onlyMissingPreVote = !v.onlyMissingPreVote
+	case "c":
+		v.onlyMissingPreCommit = !v.onlyMissingPreCommit
+	case "/":
+		v.searching = true
+	case "<Escape>":
+		if len(v.searchQuery) < 1 {
+			return false
+		}
+		v.searchQuery = ""
+	default:
+		return false
+	}
+
+	return true
+}
+
+// apply returns the vote states filtered and sorted by the view. The input must be sorted descending by voting power.
+func (v *tableView) apply(voteStates []enginetypes.ValidatorVoteState, roundStartTime time.Time) []enginetypes.ValidatorVoteState {
+	var result []enginetypes.ValidatorVoteState
+
+	query := strings.ToLower(strings.TrimSpace(v.searchQuery))
+	for _, voteState := range voteStates {
+		if v.onlyMissingPreVote && voteState.PreVoted {
+			continue
+		}
+		if v.onlyMissingPreCommit && voteState.PreCommitVoted {
+			continue
+		}
+		if len(query) > 0 &&
+			!strings.Contains(strings.ToLower(voteState.Validator.Moniker), query) &&
+			!strings.Contains(strings.ToLower(voteState.Validator.Address), query) {
+			continue
+		}
+		result = append(result, voteState)
+	}
+
+	switch v.sortOrder {
+	case sortByMoniker:
+		sort.SliceStable(result, func(i, j int) bool {
+			return strings.ToLower(strings.TrimSpace(result[i].Validator.Moniker)) < strings.ToLower(strings.TrimSpace(result[j].Validator.Moniker))
+		})
+	case sortByIndex:
+		sort.SliceStable(result, func(i, j int) bool {
+			return result[i].Validator.Index < result[j].Validator.Index
+		})
+	case sortByVoteStatus:
+		// missing first, then voted nil, then voted
+		rank := func(voteState enginetypes.ValidatorVoteState) int {
+			if !voteState.PreVoted {
+				return 0
+			}
+			if voteState.VotedZeroes {
+				return 1
+			}
+			if !voteState.PreCommitVoted {
+				return 2
+			}
+			return 3
+		}
+		sort.SliceStable(result, func(i, j int) bool {
+			return rank(result[i]) < rank(result[j])
+		})
+	case sortByLatency:
+		// fastest first, not pre-voted or unknown latency last
+		latency := func(voteState enginetypes.ValidatorVoteState) time.Duration {
+			if !voteState.PreVoted || voteState.PreVoteTime.IsZero() {
+				return time.Duration(1<<63 - 1)
+			}
+			return voteState.PreVoteTime.Sub(roundStartTime)
+		}
+		sort.SliceStable(result, func(i, j int) bool {
+			return latency(result[i]) < latency(result[j])
+		})
+	default:
+		// already sorted by voting power
+	}
+
+	return result
+}
+
+// describe returns a short description of the view, to be displayed as the title of the validator table.
+func (v *tableView) describe(shownCount, totalCount int) string {
+	parts := []string{fmt.Sprintf("sort: %s [s]", v.sortOrder)}
+	if v.onlyMissingPreVote {
+		parts = append(parts, "missing pre-vote [p]")
+	}
+	if v.onlyMissingPreCommit {
+		parts = append(parts, "missing pre-commit [c]")
+	}
+	if v.searching {
+		parts = append(parts, fmt.Sprintf("search: %s_", v.searchQuery))
+	} else if len(v.searchQuery) > 0 {
+		parts = append(parts, fmt.Sprintf("search: %s [Esc]", v.searchQuery))
+	} else {
+		parts = append(parts, "[/] search")
+	}
+	if shownCount != totalCount {
+		parts = append(parts, fmt.Sprintf("%d/%d shown", shownCount, totalCount))
+	}
+	return " " + strings.Join(parts, " | ") + " "
+}
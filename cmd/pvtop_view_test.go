@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func Test_tableView(t *testing.T) {
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// sorted descending by voting power
+	voteStates := []enginetypes.ValidatorVoteState{
+		{Validator: enginetypes.LightValidator{Index: 2, Moniker: "Charlie", Address: "CCCC"}, PreVoted: true, PreCommitVoted: true, PreVoteTime: startTime.Add(3 * time.Second)},
+		{Validator: enginetypes.LightValidator{Index: 0, Moniker: "alpha", Address: "AAAA"}},
+		{Validator: enginetypes.LightValidator{Index: 3, Moniker: "Delta", Address: "DDDD"}, PreVoted: true, VotedZeroes: true, PreVoteTime: startTime.Add(1 * time.Second)},
+		{Validator: enginetypes.LightValidator{Index: 1, Moniker: "bravo", Address: "BBBB"}, PreVoted: true, PreVoteTime: startTime.Add(2 * time.Second)},
+	}
+
+	monikersOf := func(voteStates []enginetypes.ValidatorVoteState) []string {
+		var monikers []string
+		for _, voteState := range voteStates {
+			monikers = append(monikers, voteState.Validator.Moniker)
+		}
+		return monikers
+	}
+
+	view := &tableView{}
+	require.Equal(t, []string{"Charlie", "alpha", "Delta", "bravo"}, monikersOf(view.apply(voteStates, startTime)))
+
+	expectedBySortOrder := map[sortOrder][]string{
+		sortByMoniker:    {"alpha", "bravo", "Charlie", "Delta"},
+		sortByIndex:      {"alpha", "bravo", "Charlie", "Delta"},
+		sortByVoteStatus: {"alpha", "Delta", "bravo", "Charlie"},
+		sortByLatency:    {"Delta", "bravo", "Charlie", "alpha"},
+	}
+	for i := 1; i < int(sortOrdersCount); i++ {
+		require.True(t, view.handleKeyEvent("s"))
+		require.Equal(t, expectedBySortOrder[view.sortOrder], monikersOf(view.apply(voteStates, startTime)), view.sortOrder.String())
+	}
+	require.True(t, view.handleKeyEvent("s"))
+	require.Equal(t, sortByVotingPower, view.sortOrder)
+
+	// filters
+	require.True(t, view.handleKeyEvent("p"))
+	require.Equal(t, []string{"alpha"}, monikersOf(view.apply(voteStates, startTime)))
+	require.True(t, view.handleKeyEvent("p"))
+	require.True(t, view.handleKeyEvent("c"))
+	require.Equal(t, []string{"alpha", "Delta", "bravo"}, monikersOf(view.apply(voteStates, startTime)))
+	require.Contains(t, view.describe(3, 4), "3/4 shown")
+	require.True(t, view.handleKeyEvent("c"))
+
+	// search, keys are captured while typing
+	require.True(t, view.handleKeyEvent("/"))
+	for _, key := range []string{"D", "<Backspace>", "b", "b"} {
+		require.True(t, view.handleKeyEvent(key))
+	}
+	require.Equal(t, []string{"bravo"}, monikersOf(view.apply(voteStates, startTime)), "matches address")
+	require.True(t, view.handleKeyEvent("s"), "typing, not sorting")
+	require.Equal(t, "bbs", view.searchQuery)
+	require.Equal(t, sortByVotingPower, view.sortOrder)
+	require.False(t, view.handleKeyEvent("<C-c>"), "must not capture exit key")
+
+	require.True(t, view.handleKeyEvent("<Backspace>"))
+	require.True(t, view.handleKeyEvent("<Enter>"))
+	require.False(t, view.searching)
+	require.Contains(t, view.describe(1, 4), "search: bb")
+	require.False(t, view.handleKeyEvent("q"), "not typing anymore")
+
+	require.True(t, view.handleKeyEvent("<Escape>"))
+	require.Empty(t, view.searchQuery)
+	require.False(t, view.handleKeyEvent("<Escape>"))
+
+	require.True(t, view.handleKeyEvent("/"))
+	require.True(t, view.handleKeyEvent("a"))
+	require.True(t, view.handleKeyEvent("<Escape>"))
+	require.False(t, view.searching)
+	require.Empty(t, view.searchQuery)
+}
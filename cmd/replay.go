@@ -0,0 +1,155 @@
+package cmd
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"fmt"
+	"github.com/bcdevtools/consvp/aos"
+	"github.com/bcdevtools/consvp/constants"
+	"github.com/bcdevtools/consvp/engine/consensus_service/replay_conss_impl"
+	"github.com/bcdevtools/consvp/engine/recording"
+	"github.com/bcdevtools/consvp/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"time"
+)
+
+const flagSpeed = "speed"
+
+const (
+	replayTickInterval = 250 * time.Millisecond
+	replaySeekStep     = 10 * time.Second
+)
+
+// GetReplayCommand returns the command to replay a session recorded using the '--record' flag.
+func GetReplayCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "replay [recording file]",
+		Short: "Replay a session recorded using the '--record' flag",
+		Long: `Replay a session recorded using the '--record' flag.
+Key bindings on terminal UI:
+- Space: pause/resume
+- +/-: speed up/slow down
+- Left/Right: seek backward/forward 10 seconds
+`,
+		Args: cobra.ExactArgs(1),
+		Run:  replayHandler,
+	}
+
+	cmd.Flags().Float64(flagSpeed, 1, "playback speed, for example 2 for double speed or 0.5 for half speed.")
+
+	return cmd
+}
+
+func replayHandler(cmd *cobra.Command, args []string) {
+	outputMode := readOutputMode(cmd)
+	readRenderingStyle(cmd)
+
+	rec, err := recording.LoadRecording(args[0])
+	if err != nil {
+		utils.PrintlnStdErr("ERR: failed to load recording")
+		utils.PrintlnStdErr(err)
+		aos.Exit(1)
+	}
+
+	speed, _ := cmd.Flags().GetFloat64(flagSpeed)
+	if speed <= 0 {
+		utils.PrintlnStdErr("ERR: speed must be positive")
+		aos.Exit(1)
+	}
+
+	printlnInfo(constants.APP_INTRO)
+	printlnInfo("Replaying", args[0])
+	printlnInfo("Chain ID:", rec.Header.ChainId)
+
+	replayService := replay_conss_impl.NewReplayConsensusService(rec, speed)
+	utils.AppExitHelper.RegisterFuncUponAppExit(func() {
+		_ = replayService.Shutdown()
+	})
+
+	renderVotingInfoChan := make(chan interface{})
+	replayStatusChan := make(chan string)
+
+	startRendering(outputMode, screenOptions{
+		chainId:          rec.Header.ChainId,
+		consensusVersion: rec.Header.ConsensusVersion,
+		moniker:          rec.Header.Moniker,
+		statusPanelTitle: " Replay ",
+		keyEventHandler: func(eventId string) {
+			switch eventId {
+			case "<Space>":
+				replayService.TogglePause()
+			case "+", "=":
+				replayService.SpeedUp()
+			case "-", "_":
+				replayService.SlowDown()
+			case "<Left>":
+				replayService.Seek(-replaySeekStep)
+			case "<Right>":
+				replayService.Seek(replaySeekStep)
+			}
+		},
+	}, renderVotingInfoChan, replayStatusChan)
+
+	if outputMode != outputTui {
+		// plain text and machine-readable outputs print each record exactly once, at the recorded pace, then exit at the end
+		var recordsCount int
+		for {
+			record, wait, ok := replayService.NextRecord()
+			if !ok {
+				break
+			}
+
+			time.Sleep(wait)
+			renderReplayedRecord(renderVotingInfoChan, record)
+			recordsCount++
+		}
+
+		// give the renderer a chance to flush the last record
+		time.Sleep(replayTickInterval)
+		printlnInfo(fmt.Sprintf("Replay ended, %d records", recordsCount))
+		aos.Exit(0)
+	}
+
+	// the voting information is re-rendered every tick on terminal UI, so the duration of the round keeps increasing
+	ticker := time.NewTicker(replayTickInterval)
+	defer ticker.Stop()
+
+	for {
+		if record, ok := replayService.CurrentRecord(); ok {
+			renderReplayedRecord(renderVotingInfoChan, record)
+		} else {
+			renderVotingInfoChan <- fmt.Errorf("recording is empty")
+		}
+		replayStatusChan <- formatPlaybackStatus(replayService.Status())
+
+		<-ticker.C
+	}
+}
+
+// formatPlaybackStatus returns the human-readable playback status, rendered with the symbol set in use.
+func formatPlaybackStatus(status replay_conss_impl.PlaybackStatus) string {
+	var state string
+	switch status.State {
+	case replay_conss_impl.PlaybackStateEnded:
+		state = symbols.ended + "Ended"
+	case replay_conss_impl.PlaybackStatePaused:
+		state = symbols.playbackPaused + "Paused"
+	default:
+		state = symbols.playing + "Playing"
+	}
+
+	return fmt.Sprintf(
+		"%s %gx\n%s / %s",
+		state, status.Speed,
+		status.Position.Truncate(time.Second), status.Duration.Truncate(time.Second),
+	)
+}
+
+// renderReplayedRecord sends the recorded voting information or error to the renderer.
+func renderReplayedRecord(renderVotingInfoChan chan<- interface{}, record replay_conss_impl.ReplayedRecord) {
+	if record.Err != nil {
+		renderVotingInfoChan <- errors.Wrap(record.Err, "recorded")
+	} else {
+		renderVotingInfoChan <- record.VotingInfo
+	}
+}
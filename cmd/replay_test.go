@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"github.com/bcdevtools/consvp/engine/consensus_service/replay_conss_impl"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func Test_formatPlaybackStatus(t *testing.T) {
+	defer func() {
+		symbols = emojiSymbols
+	}()
+
+	status := replay_conss_impl.PlaybackStatus{
+		State:    replay_conss_impl.PlaybackStatePlaying,
+		Speed:    0.5,
+		Position: 61500 * time.Millisecond,
+		Duration: 2 * time.Hour,
+	}
+	require.Equal(t, "▶ Playing 0.5x\n1m1s / 2h0m0s", formatPlaybackStatus(status))
+
+	symbols = asciiSymbols
+	status.State = replay_conss_impl.PlaybackStatePaused
+	require.Equal(t, "[pause] Paused 0.5x\n1m1s / 2h0m0s", formatPlaybackStatus(status))
+
+	status.State = replay_conss_impl.PlaybackStateEnded
+	require.Equal(t, "[end] Ended 0.5x\n1m1s / 2h0m0s", formatPlaybackStatus(status))
+}
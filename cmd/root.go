@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"github.com/bcdevtools/consvp/aos"
 	"github.com/bcdevtools/consvp/constants"
+	fsinki "github.com/bcdevtools/consvp/engine/broadcast_sink/file_sink_impl"
+	pvssi "github.com/bcdevtools/consvp/engine/prevote_streaming_service/prevote_ss_impl"
 	corecodec "github.com/bcdevtools/cvp-streaming-core/codec"
+	coreconstants "github.com/bcdevtools/cvp-streaming-core/constants"
 	"github.com/spf13/cobra"
 )
 
@@ -21,20 +24,50 @@ If only a port is provided, the default host is localhost.
 			aos.Exit(0)
 		}
 	},
-	Run: pvtopHandler,
+	Args: cobra.ArbitraryArgs,
+	Run:  pvtopHandler,
 }
 
 func Execute() {
 	rootCmd.Flags().Bool(flagHttp, false, "use http call for rpc client instead of default is websocket")
 	rootCmd.Flags().BoolP(flagRapidRefresh, "r", false, fmt.Sprintf("refresh rate quicker, default is %v will be changed to %v", defaultRefreshInterval, rapidRefreshInterval))
 	rootCmd.Flags().BoolP(flagStreaming, "s", false, "open a live-streaming pre-vote session to be able to share the view with others.")
-	rootCmd.Flags().String(flagCodec, string(corecodec.NewProxyCvpCodec().GetVersion()), "specify codec version to be used to encode the streaming data, mostly used for testing purpose or workaround when the default codec version has bug.")
-	rootCmd.Flags().Bool(flagResumeStreaming, false, "resume an opened live-streaming pre-vote session to keep the current shared URL.")
+	rootCmd.Flags().String(flagCodec, "", fmt.Sprintf("specify codec version to be used to encode the streaming data, mostly used for testing purpose or workaround when the default codec version has bug. By default, codec version is negotiated with the streaming server, starting from the newest version %s then fallback to the older versions.", corecodec.NewProxyCvpCodec().GetVersion()))
+	rootCmd.Flags().Bool(flagResumeStreaming, false, "resume an opened live-streaming pre-vote session to keep the current shared URL. The latest valid session of the chain, saved when registered, is picked automatically.")
+	rootCmd.Flags().String(flagSessionId, "", fmt.Sprintf("resume the live-streaming pre-vote session with the given ID, implies --%s. Session key is taken from env %s or the saved sessions. Can also be provided via env %s.", flagResumeStreaming, constants.ENV_STREAMING_SESSION_KEY, constants.ENV_STREAMING_SESSION_ID))
+	rootCmd.Flags().Int(flagSignedBlocksWindow, 0, "number of recent committed blocks to be fetched via '/commit' to render the signing sparkline of each validator, 0 to disable.")
+	rootCmd.Flags().String(flagRecord, "", "record every consensus snapshot and the validator set into the given file, to be replayed later using the 'replay' command.")
+	rootCmd.PersistentFlags().StringP(flagOutput, "o", outputTui, fmt.Sprintf("output mode, '%s' for terminal UI, '%s' for line-oriented plain text or '%s' for JSON Lines. Automatically switch to '%s' when no TTY.", outputTui, outputPlain, outputJsonl, outputPlain))
+	rootCmd.PersistentFlags().String(flagSymbols, symbolsAuto, fmt.Sprintf("symbols to render votes and status, '%s' or '%s' for terminals can not render emoji properly. By default, '%s' is selected when the locale is not UTF-8 or the terminal is known to not support emoji.", symbolsEmoji, symbolsAscii, symbolsAscii))
+	rootCmd.PersistentFlags().String(flagTheme, themeAuto, fmt.Sprintf("color theme of the terminal UI: '%s', '%s' (avoid red/green) or '%s'. By default, '%s' is selected when env NO_COLOR is set or the terminal does not support colors.", themeDefault, themeColorblind, themeMono, themeMono))
+	rootCmd.Flags().StringSlice(flagWatchlist, nil, fmt.Sprintf("consensus addresses or monikers of the validators to be watched, pinned to the top and highlighted, always streamed when the validator set exceeds the streaming limit %d. Can also be set in the config file.", coreconstants.MAX_VALIDATORS))
+	rootCmd.Flags().Bool(flagNoAutoWatch, false, "do not add the validator of the RPC node, detected via '/status', into the watchlist.")
+	rootCmd.Flags().Duration(flagWatchMissingPreVoteAfter, defaultWatchMissingPreVoteAfter, "ring the terminal bell when any watched validator has not pre-voted after this duration into a round, 0 to disable.")
+	rootCmd.Flags().String(flagStreamingServer, "", fmt.Sprintf("base URL of the streaming server, default is %s. Can also be set in the config file.", coreconstants.STREAMING_BASE_URL))
+	rootCmd.Flags().Duration(flagStreamingTimeout, 0, fmt.Sprintf("timeout of each request to the streaming server, default is %v.", pvssi.DefaultHttpTimeout))
+	rootCmd.Flags().String(flagStreamingProxy, "", "HTTP proxy to connect to the streaming server, default is taken from environment variables HTTP_PROXY, HTTPS_PROXY and NO_PROXY.")
+	rootCmd.Flags().String(flagStreamingCaFile, "", "PEM-encoded certificate authority file to verify the streaming server, in addition to the system certificate pool.")
+	rootCmd.Flags().StringArray(flagStreamingHeader, nil, "custom header 'Name: Value' to be added to each request to the streaming server, can be repeated.")
+	rootCmd.Flags().StringArray(flagWebhook, nil, "URL of the webhook to be POSTed each snapshot as a JSON object of schema 'cvp/snapshot/v1', can be repeated.")
+	rootCmd.Flags().String(flagSinkFile, "", "path to the file to be appended each snapshot as a JSON line, rolled when exceeds the max size.")
+	rootCmd.Flags().Int(flagSinkFileMaxSizeMb, int(fsinki.DefaultMaxSize/1024/1024), fmt.Sprintf("maximum size in megabytes of the file of --%s before rolled.", flagSinkFile))
+	rootCmd.Flags().Int(flagSinkFileMaxBackups, fsinki.DefaultMaxBackups, fmt.Sprintf("maximum number of rolled files of --%s to be kept.", flagSinkFile))
+	rootCmd.Flags().String(flagLogFile, "", "path to the file to be mirrored every info, warning and error message with timestamp, rolled when exceeds the max size.")
+	rootCmd.Flags().Int(flagLogFileMaxSizeMb, defaultLogFileMaxSizeMb, fmt.Sprintf("maximum size in megabytes of the file of --%s before rolled.", flagLogFile))
+	rootCmd.Flags().Int(flagLogFileMaxBackups, defaultLogFileMaxBackups, fmt.Sprintf("maximum number of rolled files of --%s to be kept.", flagLogFile))
+	rootCmd.Flags().String(flagWebsocketListen, "", "address to serve a local websocket endpoint '/ws' sending each snapshot as a JSON object, with a viewer page for browsers at '/', eg: 'localhost:8081'.")
+	rootCmd.Flags().String(flagMetricsAddr, "", "address to serve Prometheus metrics of the consensus & vote state at '/metrics', eg: 'localhost:9100'.")
+	rootCmd.Flags().String(flagAlertWebhook, "", "URL of the webhook which alerts are POSTed to, alert rules are configured in the config file. Can also be set in the config file.")
+	rootCmd.Flags().String(flagConfig, "", "path to the config file, default is ~/.cvp/config.json if exists.")
 	rootCmd.Flags().StringP(flagMockStreamingServer, "t", "none", "for testing purpose only, mock a streaming server or connect to local streaming server to test the streaming client.")
 
 	rootCmd.Flags().BoolP(flagVersion, "v", false, "print the binary version. WARN: This action will bypass the main command handler.")
 	rootCmd.Flags().Bool(flagLongVersion, false, fmt.Sprintf("print extra version information, must be used with --%s", flagVersion))
 
+	rootCmd.AddCommand(GetReplayCommand())
+	rootCmd.AddCommand(GetInspectCommand())
+	rootCmd.AddCommand(GetServeCommand())
+
 	rootCmd.CompletionOptions.HiddenDefaultCmd = true    // hide the 'completion' subcommand
 	rootCmd.SetHelpCommand(&cobra.Command{Hidden: true}) // hide the 'help' subcommand
 
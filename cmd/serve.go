@@ -0,0 +1,89 @@
+package cmd
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"context"
+	"fmt"
+	"github.com/bcdevtools/consvp/aos"
+	"github.com/bcdevtools/consvp/engine/streaming_server"
+	"github.com/bcdevtools/consvp/utils"
+	"github.com/spf13/cobra"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+const (
+	flagListen          = "listen"
+	flagSessionDuration = "session-duration"
+	flagMaxSessions     = "max-sessions"
+)
+
+// GetServeCommand returns the command to run a self-hosted streaming server.
+func GetServeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Run a self-hosted streaming server, for private pre-vote streaming",
+		Long: `Run a self-hosted streaming server, for private pre-vote streaming without any third party.
+Sessions are held in memory, they will be lost when the server restarts.
+With the default listen address, the streaming client can connect using '--mock-streaming-server local'.
+`,
+		Args: cobra.NoArgs,
+		Run:  serveHandler,
+	}
+
+	cmd.Flags().String(flagListen, ":8080", "address to listen on.")
+	cmd.Flags().Duration(flagSessionDuration, streaming_server.DefaultSessionDuration, "duration of a streaming session, since registered.")
+	cmd.Flags().Int(flagMaxSessions, streaming_server.DefaultMaxSessions, "maximum number of sessions can be held at the same time.")
+
+	return cmd
+}
+
+func serveHandler(cmd *cobra.Command, _ []string) {
+	listenAddress, _ := cmd.Flags().GetString(flagListen)
+	sessionDuration, _ := cmd.Flags().GetDuration(flagSessionDuration)
+	maxSessions, _ := cmd.Flags().GetInt(flagMaxSessions)
+
+	if sessionDuration <= 0 {
+		utils.PrintlnStdErr("ERR: session duration must be positive")
+		aos.Exit(1)
+	}
+	if maxSessions < 1 {
+		utils.PrintlnStdErr("ERR: max sessions must be positive")
+		aos.Exit(1)
+	}
+
+	streamingServer := streaming_server.NewStreamingServer(sessionDuration, maxSessions)
+
+	httpServer := &http.Server{
+		Addr:              listenAddress,
+		Handler:           streamingServer.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+	}
+
+	utils.AppExitHelper.RegisterFuncUponAppExit(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = httpServer.Shutdown(ctx)
+	})
+
+	signalChan := make(chan os.Signal, 1)
+	signal.Notify(signalChan, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signalChan
+		aos.Exit(0)
+	}()
+
+	fmt.Println("Streaming server is listening on", listenAddress)
+	fmt.Printf("Session duration: %v, max sessions: %d\n", sessionDuration, maxSessions)
+
+	if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		utils.PrintlnStdErr("ERR: streaming server stopped")
+		utils.PrintlnStdErr(err)
+		aos.Exit(1)
+	}
+}
@@ -0,0 +1,306 @@
+package cmd
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"fmt"
+	"github.com/bcdevtools/consvp/aos"
+	"github.com/bcdevtools/consvp/utils"
+	ui "github.com/gizak/termui/v3"
+	"github.com/spf13/cobra"
+	"os"
+	"strings"
+)
+
+const (
+	flagSymbols = "symbols"
+	flagTheme   = "theme"
+)
+
+const (
+	symbolsAuto  = "auto"
+	symbolsEmoji = "emoji"
+	symbolsAscii = "ascii"
+)
+
+const (
+	themeAuto       = "auto"
+	themeDefault    = "default"
+	themeColorblind = "colorblind"
+	themeMono       = "mono"
+)
+
+// symbolSet is the set of symbols used to render the voting information and status.
+type symbolSet struct {
+	voted    string // voted for a block
+	votedNil string // voted for nil (zeroes block hash)
+	missing  string // not voted
+
+	ok      string
+	stopped string
+	paused  string
+	err     string
+	bell    string
+	warning string
+
+	scrollUp   string
+	scrollDown string
+
+	// playback state of replay
+	playing        string
+	playbackPaused string
+	ended          string
+
+	// styled is true if the vote symbols are styled by the theme, emoji are colored already.
+	styled bool
+
+	// chartDot is the character to draw the history chart, zero to use braille.
+	chartDot rune
+
+	// sparklineLevels is the characters of the sparkline, from the lowest to the highest.
+	sparklineLevels []rune
+}
+
+var emojiSymbols = symbolSet{
+	voted:    "✅",
+	votedNil: "🤷",
+	missing:  "❌",
+
+	ok:      "🟢 ",
+	stopped: "🔴 ",
+	paused:  "💢 ",
+	err:     "❗",
+	bell:    "🔔 ",
+	warning: "⚠ ",
+
+	scrollUp:   "↑",
+	scrollDown: "↓",
+
+	playing:        "▶ ",
+	playbackPaused: "⏸ ",
+	ended:          "⏹ ",
+
+	sparklineLevels: []rune("▁▂▃▄▅▆▇█"),
+}
+
+// asciiSymbols is used when emoji can not be rendered properly, colors are provided by the theme instead.
+var asciiSymbols = symbolSet{
+	voted:    "+",
+	votedNil: "0",
+	missing:  "x",
+
+	ok:      "[ok] ",
+	stopped: "[stop] ",
+	paused:  "[pause] ",
+	err:     "[!] ",
+	bell:    "[!] ",
+	warning: "[!] ",
+
+	scrollUp:   "^",
+	scrollDown: "v",
+
+	playing:        "[play] ",
+	playbackPaused: "[pause] ",
+	ended:          "[end] ",
+
+	styled:   true,
+	chartDot: '*',
+
+	sparklineLevels: []rune("_.,-~=*#"),
+}
+
+// colorTheme is the set of styles used to render the validator rows on terminal UI,
+// each style uses the termui syntax, eg: 'fg:red,mod:bold', empty for the default style.
+type colorTheme struct {
+	voted    string
+	votedNil string
+	missing  string
+
+	jailed      string // jailed or tombstoned
+	closeToJail string // close to the downtime jail threshold
+	watched     string // in the watchlist, applied when none of the above
+	watchedMod  string // modifier added to the style of validators in the watchlist, empty if not compatible
+
+	chartPreVote   ui.Color
+	chartPreCommit ui.Color
+
+	changed string // block hash of validators those have new votes since the previous refresh
+
+	logWarn  string // severity level of warning messages in the log panel
+	logError string // severity level of error messages in the log panel
+}
+
+var defaultTheme = colorTheme{
+	voted:    "fg:green",
+	votedNil: "fg:yellow",
+	missing:  "fg:red",
+
+	jailed:      "fg:red",
+	closeToJail: "fg:yellow",
+	watched:     "fg:cyan,mod:bold",
+	watchedMod:  "mod:bold",
+
+	chartPreVote:   ui.ColorGreen,
+	chartPreCommit: ui.ColorCyan,
+
+	changed: "fg:black,bg:green",
+
+	logWarn:  "fg:yellow",
+	logError: "fg:red,mod:bold",
+}
+
+// colorblindTheme avoids red/green distinction, using blue/yellow/magenta instead.
+var colorblindTheme = colorTheme{
+	voted:    "fg:blue",
+	votedNil: "fg:cyan",
+	missing:  "fg:yellow,mod:bold",
+
+	jailed:      "fg:magenta",
+	closeToJail: "fg:yellow",
+	watched:     "fg:cyan,mod:bold",
+	watchedMod:  "mod:bold",
+
+	chartPreVote:   ui.ColorBlue,
+	chartPreCommit: ui.ColorYellow,
+
+	changed: "fg:black,bg:cyan",
+
+	logWarn:  "fg:yellow",
+	logError: "fg:magenta,mod:bold",
+}
+
+// monoTheme uses modifiers only.
+var monoTheme = colorTheme{
+	missing: "mod:bold",
+
+	jailed:      "mod:reverse",
+	closeToJail: "mod:underline",
+	watched:     "mod:bold",
+
+	chartPreVote:   ui.ColorWhite,
+	chartPreCommit: ui.ColorWhite,
+
+	changed: "mod:underline",
+
+	logError: "mod:bold",
+}
+
+var (
+	// symbols is the symbol set in use, selected by readRenderingStyle.
+	symbols = emojiSymbols
+	// theme is the color theme in use, selected by readRenderingStyle.
+	theme = defaultTheme
+)
+
+// readRenderingStyle selects the symbol set and the color theme from flags,
+// automatically based on the 'TERM' and locale environment variables if not specified.
+func readRenderingStyle(cmd *cobra.Command) {
+	symbolsName, _ := cmd.Flags().GetString(flagSymbols)
+	symbolsName = strings.ToLower(strings.TrimSpace(symbolsName))
+	if symbolsName == symbolsAuto {
+		symbolsName = detectSymbols(os.Getenv)
+	}
+	switch symbolsName {
+	case symbolsEmoji:
+		symbols = emojiSymbols
+	case symbolsAscii:
+		symbols = asciiSymbols
+	default:
+		utils.PrintlnStdErr("ERR: bad symbols: " + symbolsName)
+		aos.Exit(1)
+	}
+
+	themeName, _ := cmd.Flags().GetString(flagTheme)
+	themeName = strings.ToLower(strings.TrimSpace(themeName))
+	if themeName == themeAuto {
+		themeName = detectTheme(os.Getenv)
+	}
+	switch themeName {
+	case themeDefault:
+		theme = defaultTheme
+	case themeColorblind:
+		theme = colorblindTheme
+	case themeMono:
+		theme = monoTheme
+	default:
+		utils.PrintlnStdErr("ERR: bad theme: " + themeName)
+		aos.Exit(1)
+	}
+}
+
+// detectSymbols returns ASCII symbols for the terminals known to not render emoji, or when the locale is not UTF-8.
+// Emoji is used when the locale is not set.
+func detectSymbols(getEnv func(string) string) string {
+	switch getEnv("TERM") {
+	case "dumb", "linux", "vt100", "vt102", "vt220", "cons25":
+		return symbolsAscii
+	}
+
+	// the first non-empty one takes effect, following POSIX locale precedence
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if locale := getEnv(name); len(locale) > 0 {
+			locale = strings.ToLower(locale)
+			if strings.Contains(locale, "utf-8") || strings.Contains(locale, "utf8") {
+				return symbolsEmoji
+			}
+			return symbolsAscii
+		}
+	}
+
+	return symbolsEmoji
+}
+
+// detectTheme returns monochrome theme when 'NO_COLOR' is set (https://no-color.org) or the terminal does not support colors.
+func detectTheme(getEnv func(string) string) string {
+	if len(getEnv("NO_COLOR")) > 0 {
+		return themeMono
+	}
+
+	switch getEnv("TERM") {
+	case "dumb", "vt100", "vt102", "vt220":
+		return themeMono
+	}
+
+	return themeDefault
+}
+
+// renderVote returns the symbol of the vote, padded to the same width as emoji, styled by the theme in ASCII mode.
+func renderVote(voted, votedNil bool) string {
+	var symbol, style string
+	if votedNil {
+		symbol, style = symbols.votedNil, theme.votedNil
+	} else if voted {
+		symbol, style = symbols.voted, theme.voted
+	} else {
+		symbol, style = symbols.missing, theme.missing
+	}
+
+	if !symbols.styled {
+		return symbol + " "
+	}
+
+	symbol = fmt.Sprintf("%-2s ", symbol)
+	if len(style) > 0 {
+		symbol = fmt.Sprintf("[%s](%s)", symbol, style)
+	}
+	return symbol
+}
+
+// getMonikerStyle returns the style of the moniker, empty for the default style.
+func getMonikerStyle(jailed, closeToJail, watched bool) string {
+	var style string
+	if jailed {
+		style = theme.jailed
+	} else if closeToJail {
+		style = theme.closeToJail
+	} else if watched {
+		return theme.watched
+	}
+
+	if watched && len(theme.watchedMod) > 0 {
+		if len(style) > 0 {
+			style += ","
+		}
+		style += theme.watchedMod
+	}
+	return style
+}
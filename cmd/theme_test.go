@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func Test_detectSymbols(t *testing.T) {
+	envOf := func(env map[string]string) func(string) string {
+		return func(name string) string {
+			return env[name]
+		}
+	}
+
+	require.Equal(t, symbolsEmoji, detectSymbols(envOf(nil)))
+	require.Equal(t, symbolsEmoji, detectSymbols(envOf(map[string]string{"TERM": "xterm-256color", "LANG": "en_US.UTF-8"})))
+	require.Equal(t, symbolsEmoji, detectSymbols(envOf(map[string]string{"LC_CTYPE": "C.utf8", "LANG": "C"})))
+	require.Equal(t, symbolsAscii, detectSymbols(envOf(map[string]string{"TERM": "linux", "LANG": "en_US.UTF-8"})))
+	require.Equal(t, symbolsAscii, detectSymbols(envOf(map[string]string{"LANG": "C"})))
+	require.Equal(t, symbolsAscii, detectSymbols(envOf(map[string]string{"LC_ALL": "POSIX", "LANG": "en_US.UTF-8"})))
+}
+
+func Test_detectTheme(t *testing.T) {
+	envOf := func(env map[string]string) func(string) string {
+		return func(name string) string {
+			return env[name]
+		}
+	}
+
+	require.Equal(t, themeDefault, detectTheme(envOf(nil)))
+	require.Equal(t, themeDefault, detectTheme(envOf(map[string]string{"TERM": "linux"})))
+	require.Equal(t, themeMono, detectTheme(envOf(map[string]string{"TERM": "xterm", "NO_COLOR": "1"})))
+	require.Equal(t, themeMono, detectTheme(envOf(map[string]string{"TERM": "vt100"})))
+}
+
+func Test_renderVote(t *testing.T) {
+	defer func() {
+		symbols, theme = emojiSymbols, defaultTheme
+	}()
+
+	require.Equal(t, "✅ ", renderVote(true, false))
+	require.Equal(t, "🤷 ", renderVote(true, true))
+	require.Equal(t, "❌ ", renderVote(false, false))
+
+	symbols = asciiSymbols
+	require.Equal(t, "[+  ](fg:green)", renderVote(true, false))
+	require.Equal(t, "[0  ](fg:yellow)", renderVote(true, true))
+	require.Equal(t, "[x  ](fg:red)", renderVote(false, false))
+
+	theme = monoTheme
+	require.Equal(t, "+  ", renderVote(true, false))
+	require.Equal(t, "[x  ](mod:bold)", renderVote(false, false))
+}
+
+func Test_getMonikerStyle(t *testing.T) {
+	defer func() {
+		theme = defaultTheme
+	}()
+
+	require.Empty(t, getMonikerStyle(false, false, false))
+	require.Equal(t, "fg:red", getMonikerStyle(true, false, false))
+	require.Equal(t, "fg:red,mod:bold", getMonikerStyle(true, false, true))
+	require.Equal(t, "fg:yellow,mod:bold", getMonikerStyle(false, true, true))
+	require.Equal(t, "fg:cyan,mod:bold", getMonikerStyle(false, false, true))
+
+	theme = monoTheme
+	require.Equal(t, "mod:reverse", getMonikerStyle(true, false, true))
+	require.Equal(t, "mod:bold", getMonikerStyle(false, false, true))
+}
@@ -0,0 +1,106 @@
+package cmd
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"fmt"
+	"github.com/bcdevtools/consvp/engine/rpc_client"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	flagNoAutoWatch              = "no-auto-watch"
+	flagWatchMissingPreVoteAfter = "watch-missing-prevote-after"
+)
+
+// defaultWatchMissingPreVoteAfter is the default duration into a round, after which the watched validators
+// those have not pre-voted are alerted.
+const defaultWatchMissingPreVoteAfter = 10 * time.Second
+
+// detectOwnValidatorAddress returns the consensus address of the validator run by the RPC node, via '/status'.
+// Returns empty if the node is not a validator, or failed to detect.
+func detectOwnValidatorAddress(rpcClient rpc_client.RpcClient) string {
+	status, err := rpcClient.Status()
+	if err != nil || status == nil {
+		return ""
+	}
+
+	if status.ValidatorInfo.VotingPower < 1 || len(status.ValidatorInfo.Address) < 1 {
+		return "" // full node
+	}
+
+	return strings.ToUpper(status.ValidatorInfo.Address.String())
+}
+
+// pinWatchedValidators returns the vote states with the watched validators moved to the top,
+// keeping the original order of each group.
+func pinWatchedValidators(voteStates []enginetypes.ValidatorVoteState, watchlist enginetypes.Watchlist) []enginetypes.ValidatorVoteState {
+	if len(watchlist) < 1 {
+		return voteStates
+	}
+
+	pinned := append([]enginetypes.ValidatorVoteState{}, voteStates...)
+	sort.SliceStable(pinned, func(i, j int) bool {
+		return watchlist.Contains(pinned[i].Validator) && !watchlist.Contains(pinned[j].Validator)
+	})
+	return pinned
+}
+
+// watchedMissingPreVoteMonitor detects the watched validators those have not pre-voted after a duration into a round.
+// Each validator is alerted at most once per height/round.
+type watchedMissingPreVoteMonitor struct {
+	watchlist enginetypes.Watchlist
+	after     time.Duration
+
+	heightRoundStep string
+	alerted         map[string]bool // by validator address, within the current height/round
+}
+
+// newWatchedMissingPreVoteMonitor returns a new monitor, it detects nothing if the watchlist is empty or duration is not positive.
+func newWatchedMissingPreVoteMonitor(watchlist enginetypes.Watchlist, after time.Duration) *watchedMissingPreVoteMonitor {
+	return &watchedMissingPreVoteMonitor{
+		watchlist: watchlist,
+		after:     after,
+		alerted:   make(map[string]bool),
+	}
+}
+
+// check returns the warning message of the watched validators those have not pre-voted after the duration into the round,
+// and shouldAlert is true when any of them has not been alerted in this round.
+func (m *watchedMissingPreVoteMonitor) check(votingInfo *enginetypes.NextBlockVotingInformation, now time.Time) (warning string, shouldAlert bool) {
+	if len(m.watchlist) < 1 || m.after <= 0 {
+		return "", false
+	}
+
+	heightRound := votingInfo.HeightRoundStep[:strings.LastIndex(votingInfo.HeightRoundStep, "/")+1]
+	if heightRound != m.heightRoundStep {
+		m.heightRoundStep = heightRound
+		m.alerted = make(map[string]bool)
+	}
+
+	duration := now.UTC().Sub(votingInfo.StartTimeUTC)
+	if duration < m.after {
+		return "", false
+	}
+
+	var monikers []string
+	for _, voteState := range votingInfo.SortedValidatorVoteStates {
+		if voteState.PreVoted || !m.watchlist.Contains(voteState.Validator) {
+			continue
+		}
+
+		monikers = append(monikers, voteState.Validator.Moniker)
+		if !m.alerted[voteState.Validator.Address] {
+			m.alerted[voteState.Validator.Address] = true
+			shouldAlert = true
+		}
+	}
+
+	if len(monikers) < 1 {
+		return "", false
+	}
+
+	return fmt.Sprintf("%snot pre-voted after %v: %s", symbols.bell, duration.Truncate(time.Second), strings.Join(monikers, ", ")), shouldAlert
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func newTestVoteStates(preVoted ...bool) []enginetypes.ValidatorVoteState {
+	var voteStates []enginetypes.ValidatorVoteState
+	for i, voted := range preVoted {
+		voteStates = append(voteStates, enginetypes.ValidatorVoteState{
+			Validator: enginetypes.LightValidator{
+				Index:   i,
+				Address: fmt.Sprintf("%040X", i),
+				Moniker: fmt.Sprintf("val%d", i),
+			},
+			PreVoted: voted,
+		})
+	}
+	return voteStates
+}
+
+func Test_pinWatchedValidators(t *testing.T) {
+	voteStates := newTestVoteStates(true, true, true, true)
+
+	require.Equal(t, voteStates, pinWatchedValidators(voteStates, nil))
+
+	pinned := pinWatchedValidators(voteStates, enginetypes.Watchlist{"val3", fmt.Sprintf("%040x", 1)})
+	var monikers []string
+	for _, voteState := range pinned {
+		monikers = append(monikers, voteState.Validator.Moniker)
+	}
+	require.Equal(t, []string{"val1", "val3", "val0", "val2"}, monikers)
+	require.Equal(t, "val0", voteStates[0].Validator.Moniker, "input must not be modified")
+}
+
+func Test_watchedMissingPreVoteMonitor(t *testing.T) {
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	votingInfo := func(heightRoundStep string, preVoted ...bool) *enginetypes.NextBlockVotingInformation {
+		return &enginetypes.NextBlockVotingInformation{
+			HeightRoundStep:           heightRoundStep,
+			StartTimeUTC:              startTime,
+			SortedValidatorVoteStates: newTestVoteStates(preVoted...),
+		}
+	}
+
+	monitor := newWatchedMissingPreVoteMonitor(enginetypes.Watchlist{"val0", "val1"}, 10*time.Second)
+
+	warning, shouldAlert := monitor.check(votingInfo("10/0/1", false, false, false), startTime.Add(5*time.Second))
+	require.Empty(t, warning, "not yet exceeded the duration")
+	require.False(t, shouldAlert)
+
+	warning, shouldAlert = monitor.check(votingInfo("10/0/1", false, true, false), startTime.Add(12*time.Second))
+	require.Equal(t, "🔔 not pre-voted after 12s: val0", warning)
+	require.True(t, shouldAlert)
+
+	warning, shouldAlert = monitor.check(votingInfo("10/0/6", false, false, false), startTime.Add(13*time.Second))
+	require.Equal(t, "🔔 not pre-voted after 13s: val0, val1", warning)
+	require.True(t, shouldAlert, "val1 has not been alerted")
+
+	warning, shouldAlert = monitor.check(votingInfo("10/0/6", false, false, false), startTime.Add(14*time.Second))
+	require.NotEmpty(t, warning)
+	require.False(t, shouldAlert, "alerted once per round")
+
+	_, shouldAlert = monitor.check(votingInfo("10/1/1", false, true, false), startTime.Add(15*time.Second))
+	require.True(t, shouldAlert, "alert again in new round")
+
+	monitor = newWatchedMissingPreVoteMonitor(enginetypes.Watchlist{"val0"}, 0)
+	warning, shouldAlert = monitor.check(votingInfo("10/0/1", false), startTime.Add(time.Hour))
+	require.Empty(t, warning, "disabled")
+	require.False(t, shouldAlert)
+}
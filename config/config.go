@@ -0,0 +1,211 @@
+package config
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/bcdevtools/consvp/constants"
+	"github.com/pkg/errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const configFileName = "config.json"
+
+// Config is the optional configuration of the app, loaded from a JSON file.
+// Command flags take precedence over the configuration.
+type Config struct {
+	Streaming StreamingConfig `json:"streaming"`
+
+	// Watchlist is the list of validators to be watched, each entry is either a consensus address or a moniker.
+	Watchlist []string `json:"watchlist,omitempty"`
+
+	Alerting AlertingConfig `json:"alerting"`
+}
+
+// StreamingConfig is the configuration of the live-streaming mode.
+type StreamingConfig struct {
+	// ServerUrl is the base URL of the streaming server, default is the hosted streaming server.
+	ServerUrl string `json:"server_url,omitempty"`
+
+	// Timeout is the timeout of each request to the streaming server, in Go duration format like "10s".
+	Timeout string `json:"timeout,omitempty"`
+
+	// ProxyUrl is the HTTP proxy to connect to the streaming server,
+	// default is taken from environment variables HTTP_PROXY, HTTPS_PROXY and NO_PROXY.
+	ProxyUrl string `json:"proxy_url,omitempty"`
+
+	// CaFile is the path to the PEM-encoded certificate authority file to verify the streaming server,
+	// in addition to the system certificate pool.
+	CaFile string `json:"ca_file,omitempty"`
+
+	// Headers are the custom headers to be added to each request to the streaming server.
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// AlertingConfig is the configuration of the threshold alerting.
+type AlertingConfig struct {
+	// WebhookUrl is the URL of the webhook which alerts are POSTed to.
+	WebhookUrl string `json:"webhook_url,omitempty"`
+
+	// Headers are the custom headers to be added to each request to the webhook.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// BodyTemplate is the Go text/template to render the JSON body of each request, default template is used if empty.
+	BodyTemplate string `json:"body_template,omitempty"`
+
+	// RepeatInterval is the interval to repeat the notification of a firing alert, in Go duration format like "30m".
+	// Not repeated if not set.
+	RepeatInterval string `json:"repeat_interval,omitempty"`
+
+	Rules []AlertRuleConfig `json:"rules,omitempty"`
+}
+
+// AlertRuleConfig is the configuration of an alert rule.
+type AlertRuleConfig struct {
+	// Name is the unique name of the rule.
+	Name string `json:"name"`
+
+	// Type is one of: prevote_below, round_at_least, validator_missing_prevote, height_stuck.
+	Type string `json:"type"`
+
+	// Threshold is the pre-vote percent for prevote_below, the round for round_at_least.
+	Threshold float64 `json:"threshold,omitempty"`
+
+	// For is the duration the condition must be active before the alert is fired, in Go duration format like "30s".
+	For string `json:"for,omitempty"`
+
+	// Validators are the consensus addresses or monikers of the watched validators for validator_missing_prevote,
+	// default is the watchlist.
+	Validators []string `json:"validators,omitempty"`
+}
+
+// DefaultConfigFilePath returns the default path of the configuration file, inside the app directory of user home.
+func DefaultConfigFilePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get user home directory")
+	}
+	return filepath.Join(homeDir, constants.HOME_DIR_NAME, configFileName), nil
+}
+
+// LoadConfig loads the configuration from the given file.
+// If the file does not exist, an empty configuration is returned when optional, otherwise error.
+func LoadConfig(filePath string, optional bool) (*Config, error) {
+	bz, err := os.ReadFile(filePath)
+	if err != nil {
+		if optional && os.IsNotExist(err) {
+			return &Config{}, nil
+		}
+		return nil, errors.Wrap(err, "failed to read config file")
+	}
+
+	var config Config
+	if err := json.Unmarshal(bz, &config); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal config file")
+	}
+
+	if err := config.ValidateBasic(); err != nil {
+		return nil, errors.Wrap(err, "invalid config")
+	}
+
+	return &config, nil
+}
+
+// ValidateBasic returns error if the configuration is malformed.
+func (c Config) ValidateBasic() error {
+	if err := c.Streaming.ValidateBasic(); err != nil {
+		return errors.Wrap(err, "streaming")
+	}
+	if err := c.Alerting.ValidateBasic(); err != nil {
+		return errors.Wrap(err, "alerting")
+	}
+	return nil
+}
+
+// ValidateBasic returns error if the streaming configuration is malformed.
+func (c StreamingConfig) ValidateBasic() error {
+	if len(c.ServerUrl) > 0 && !strings.HasPrefix(c.ServerUrl, "http://") && !strings.HasPrefix(c.ServerUrl, "https://") {
+		return fmt.Errorf("server url must start with http:// or https://")
+	}
+
+	if _, err := c.GetTimeout(); err != nil {
+		return err
+	}
+
+	for key := range c.Headers {
+		if len(strings.TrimSpace(key)) < 1 {
+			return fmt.Errorf("header name must not be empty")
+		}
+	}
+
+	return nil
+}
+
+// GetTimeout returns the parsed timeout, zero if not set.
+func (c StreamingConfig) GetTimeout() (time.Duration, error) {
+	if len(c.Timeout) < 1 {
+		return 0, nil
+	}
+
+	timeout, err := time.ParseDuration(c.Timeout)
+	if err != nil {
+		return 0, errors.Wrap(err, "bad timeout")
+	}
+
+	if timeout < 0 {
+		return 0, fmt.Errorf("timeout must not be negative")
+	}
+
+	return timeout, nil
+}
+
+// ValidateBasic returns error if the alerting configuration is malformed.
+// Rules are validated further by the alerting engine.
+func (c AlertingConfig) ValidateBasic() error {
+	if len(c.WebhookUrl) > 0 && !strings.HasPrefix(c.WebhookUrl, "http://") && !strings.HasPrefix(c.WebhookUrl, "https://") {
+		return fmt.Errorf("webhook url must start with http:// or https://")
+	}
+
+	if _, err := c.GetRepeatInterval(); err != nil {
+		return err
+	}
+
+	for i, rule := range c.Rules {
+		if _, err := rule.GetFor(); err != nil {
+			return errors.Wrap(err, fmt.Sprintf("rule #%d", i+1))
+		}
+	}
+
+	return nil
+}
+
+// GetRepeatInterval returns the parsed repeat interval, zero if not set.
+func (c AlertingConfig) GetRepeatInterval() (time.Duration, error) {
+	return parseNonNegativeDuration(c.RepeatInterval, "repeat interval")
+}
+
+// GetFor returns the parsed duration the condition must be active before the alert is fired, zero if not set.
+func (c AlertRuleConfig) GetFor() (time.Duration, error) {
+	return parseNonNegativeDuration(c.For, "duration")
+}
+
+// parseNonNegativeDuration parses the duration in Go duration format, zero if empty.
+func parseNonNegativeDuration(s string, name string) (time.Duration, error) {
+	if len(s) < 1 {
+		return 0, nil
+	}
+
+	duration, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, errors.Wrap(err, "bad "+name)
+	}
+
+	if duration < 0 {
+		return 0, fmt.Errorf("%s must not be negative", name)
+	}
+
+	return duration, nil
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("not exists", func(t *testing.T) {
+		config, err := LoadConfig(filepath.Join(dir, "not-exists.json"), true)
+		require.NoError(t, err)
+		require.Equal(t, Config{}, *config)
+
+		_, err = LoadConfig(filepath.Join(dir, "not-exists.json"), false)
+		require.Error(t, err)
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		filePath := filepath.Join(dir, "valid.json")
+		require.NoError(t, os.WriteFile(filePath, []byte(`{
+  "streaming": {
+    "server_url": "https://cvp.example.com",
+    "timeout": "15s",
+    "proxy_url": "http://proxy.local:3128",
+    "ca_file": "/etc/ssl/corp-ca.pem",
+    "headers": {
+      "Authorization": "Bearer token"
+    }
+  },
+  "watchlist": ["My Validator", "AABBCCDDEEFF00112233445566778899AABBCCDD"],
+  "alerting": {
+    "webhook_url": "https://hooks.example.com/alert",
+    "repeat_interval": "30m",
+    "rules": [
+      {"name": "low-prevote", "type": "prevote_below", "threshold": 67, "for": "30s"},
+      {"name": "watched", "type": "validator_missing_prevote", "validators": ["My Validator"], "for": "20s"}
+    ]
+  }
+}`), 0o600))
+
+		config, err := LoadConfig(filePath, false)
+		require.NoError(t, err)
+		require.Equal(t, "https://cvp.example.com", config.Streaming.ServerUrl)
+		require.Equal(t, "http://proxy.local:3128", config.Streaming.ProxyUrl)
+		require.Equal(t, "/etc/ssl/corp-ca.pem", config.Streaming.CaFile)
+		require.Equal(t, map[string]string{"Authorization": "Bearer token"}, config.Streaming.Headers)
+		require.Equal(t, []string{"My Validator", "AABBCCDDEEFF00112233445566778899AABBCCDD"}, config.Watchlist)
+
+		timeout, err := config.Streaming.GetTimeout()
+		require.NoError(t, err)
+		require.Equal(t, 15*time.Second, timeout)
+
+		require.Equal(t, "https://hooks.example.com/alert", config.Alerting.WebhookUrl)
+		repeatInterval, err := config.Alerting.GetRepeatInterval()
+		require.NoError(t, err)
+		require.Equal(t, 30*time.Minute, repeatInterval)
+		require.Len(t, config.Alerting.Rules, 2)
+		require.Equal(t, "prevote_below", config.Alerting.Rules[0].Type)
+		require.Equal(t, float64(67), config.Alerting.Rules[0].Threshold)
+		ruleFor, err := config.Alerting.Rules[0].GetFor()
+		require.NoError(t, err)
+		require.Equal(t, 30*time.Second, ruleFor)
+		require.Equal(t, []string{"My Validator"}, config.Alerting.Rules[1].Validators)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for name, content := range map[string]string{
+			"malformed":      `{`,
+			"bad server url": `{"streaming":{"server_url":"cvp.example.com"}}`,
+			"bad timeout":    `{"streaming":{"timeout":"15"}}`,
+			"empty header":   `{"streaming":{"headers":{" ":"x"}}}`,
+			"bad alert url":  `{"alerting":{"webhook_url":"hooks.example.com"}}`,
+			"bad repeat":     `{"alerting":{"repeat_interval":"-1m"}}`,
+			"bad rule for":   `{"alerting":{"rules":[{"name":"a","type":"height_stuck","for":"5"}]}}`,
+		} {
+			t.Run(name, func(t *testing.T) {
+				filePath := filepath.Join(dir, "invalid.json")
+				require.NoError(t, os.WriteFile(filePath, []byte(content), 0o600))
+
+				_, err := LoadConfig(filePath, true)
+				require.Error(t, err)
+			})
+		}
+	})
+}
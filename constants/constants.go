@@ -8,6 +8,15 @@ const (
 	APP_INTRO      = "You are using " + APP_NAME + ", a product of bcdev.tools\nFollow us on GitHub for new tools and updates: " + GITHUB_ORG + " (don't forget to star our repo!)"
 
 	BINARY_NAME = "cvp"
+
+	// HOME_DIR_NAME is the name of the directory, inside user home directory, where the app state and config are stored.
+	HOME_DIR_NAME = ".cvp"
+)
+
+//goland:noinspection GoSnakeCaseUsage
+const (
+	ENV_STREAMING_SESSION_ID  = "CVP_STREAMING_SESSION_ID"
+	ENV_STREAMING_SESSION_KEY = "CVP_STREAMING_SESSION_KEY"
 )
 
 //goland:noinspection GoSnakeCaseUsage
@@ -0,0 +1,234 @@
+package alerting
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"fmt"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"time"
+)
+
+// AlertStatus is the status of an alert notification.
+type AlertStatus string
+
+const (
+	AlertStatusFiring   AlertStatus = "firing"
+	AlertStatusResolved AlertStatus = "resolved"
+)
+
+// Alert is the content of a notification, used as the data of the body template.
+type Alert struct {
+	Status AlertStatus
+
+	// Rule is the name of the rule.
+	Rule string
+
+	// Fingerprint identifies the alert, firing and resolved notifications of the same alert have the same fingerprint.
+	Fingerprint string
+
+	// Subject is the subject of the alert, eg: validator address, empty if the rule has single instance.
+	Subject string
+
+	Summary string
+	ChainId string
+
+	// Height, Round and Step are of the latest evaluated voting information.
+	Height int64
+	Round  int
+	Step   int
+
+	// StartsAt is the time when the condition became active.
+	StartsAt time.Time
+
+	// EndsAt is the time when the alert resolved, zero if firing.
+	EndsAt time.Time
+}
+
+// Notifier delivers the alert notifications.
+type Notifier interface {
+	Notify(Alert) error
+}
+
+// alertState is the state of an active rule instance.
+type alertState struct {
+	rule     Rule
+	instance ruleInstance
+	since    time.Time
+
+	firing         bool
+	lastNotifiedAt time.Time
+}
+
+// Engine evaluates the rules against each voting information and notifies when alerts fire or resolve.
+//
+// Notifications are de-duplicated: a firing alert is notified once, then again every repeat interval if set,
+// and a resolved notification is sent when the condition is no longer active.
+// Engine is not safe for concurrent use, Evaluate is expected to be called from the fetching loop.
+type Engine struct {
+	rules          []Rule
+	chainId        string
+	notifier       Notifier
+	repeatInterval time.Duration
+
+	states          map[string]*alertState
+	lastHeight      int64
+	lastInformation *enginetypes.NextBlockVotingInformation
+
+	nowFunc func() time.Time // to be replaced in tests
+}
+
+// NewEngine returns a new Engine, repeat interval is zero to not repeat firing notifications.
+func NewEngine(rules []Rule, chainId string, notifier Notifier, repeatInterval time.Duration) (*Engine, error) {
+	names := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		if err := rule.ValidateBasic(); err != nil {
+			return nil, err
+		}
+		if names[rule.Name] {
+			return nil, fmt.Errorf("duplicated rule name %s", rule.Name)
+		}
+		names[rule.Name] = true
+	}
+
+	if notifier == nil {
+		return nil, fmt.Errorf("notifier is required")
+	}
+
+	if repeatInterval < 0 {
+		return nil, fmt.Errorf("repeat interval must not be negative")
+	}
+
+	return &Engine{
+		rules:          rules,
+		chainId:        chainId,
+		notifier:       notifier,
+		repeatInterval: repeatInterval,
+		states:         make(map[string]*alertState),
+		nowFunc:        time.Now,
+	}, nil
+}
+
+// Evaluate evaluates the rules against the voting information, then notifies the alerts fired or resolved.
+// Returns the errors of the notifications failed to be delivered, the failed notifications will be retried
+// at the next evaluation.
+func (e *Engine) Evaluate(information *enginetypes.NextBlockVotingInformation) []error {
+	height, _, _, _ := enginetypes.ParseHeightRoundStep(information.HeightRoundStep)
+	heightUnchanged := e.lastHeight > 0 && height == e.lastHeight
+	e.lastHeight = height
+	e.lastInformation = information
+
+	return e.evaluate(information, heightUnchanged)
+}
+
+// EvaluateFetchFailure evaluates the rules when fetching the voting information failed,
+// against the latest evaluated voting information as if the height has not changed,
+// so the height stuck alert still fires and the firing alerts are still repeated while the node is unreachable.
+// Returns the errors of the notifications failed to be delivered, same as Evaluate.
+func (e *Engine) EvaluateFetchFailure() []error {
+	if e.lastInformation == nil {
+		return nil
+	}
+
+	return e.evaluate(e.lastInformation, true)
+}
+
+func (e *Engine) evaluate(information *enginetypes.NextBlockVotingInformation, heightUnchanged bool) []error {
+	now := e.nowFunc()
+	height, round, step, _ := enginetypes.ParseHeightRoundStep(information.HeightRoundStep)
+
+	ctx := evaluationContext{
+		information:     information,
+		height:          height,
+		round:           round,
+		heightUnchanged: heightUnchanged,
+	}
+
+	newAlert := func(state *alertState, fingerprint string, status AlertStatus) Alert {
+		alert := Alert{
+			Status:      status,
+			Rule:        state.rule.Name,
+			Fingerprint: fingerprint,
+			Subject:     state.instance.subject,
+			Summary:     state.instance.summary,
+			ChainId:     e.chainId,
+			Height:      height,
+			Round:       round,
+			Step:        step,
+			StartsAt:    state.since,
+		}
+		if status == AlertStatusResolved {
+			alert.EndsAt = now
+		}
+		return alert
+	}
+
+	var errs []error
+
+	activeFingerprints := make(map[string]bool)
+	for _, rule := range e.rules {
+		for _, instance := range rule.evaluate(ctx) {
+			fingerprint := getFingerprint(rule.Name, instance.subject)
+			activeFingerprints[fingerprint] = true
+
+			state, found := e.states[fingerprint]
+			if !found {
+				state = &alertState{
+					rule:  rule,
+					since: now,
+				}
+				e.states[fingerprint] = state
+			}
+			state.instance = instance
+
+			if now.Sub(state.since) < rule.For {
+				continue // pending
+			}
+
+			if state.firing && (e.repeatInterval == 0 || now.Sub(state.lastNotifiedAt) < e.repeatInterval) {
+				continue // de-duplicated
+			}
+
+			if err := e.notifier.Notify(newAlert(state, fingerprint, AlertStatusFiring)); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			state.firing = true
+			state.lastNotifiedAt = now
+		}
+	}
+
+	for fingerprint, state := range e.states {
+		if activeFingerprints[fingerprint] {
+			continue
+		}
+
+		if state.firing {
+			if err := e.notifier.Notify(newAlert(state, fingerprint, AlertStatusResolved)); err != nil {
+				errs = append(errs, err)
+				continue // retry at the next evaluation
+			}
+		}
+
+		delete(e.states, fingerprint)
+	}
+
+	return errs
+}
+
+// FiringCount returns the number of the firing alerts.
+func (e *Engine) FiringCount() int {
+	var count int
+	for _, state := range e.states {
+		if state.firing {
+			count++
+		}
+	}
+	return count
+}
+
+func getFingerprint(ruleName, subject string) string {
+	if len(subject) < 1 {
+		return ruleName
+	}
+	return ruleName + "/" + subject
+}
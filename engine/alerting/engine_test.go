@@ -0,0 +1,316 @@
+package alerting
+
+import (
+	"encoding/json"
+	"fmt"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// webhookStandIn is a local HTTP stand-in of the alert webhook, records the received bodies.
+type webhookStandIn struct {
+	mutex      sync.Mutex
+	bodies     []map[string]interface{}
+	statusCode int
+}
+
+func newWebhookStandIn(t *testing.T) (*webhookStandIn, *httptest.Server) {
+	standIn := &webhookStandIn{
+		statusCode: http.StatusOK,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.Equal(t, "Bearer xxx", r.Header.Get("Authorization"))
+
+		bz, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(bz, &body))
+
+		standIn.mutex.Lock()
+		defer standIn.mutex.Unlock()
+		if standIn.statusCode == http.StatusOK {
+			standIn.bodies = append(standIn.bodies, body)
+		}
+		w.WriteHeader(standIn.statusCode)
+	}))
+	t.Cleanup(server.Close)
+	return standIn, server
+}
+
+func (s *webhookStandIn) takeBodies() []map[string]interface{} {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	bodies := s.bodies
+	s.bodies = nil
+	return bodies
+}
+
+func (s *webhookStandIn) setStatusCode(statusCode int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.statusCode = statusCode
+}
+
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func (c *testClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestEngine(t *testing.T, rules []Rule, repeatInterval time.Duration) (*Engine, *webhookStandIn, *testClock) {
+	standIn, server := newWebhookStandIn(t)
+
+	notifier, err := NewWebhookNotifier(server.URL, map[string]string{"Authorization": "Bearer xxx"}, "", nil)
+	require.NoError(t, err)
+
+	engine, err := NewEngine(rules, "chain-1", notifier, repeatInterval)
+	require.NoError(t, err)
+
+	clock := &testClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	engine.nowFunc = clock.Now
+
+	return engine, standIn, clock
+}
+
+func newTestInformation(heightRoundStep string, preVotePercent float64, voteStates ...enginetypes.ValidatorVoteState) *enginetypes.NextBlockVotingInformation {
+	return &enginetypes.NextBlockVotingInformation{
+		HeightRoundStep:           heightRoundStep,
+		PreVotePercent:            preVotePercent,
+		SortedValidatorVoteStates: voteStates,
+	}
+}
+
+func TestEngine_PreVoteBelow(t *testing.T) {
+	engine, standIn, clock := newTestEngine(t, []Rule{{
+		Name:      "low-prevote",
+		Type:      RuleTypePreVoteBelow,
+		Threshold: 67,
+		For:       30 * time.Second,
+	}}, 0)
+
+	// pending
+	require.Empty(t, engine.Evaluate(newTestInformation("10/0/1", 50)))
+	clock.Advance(20 * time.Second)
+	require.Empty(t, engine.Evaluate(newTestInformation("10/0/1", 60)))
+	require.Empty(t, standIn.takeBodies())
+
+	// fired
+	clock.Advance(10 * time.Second)
+	require.Empty(t, engine.Evaluate(newTestInformation("10/0/1", 60)))
+	bodies := standIn.takeBodies()
+	require.Len(t, bodies, 1)
+	require.Equal(t, "firing", bodies[0]["status"])
+	require.Equal(t, "low-prevote", bodies[0]["rule"])
+	require.Equal(t, "chain-1", bodies[0]["chain_id"])
+	require.Equal(t, "pre-vote 60.00% is below 67.00%", bodies[0]["summary"])
+	require.Equal(t, float64(10), bodies[0]["height"])
+	require.Equal(t, "2024-01-01T00:00:00Z", bodies[0]["starts_at"])
+	require.Nil(t, bodies[0]["ends_at"])
+	require.Equal(t, 1, engine.FiringCount())
+
+	// de-duplicated
+	clock.Advance(time.Minute)
+	require.Empty(t, engine.Evaluate(newTestInformation("10/0/1", 60)))
+	require.Empty(t, standIn.takeBodies())
+
+	// resolved
+	clock.Advance(time.Second)
+	require.Empty(t, engine.Evaluate(newTestInformation("10/0/1", 70)))
+	bodies = standIn.takeBodies()
+	require.Len(t, bodies, 1)
+	require.Equal(t, "resolved", bodies[0]["status"])
+	require.Equal(t, "2024-01-01T00:01:31Z", bodies[0]["ends_at"])
+	require.Equal(t, 0, engine.FiringCount())
+
+	// condition recovered before fired, no notification
+	require.Empty(t, engine.Evaluate(newTestInformation("11/0/1", 10)))
+	clock.Advance(time.Second)
+	require.Empty(t, engine.Evaluate(newTestInformation("11/0/1", 90)))
+	require.Empty(t, standIn.takeBodies())
+}
+
+func TestEngine_RepeatAndRetry(t *testing.T) {
+	engine, standIn, clock := newTestEngine(t, []Rule{{
+		Name:      "round",
+		Type:      RuleTypeRoundAtLeast,
+		Threshold: 2,
+	}}, 5*time.Minute)
+
+	require.Empty(t, engine.Evaluate(newTestInformation("10/1/1", 100)))
+	require.Empty(t, engine.Evaluate(newTestInformation("10/2/1", 100)))
+	require.Len(t, standIn.takeBodies(), 1)
+
+	clock.Advance(4 * time.Minute)
+	require.Empty(t, engine.Evaluate(newTestInformation("10/3/1", 100)))
+	require.Empty(t, standIn.takeBodies())
+
+	clock.Advance(time.Minute)
+	require.Empty(t, engine.Evaluate(newTestInformation("10/3/1", 100)))
+	bodies := standIn.takeBodies()
+	require.Len(t, bodies, 1, "repeated after the repeat interval")
+	require.Equal(t, "firing", bodies[0]["status"])
+
+	// resolved notification failed to be delivered, retried at the next evaluation
+	standIn.setStatusCode(http.StatusServiceUnavailable)
+	require.Len(t, engine.Evaluate(newTestInformation("11/0/1", 100)), 1)
+	require.Equal(t, 1, engine.FiringCount())
+
+	standIn.setStatusCode(http.StatusOK)
+	require.Empty(t, engine.Evaluate(newTestInformation("11/0/2", 100)))
+	bodies = standIn.takeBodies()
+	require.Len(t, bodies, 1)
+	require.Equal(t, "resolved", bodies[0]["status"])
+	require.Equal(t, 0, engine.FiringCount())
+}
+
+func TestEngine_ValidatorMissingPreVote(t *testing.T) {
+	engine, standIn, clock := newTestEngine(t, []Rule{{
+		Name:       "watched",
+		Type:       RuleTypeValidatorMissingPreVote,
+		For:        10 * time.Second,
+		Validators: enginetypes.Watchlist{"val1", fmt.Sprintf("%040X", 2)},
+	}}, 0)
+
+	voteState := func(index int, preVoted bool) enginetypes.ValidatorVoteState {
+		return enginetypes.ValidatorVoteState{
+			Validator: enginetypes.LightValidator{
+				Index:   index,
+				Address: fmt.Sprintf("%040X", index),
+				Moniker: fmt.Sprintf("val%d", index),
+			},
+			PreVoted: preVoted,
+		}
+	}
+
+	require.Empty(t, engine.Evaluate(newTestInformation("10/0/1", 50, voteState(0, false), voteState(1, false), voteState(2, false))))
+	clock.Advance(10 * time.Second)
+	require.Empty(t, engine.Evaluate(newTestInformation("10/0/1", 50, voteState(0, false), voteState(1, false), voteState(2, false))))
+
+	bodies := standIn.takeBodies()
+	require.Len(t, bodies, 2, "one alert per watched validator")
+	subjects := []interface{}{bodies[0]["subject"], bodies[1]["subject"]}
+	require.ElementsMatch(t, []interface{}{fmt.Sprintf("%040X", 1), fmt.Sprintf("%040X", 2)}, subjects)
+
+	// only val2 resolved
+	require.Empty(t, engine.Evaluate(newTestInformation("10/0/1", 50, voteState(0, false), voteState(1, false), voteState(2, true))))
+	bodies = standIn.takeBodies()
+	require.Len(t, bodies, 1)
+	require.Equal(t, "resolved", bodies[0]["status"])
+	require.Equal(t, "watched/"+fmt.Sprintf("%040X", 2), bodies[0]["fingerprint"])
+}
+
+func TestEngine_HeightStuck(t *testing.T) {
+	engine, standIn, clock := newTestEngine(t, []Rule{{
+		Name: "stuck",
+		Type: RuleTypeHeightStuck,
+		For:  5 * time.Minute,
+	}}, 0)
+
+	require.Empty(t, engine.Evaluate(newTestInformation("10/0/1", 0)))
+	for i := 0; i < 5; i++ {
+		clock.Advance(time.Minute)
+		require.Empty(t, engine.Evaluate(newTestInformation(fmt.Sprintf("10/%d/1", i), 0)))
+	}
+	require.Empty(t, standIn.takeBodies(), "stuck for 4 minutes since the first unchanged observation")
+
+	clock.Advance(time.Minute)
+	require.Empty(t, engine.Evaluate(newTestInformation("10/5/1", 0)))
+	bodies := standIn.takeBodies()
+	require.Len(t, bodies, 1)
+	require.Equal(t, "height 10 has not changed", bodies[0]["summary"])
+
+	require.Empty(t, engine.Evaluate(newTestInformation("11/0/1", 0)))
+	bodies = standIn.takeBodies()
+	require.Len(t, bodies, 1)
+	require.Equal(t, "resolved", bodies[0]["status"])
+}
+
+func TestEngine_EvaluateFetchFailure(t *testing.T) {
+	engine, standIn, clock := newTestEngine(t, []Rule{{
+		Name: "stuck",
+		Type: RuleTypeHeightStuck,
+		For:  time.Minute,
+	}, {
+		Name:      "round",
+		Type:      RuleTypeRoundAtLeast,
+		Threshold: 2,
+	}}, 5*time.Minute)
+
+	require.Empty(t, engine.EvaluateFetchFailure(), "nothing evaluated yet")
+
+	require.Empty(t, engine.Evaluate(newTestInformation("10/2/1", 100)))
+	bodies := standIn.takeBodies()
+	require.Len(t, bodies, 1)
+	require.Equal(t, "round", bodies[0]["rule"])
+
+	// node is unreachable
+	require.Empty(t, engine.EvaluateFetchFailure())
+	clock.Advance(time.Minute)
+	require.Empty(t, engine.EvaluateFetchFailure())
+	bodies = standIn.takeBodies()
+	require.Len(t, bodies, 1, "height stuck must fire while fetching fails")
+	require.Equal(t, "stuck", bodies[0]["rule"])
+	require.Equal(t, "firing", bodies[0]["status"])
+
+	clock.Advance(5 * time.Minute)
+	require.Empty(t, engine.EvaluateFetchFailure())
+	require.Len(t, standIn.takeBodies(), 2, "firing alerts must be repeated while fetching fails")
+
+	// node is back
+	require.Empty(t, engine.Evaluate(newTestInformation("11/0/1", 100)))
+	bodies = standIn.takeBodies()
+	require.Len(t, bodies, 2)
+	for _, body := range bodies {
+		require.Equal(t, "resolved", body["status"])
+	}
+}
+
+func TestNewEngine_InvalidRules(t *testing.T) {
+	notifier, err := NewWebhookNotifier("http://localhost", nil, "", nil)
+	require.NoError(t, err)
+
+	for _, rules := range [][]Rule{
+		{{Name: "", Type: RuleTypeRoundAtLeast, Threshold: 1}},
+		{{Name: "a", Type: "unknown"}},
+		{{Name: "a", Type: RuleTypePreVoteBelow, Threshold: 101}},
+		{{Name: "a", Type: RuleTypeRoundAtLeast, Threshold: 0}},
+		{{Name: "a", Type: RuleTypeValidatorMissingPreVote}},
+		{{Name: "a", Type: RuleTypeValidatorMissingPreVote, Validators: enginetypes.Watchlist{"val1"}}},
+		{{Name: "a", Type: RuleTypeHeightStuck}},
+		{{Name: "a", Type: RuleTypeRoundAtLeast, Threshold: 1}, {Name: "a", Type: RuleTypeRoundAtLeast, Threshold: 2}},
+	} {
+		_, err := NewEngine(rules, "chain-1", notifier, 0)
+		require.Error(t, err)
+	}
+}
+
+func TestWebhookNotifier_RenderBody(t *testing.T) {
+	notifier, err := NewWebhookNotifier("http://localhost", nil, `{"text": {{ json (printf "[%s] %s" .Status .Summary) }}}`, nil)
+	require.NoError(t, err)
+
+	body, err := notifier.RenderBody(Alert{Status: AlertStatusFiring, Summary: `validator "A" has not pre-voted`})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"text": "[firing] validator \"A\" has not pre-voted"}`, string(body))
+
+	notifier, err = NewWebhookNotifier("http://localhost", nil, `{"text": {{ .Summary }}}`, nil)
+	require.NoError(t, err)
+	_, err = notifier.RenderBody(Alert{Summary: "not quoted"})
+	require.ErrorContains(t, err, "not a valid JSON")
+
+	_, err = NewWebhookNotifier("http://localhost", nil, `{{ .Summary `, nil)
+	require.Error(t, err)
+}
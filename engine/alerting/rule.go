@@ -0,0 +1,137 @@
+package alerting
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"fmt"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"strings"
+	"time"
+)
+
+// RuleType is the type of condition of an alert rule.
+type RuleType string
+
+const (
+	// RuleTypePreVoteBelow is active when the pre-vote percent is below the threshold.
+	RuleTypePreVoteBelow RuleType = "prevote_below"
+
+	// RuleTypeRoundAtLeast is active when the round is greater than or equal to the threshold.
+	RuleTypeRoundAtLeast RuleType = "round_at_least"
+
+	// RuleTypeValidatorMissingPreVote is active, per validator, when any of the watched validators has not pre-voted.
+	// Rule.For is required, because no validator has pre-voted at the beginning of each round.
+	RuleTypeValidatorMissingPreVote RuleType = "validator_missing_prevote"
+
+	// RuleTypeHeightStuck is active when the height has not changed since the previous evaluation,
+	// combine with Rule.For to alert when the height is stuck for a duration.
+	RuleTypeHeightStuck RuleType = "height_stuck"
+)
+
+// Rule is an alert rule, alert is fired when the condition is active continuously for the duration Rule.For.
+type Rule struct {
+	// Name is the unique name of the rule.
+	Name string
+
+	Type RuleType
+
+	// Threshold is the pre-vote percent for RuleTypePreVoteBelow, the round for RuleTypeRoundAtLeast.
+	Threshold float64
+
+	// For is the duration the condition must be active before the alert is fired, zero to fire immediately.
+	For time.Duration
+
+	// Validators are the watched validators for RuleTypeValidatorMissingPreVote.
+	Validators enginetypes.Watchlist
+}
+
+// ValidateBasic returns error if the rule is malformed.
+func (r Rule) ValidateBasic() error {
+	if len(strings.TrimSpace(r.Name)) < 1 {
+		return fmt.Errorf("rule name is required")
+	}
+
+	if r.For < 0 {
+		return fmt.Errorf("rule %s: duration must not be negative", r.Name)
+	}
+
+	switch r.Type {
+	case RuleTypePreVoteBelow:
+		if r.Threshold <= 0 || r.Threshold > 100 {
+			return fmt.Errorf("rule %s: threshold must be a percent in range (0, 100]", r.Name)
+		}
+	case RuleTypeRoundAtLeast:
+		if r.Threshold < 1 {
+			return fmt.Errorf("rule %s: threshold must be a positive round", r.Name)
+		}
+	case RuleTypeValidatorMissingPreVote:
+		if len(r.Validators) < 1 {
+			return fmt.Errorf("rule %s: validators are required", r.Name)
+		}
+		if r.For <= 0 {
+			return fmt.Errorf("rule %s: duration is required", r.Name)
+		}
+	case RuleTypeHeightStuck:
+		if r.For <= 0 {
+			return fmt.Errorf("rule %s: duration is required", r.Name)
+		}
+	default:
+		return fmt.Errorf("rule %s: unknown rule type '%s'", r.Name, r.Type)
+	}
+
+	return nil
+}
+
+// ruleInstance is an active condition of a rule, a rule can have multiple instances, one per subject.
+type ruleInstance struct {
+	// subject is the subject of the instance, eg: validator address, empty if the rule has single instance.
+	subject string
+	summary string
+}
+
+// evaluationContext holds the information needed to evaluate the rules.
+type evaluationContext struct {
+	information *enginetypes.NextBlockVotingInformation
+	height      int64
+	round       int
+
+	// heightUnchanged is true if the height equals to the height of the previous evaluation.
+	heightUnchanged bool
+}
+
+// evaluate returns the active instances of the rule.
+func (r Rule) evaluate(ctx evaluationContext) []ruleInstance {
+	switch r.Type {
+	case RuleTypePreVoteBelow:
+		if ctx.information.PreVotePercent < r.Threshold {
+			return []ruleInstance{{
+				summary: fmt.Sprintf("pre-vote %.2f%% is below %.2f%%", ctx.information.PreVotePercent, r.Threshold),
+			}}
+		}
+	case RuleTypeRoundAtLeast:
+		if float64(ctx.round) >= r.Threshold {
+			return []ruleInstance{{
+				summary: fmt.Sprintf("round %d reached the threshold %.0f", ctx.round, r.Threshold),
+			}}
+		}
+	case RuleTypeValidatorMissingPreVote:
+		var instances []ruleInstance
+		for _, voteState := range ctx.information.SortedValidatorVoteStates {
+			if voteState.PreVoted || !r.Validators.Contains(voteState.Validator) {
+				continue
+			}
+			instances = append(instances, ruleInstance{
+				subject: voteState.Validator.Address,
+				summary: fmt.Sprintf("validator %s has not pre-voted", voteState.Validator.Moniker),
+			})
+		}
+		return instances
+	case RuleTypeHeightStuck:
+		if ctx.heightUnchanged {
+			return []ruleInstance{{
+				summary: fmt.Sprintf("height %d has not changed", ctx.height),
+			}}
+		}
+	}
+
+	return nil
+}
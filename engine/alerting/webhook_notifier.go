@@ -0,0 +1,132 @@
+package alerting
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/bcdevtools/consvp/utils"
+	"github.com/pkg/errors"
+	"io"
+	"net/http"
+	"net/url"
+	"text/template"
+	"time"
+)
+
+// DefaultBodyTemplate is the default template of the webhook request body.
+const DefaultBodyTemplate = `{
+  "status": {{ json .Status }},
+  "rule": {{ json .Rule }},
+  "fingerprint": {{ json .Fingerprint }},
+  "subject": {{ json .Subject }},
+  "summary": {{ json .Summary }},
+  "chain_id": {{ json .ChainId }},
+  "height": {{ .Height }},
+  "round": {{ .Round }},
+  "step": {{ .Step }},
+  "starts_at": {{ json .StartsAt }},
+  "ends_at": {{ if .EndsAt.IsZero }}null{{ else }}{{ json .EndsAt }}{{ end }}
+}`
+
+// DefaultWebhookTimeout is the default timeout of each webhook request.
+const DefaultWebhookTimeout = 10 * time.Second
+
+var _ Notifier = (*WebhookNotifier)(nil)
+
+// WebhookNotifier POSTs each alert to the webhook URL, the JSON body is rendered from a Go text/template
+// with the Alert as data. Template function 'json' encodes the value as JSON, eg: {{ json .Summary }}.
+type WebhookNotifier struct {
+	webhookUrl   string
+	headers      map[string]string
+	bodyTemplate *template.Template
+	httpClient   *http.Client
+}
+
+// NewWebhookNotifier returns a new WebhookNotifier, DefaultBodyTemplate is used if body template is empty.
+// Default HTTP client with DefaultWebhookTimeout is used if HTTP client is nil.
+func NewWebhookNotifier(webhookUrl string, headers map[string]string, bodyTemplate string, optionalHttpClient *http.Client) (*WebhookNotifier, error) {
+	parsedUrl, err := url.Parse(webhookUrl)
+	if err != nil {
+		return nil, errors.Wrap(err, "bad webhook URL")
+	}
+	if parsedUrl.Scheme != "http" && parsedUrl.Scheme != "https" {
+		return nil, fmt.Errorf("webhook URL must start with http:// or https://")
+	}
+
+	if len(bodyTemplate) < 1 {
+		bodyTemplate = DefaultBodyTemplate
+	}
+	tmpl, err := template.New("body").Funcs(template.FuncMap{
+		"json": func(v interface{}) (string, error) {
+			bz, err := json.Marshal(v)
+			return string(bz), err
+		},
+	}).Parse(bodyTemplate)
+	if err != nil {
+		return nil, errors.Wrap(err, "bad body template")
+	}
+
+	httpClient := optionalHttpClient
+	if httpClient == nil {
+		httpClient = &http.Client{
+			Timeout: DefaultWebhookTimeout,
+		}
+	}
+
+	return &WebhookNotifier{
+		webhookUrl:   webhookUrl,
+		headers:      headers,
+		bodyTemplate: tmpl,
+		httpClient:   httpClient,
+	}, nil
+}
+
+// Notify implements Notifier.
+func (n *WebhookNotifier) Notify(alert Alert) error {
+	body, err := n.RenderBody(alert)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, n.webhookUrl, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range n.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return errors.Wrap(utils.RedactUrlError(err), "failed to call alert webhook")
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &utils.HttpResponseError{
+			StatusCode: resp.StatusCode,
+			Message:    fmt.Sprintf("alert webhook returned status code: %d", resp.StatusCode),
+		}
+	}
+
+	return nil
+}
+
+// RenderBody renders the request body of the alert, error if the rendered body is not a valid JSON.
+func (n *WebhookNotifier) RenderBody(alert Alert) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := n.bodyTemplate.Execute(&buffer, alert); err != nil {
+		return nil, errors.Wrap(err, "failed to render body template")
+	}
+
+	if !json.Valid(buffer.Bytes()) {
+		return nil, fmt.Errorf("rendered body is not a valid JSON: %s", buffer.String())
+	}
+
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,24 @@
+package broadcast_sink
+
+//goland:noinspection SpellCheckingInspection
+import (
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+)
+
+// BroadcastSink is a destination of the next block voting information snapshots.
+// Multiple sinks can be used concurrently, each is driven by its own broadcast worker
+// so a slow or failing sink does not affect the others.
+type BroadcastSink interface {
+	// Name returns the short name of the sink, to be displayed in status.
+	Name() string
+
+	// Broadcast sends the given snapshot to the sink.
+	// It returns shouldStop=true if the sink is not able to receive any more snapshot.
+	Broadcast(*enginetypes.NextBlockVotingInformation) (err error, shouldStop bool)
+
+	// Status returns additional information of the sink to be displayed in status, can be empty.
+	Status() string
+
+	// Close releases resources held by the sink.
+	Close() error
+}
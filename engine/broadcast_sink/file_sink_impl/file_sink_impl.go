@@ -0,0 +1,91 @@
+package file_sink_impl
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/bcdevtools/consvp/engine/broadcast_sink"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"github.com/bcdevtools/consvp/utils"
+	"github.com/pkg/errors"
+	"os"
+	"time"
+)
+
+var _ broadcast_sink.BroadcastSink = (*fileSinkImpl)(nil)
+
+const (
+	// DefaultMaxSize is the default maximum size in bytes of the file before rolled.
+	DefaultMaxSize int64 = 100 * 1024 * 1024
+
+	// DefaultMaxBackups is the default maximum number of rolled files to be kept.
+	DefaultMaxBackups = 3
+)
+
+// fileSinkImpl appends each snapshot as a JSON object of schema.SnapshotV1 per line into a rolling file.
+//
+// When the file exceeds the max size, it is rolled: file.(n-1) is renamed to file.n, ..., file to file.1,
+// files beyond the max backups are removed.
+type fileSinkImpl struct {
+	filePath string
+	file     *utils.RollingFile
+}
+
+// NewFileSink returns a BroadcastSink which appends each snapshot as a JSON line into a rolling file.
+// Default values DefaultMaxSize and DefaultMaxBackups are used when non-positive value provided.
+func NewFileSink(filePath string, maxSize int64, maxBackups int) (broadcast_sink.BroadcastSink, error) {
+	if len(filePath) < 1 {
+		return nil, fmt.Errorf("file path is required")
+	}
+	if maxSize < 1 {
+		maxSize = DefaultMaxSize
+	}
+	if maxBackups < 1 {
+		maxBackups = DefaultMaxBackups
+	}
+
+	file, err := utils.NewRollingFile(filePath, 0o644, maxSize, maxBackups)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileSinkImpl{
+		filePath: filePath,
+		file:     file,
+	}, nil
+}
+
+func (s *fileSinkImpl) Name() string {
+	return "file " + s.filePath
+}
+
+func (s *fileSinkImpl) Broadcast(information *enginetypes.NextBlockVotingInformation) (err error, shouldStop bool) {
+	snapshot, err := information.ToSnapshotV1(time.Now().UTC())
+	if err != nil {
+		return err, false
+	}
+
+	bz, err := json.Marshal(snapshot)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal snapshot"), false
+	}
+	bz = append(bz, '\n')
+
+	if _, err = s.file.Write(bz); err != nil {
+		if errors.Is(err, os.ErrClosed) {
+			return fmt.Errorf("file sink has been closed"), true
+		}
+		return err, false
+	}
+
+	return nil, false
+}
+
+func (s *fileSinkImpl) Status() string {
+	size, maxSize := s.file.Size()
+	return fmt.Sprintf("%d/%d KiB", size/1024, maxSize/1024)
+}
+
+func (s *fileSinkImpl) Close() error {
+	return s.file.Close()
+}
@@ -0,0 +1,95 @@
+package file_sink_impl
+
+import (
+	"bufio"
+	"encoding/json"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"github.com/bcdevtools/consvp/schema"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func readSnapshots(t *testing.T, filePath string) []schema.SnapshotV1 {
+	file, err := os.Open(filePath)
+	require.NoError(t, err)
+	defer func() {
+		_ = file.Close()
+	}()
+
+	var snapshots []schema.SnapshotV1
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var snapshot schema.SnapshotV1
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &snapshot))
+		snapshots = append(snapshots, snapshot)
+	}
+	require.NoError(t, scanner.Err())
+	return snapshots
+}
+
+func TestFileSink_Roll(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "snapshots.jsonl")
+
+	information := func(height string) *enginetypes.NextBlockVotingInformation {
+		return &enginetypes.NextBlockVotingInformation{
+			HeightRoundStep: height + "/0/1",
+		}
+	}
+
+	// measure the size of a line
+	sink, err := NewFileSink(filePath, 0, 0)
+	require.NoError(t, err)
+	err, _ = sink.Broadcast(information("1"))
+	require.NoError(t, err)
+	require.NoError(t, sink.Close())
+	fileInfo, err := os.Stat(filePath)
+	require.NoError(t, err)
+	lineSize := fileInfo.Size()
+	require.NoError(t, os.Remove(filePath))
+
+	// 2 lines per file, keep 2 backups.
+	// Lines are slightly different in size because the timestamp is trimmed of trailing zeros.
+	sink, err = NewFileSink(filePath, lineSize*2+lineSize/2, 2)
+	require.NoError(t, err)
+	for height := 1; height <= 7; height++ {
+		err, shouldStop := sink.Broadcast(information(string(rune('0' + height))))
+		require.NoError(t, err)
+		require.False(t, shouldStop)
+	}
+	require.NoError(t, sink.Close())
+
+	heightsOf := func(filePath string) []int64 {
+		var heights []int64
+		for _, snapshot := range readSnapshots(t, filePath) {
+			heights = append(heights, snapshot.Height)
+		}
+		return heights
+	}
+
+	require.Equal(t, []int64{7}, heightsOf(filePath))
+	require.Equal(t, []int64{5, 6}, heightsOf(filePath+".1"))
+	require.Equal(t, []int64{3, 4}, heightsOf(filePath+".2"))
+	require.NoFileExists(t, filePath+".3")
+
+	err, shouldStop := sink.Broadcast(information("8"))
+	require.Error(t, err)
+	require.True(t, shouldStop, "should stop after closed")
+}
+
+func TestFileSink_AppendExisting(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "snapshots.jsonl")
+
+	for i := 0; i < 2; i++ {
+		sink, err := NewFileSink(filePath, 0, 0)
+		require.NoError(t, err)
+		err, _ = sink.Broadcast(&enginetypes.NextBlockVotingInformation{
+			HeightRoundStep: "1/0/1",
+		})
+		require.NoError(t, err)
+		require.NoError(t, sink.Close())
+	}
+
+	require.Len(t, readSnapshots(t, filePath), 2)
+}
@@ -0,0 +1,44 @@
+package streaming_sink_impl
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"github.com/bcdevtools/consvp/engine/broadcast_sink"
+	pvss "github.com/bcdevtools/consvp/engine/prevote_streaming_service"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+)
+
+var _ broadcast_sink.BroadcastSink = (*streamingSinkImpl)(nil)
+
+// streamingSinkImpl adapts the PreVoteStreamingService, which streams to the cvp streaming server, as a BroadcastSink.
+type streamingSinkImpl struct {
+	pvs pvss.PreVoteStreamingService
+}
+
+// NewStreamingSink returns a BroadcastSink which broadcasts via the given PreVoteStreamingService.
+// The streaming session must be opened or resumed before broadcasting.
+func NewStreamingSink(pvs pvss.PreVoteStreamingService) broadcast_sink.BroadcastSink {
+	return &streamingSinkImpl{
+		pvs: pvs,
+	}
+}
+
+func (s *streamingSinkImpl) Name() string {
+	return "streaming"
+}
+
+func (s *streamingSinkImpl) Broadcast(information *enginetypes.NextBlockVotingInformation) (err error, shouldStop bool) {
+	err, shouldStop = s.pvs.BroadcastPreVote(information)
+	if shouldStop {
+		s.pvs.Stop()
+	}
+	return
+}
+
+func (s *streamingSinkImpl) Status() string {
+	return "codec " + string(s.pvs.CodecVersion())
+}
+
+func (s *streamingSinkImpl) Close() error {
+	s.pvs.Stop()
+	return nil
+}
@@ -0,0 +1,94 @@
+package webhook_sink_impl
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/bcdevtools/consvp/engine/broadcast_sink"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"github.com/bcdevtools/consvp/utils"
+	"github.com/pkg/errors"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+var _ broadcast_sink.BroadcastSink = (*webhookSinkImpl)(nil)
+
+// DefaultTimeout is the default timeout of each webhook request.
+const DefaultTimeout = 10 * time.Second
+
+// webhookSinkImpl POSTs each snapshot as a JSON object of schema.SnapshotV1 to the webhook URL.
+type webhookSinkImpl struct {
+	webhookUrl string
+	httpClient *http.Client
+}
+
+// NewWebhookSink returns a BroadcastSink which POSTs each snapshot as a JSON object of schema.SnapshotV1 to the given URL.
+// Default HTTP client with DefaultTimeout is used if HTTP client is nil.
+func NewWebhookSink(webhookUrl string, optionalHttpClient *http.Client) (broadcast_sink.BroadcastSink, error) {
+	parsedUrl, err := url.Parse(webhookUrl)
+	if err != nil {
+		return nil, errors.Wrap(err, "bad webhook URL")
+	}
+	if parsedUrl.Scheme != "http" && parsedUrl.Scheme != "https" {
+		return nil, fmt.Errorf("webhook URL must start with http:// or https://")
+	}
+
+	httpClient := optionalHttpClient
+	if httpClient == nil {
+		httpClient = &http.Client{
+			Timeout: DefaultTimeout,
+		}
+	}
+
+	return &webhookSinkImpl{
+		webhookUrl: webhookUrl,
+		httpClient: httpClient,
+	}, nil
+}
+
+func (s *webhookSinkImpl) Name() string {
+	return "webhook " + utils.RedactUrl(s.webhookUrl)
+}
+
+func (s *webhookSinkImpl) Broadcast(information *enginetypes.NextBlockVotingInformation) (err error, shouldStop bool) {
+	snapshot, err := information.ToSnapshotV1(time.Now().UTC())
+	if err != nil {
+		return err, false
+	}
+
+	bz, err := json.Marshal(snapshot)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal snapshot"), false
+	}
+
+	resp, err := s.httpClient.Post(s.webhookUrl, "application/json", bytes.NewBuffer(bz))
+	if err != nil {
+		return errors.Wrap(utils.RedactUrlError(err), "failed to call webhook"), false
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &utils.HttpResponseError{
+			StatusCode: resp.StatusCode,
+			Message:    fmt.Sprintf("webhook returned status code: %d", resp.StatusCode),
+		}, false
+	}
+
+	return nil, false
+}
+
+func (s *webhookSinkImpl) Status() string {
+	return ""
+}
+
+func (s *webhookSinkImpl) Close() error {
+	s.httpClient.CloseIdleConnections()
+	return nil
+}
@@ -0,0 +1,70 @@
+package webhook_sink_impl
+
+import (
+	"encoding/json"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"github.com/bcdevtools/consvp/schema"
+	"github.com/bcdevtools/consvp/utils"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestWebhookSink_Broadcast(t *testing.T) {
+	received := make(chan schema.SnapshotV1, 1)
+	var statusCode int32 = http.StatusOK
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		bz, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		var snapshot schema.SnapshotV1
+		require.NoError(t, json.Unmarshal(bz, &snapshot))
+		received <- snapshot
+
+		w.WriteHeader(int(atomic.LoadInt32(&statusCode)))
+	}))
+	defer server.Close()
+
+	sink, err := NewWebhookSink(server.URL+"/hook/secret-token", nil)
+	require.NoError(t, err)
+	defer func() {
+		_ = sink.Close()
+	}()
+	require.Equal(t, "webhook "+server.URL, sink.Name(), "path may contain secrets")
+
+	information := &enginetypes.NextBlockVotingInformation{
+		HeightRoundStep: "10/1/6",
+	}
+
+	err, shouldStop := sink.Broadcast(information)
+	require.NoError(t, err)
+	require.False(t, shouldStop)
+
+	snapshot := <-received
+	require.Equal(t, schema.SchemaSnapshotV1, snapshot.Schema)
+	require.Equal(t, int64(10), snapshot.Height)
+	require.Equal(t, 1, snapshot.Round)
+
+	atomic.StoreInt32(&statusCode, http.StatusServiceUnavailable)
+	err, shouldStop = sink.Broadcast(information)
+	<-received
+	require.False(t, shouldStop)
+	statusCodeOfErr, ok := utils.GetHttpResponseStatusCode(err)
+	require.True(t, ok)
+	require.Equal(t, http.StatusServiceUnavailable, statusCodeOfErr)
+}
+
+func TestNewWebhookSink_BadUrl(t *testing.T) {
+	_, err := NewWebhookSink("ftp://example.com", nil)
+	require.Error(t, err)
+
+	_, err = NewWebhookSink("://", nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,234 @@
+package websocket_sink_impl
+
+//goland:noinspection SpellCheckingInspection
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"github.com/bcdevtools/consvp/engine/broadcast_sink"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var _ broadcast_sink.BroadcastSink = (*websocketSinkImpl)(nil)
+
+const (
+	// PathWebsocket is the path of the websocket endpoint.
+	PathWebsocket = "/ws"
+
+	// writeTimeout is the timeout of writing a message to a client, slow clients are disconnected.
+	writeTimeout = 10 * time.Second
+)
+
+//go:embed viewer.html
+var viewerHtml []byte
+
+// websocketSinkImpl serves a local websocket endpoint, each snapshot is sent to all the connected clients
+// as a JSON object of schema.SnapshotV1. The latest snapshot is sent to the new clients right after connected.
+//
+// A simple viewer page is served at the root path, for browsers.
+type websocketSinkImpl struct {
+	mutex *sync.RWMutex
+
+	listener   net.Listener
+	httpServer *http.Server
+	upgrader   websocket.Upgrader
+
+	clients        map[*websocketClient]struct{}
+	latestSnapshot []byte
+	closed         bool
+}
+
+// websocketClient is a connected client, messages are queued by a buffered channel of size 1 holding the latest snapshot,
+// so a slow client only misses the outdated snapshots.
+type websocketClient struct {
+	conn      *websocket.Conn
+	send      chan []byte
+	closeOnce sync.Once
+}
+
+// NewWebsocketSink returns a BroadcastSink which serves a websocket endpoint at PathWebsocket on the given address.
+// The listener is opened immediately, so error is returned if the address is not available.
+func NewWebsocketSink(listenAddress string) (broadcast_sink.BroadcastSink, error) {
+	listener, err := net.Listen("tcp", listenAddress)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to listen")
+	}
+
+	s := &websocketSinkImpl{
+		mutex:    &sync.RWMutex{},
+		listener: listener,
+		upgrader: websocket.Upgrader{
+			// local server for browsers, viewer pages are allowed to be served from anywhere
+			CheckOrigin: func(*http.Request) bool {
+				return true
+			},
+		},
+		clients: make(map[*websocketClient]struct{}),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(PathWebsocket, s.handleWebsocket)
+	mux.HandleFunc("/", s.handleViewer)
+
+	s.httpServer = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		_ = s.httpServer.Serve(listener)
+	}()
+
+	return s, nil
+}
+
+func (s *websocketSinkImpl) Name() string {
+	return "websocket " + s.listener.Addr().String()
+}
+
+func (s *websocketSinkImpl) Broadcast(information *enginetypes.NextBlockVotingInformation) (err error, shouldStop bool) {
+	snapshot, err := information.ToSnapshotV1(time.Now().UTC())
+	if err != nil {
+		return err, false
+	}
+
+	bz, err := json.Marshal(snapshot)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal snapshot"), false
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return fmt.Errorf("websocket sink has been closed"), true
+	}
+
+	s.latestSnapshot = bz
+	for client := range s.clients {
+		client.queue(bz)
+	}
+
+	return nil, false
+}
+
+func (s *websocketSinkImpl) Status() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	return fmt.Sprintf("%d clients", len(s.clients))
+}
+
+func (s *websocketSinkImpl) Close() error {
+	s.mutex.Lock()
+	if s.closed {
+		s.mutex.Unlock()
+		return nil
+	}
+	s.closed = true
+	clients := s.clients
+	s.clients = make(map[*websocketClient]struct{})
+	s.mutex.Unlock()
+
+	for client := range clients {
+		client.close()
+	}
+
+	return s.httpServer.Close()
+}
+
+// handleViewer handles 'GET /', serves the viewer page.
+func (s *websocketSinkImpl) handleViewer(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, _ = w.Write(viewerHtml)
+}
+
+// handleWebsocket handles 'GET /ws', upgrades the connection then keeps sending the snapshots until disconnected.
+func (s *websocketSinkImpl) handleWebsocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return // error response has been written by the upgrader
+	}
+
+	client := &websocketClient{
+		conn: conn,
+		send: make(chan []byte, 1),
+	}
+
+	s.mutex.Lock()
+	if s.closed {
+		s.mutex.Unlock()
+		_ = conn.Close()
+		return
+	}
+	s.clients[client] = struct{}{}
+	if s.latestSnapshot != nil {
+		client.queue(s.latestSnapshot)
+	}
+	s.mutex.Unlock()
+
+	go client.writeLoop()
+	client.readLoop()
+
+	s.mutex.Lock()
+	delete(s.clients, client)
+	s.mutex.Unlock()
+	client.close()
+}
+
+// queue puts the message into the send queue, replacing the queued message which has not been sent yet.
+func (c *websocketClient) queue(bz []byte) {
+	for {
+		select {
+		case c.send <- bz:
+			return
+		default:
+			select {
+			case <-c.send: // drop the outdated message
+			default:
+			}
+		}
+	}
+}
+
+// writeLoop sends the queued messages to the client until the connection is closed.
+func (c *websocketClient) writeLoop() {
+	for bz := range c.send {
+		_ = c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if err := c.conn.WriteMessage(websocket.TextMessage, bz); err != nil {
+			_ = c.conn.Close() // causes the read loop to exit
+			return
+		}
+	}
+}
+
+// readLoop reads and discards messages from the client, to process control messages, until the connection is closed.
+func (c *websocketClient) readLoop() {
+	for {
+		if _, _, err := c.conn.NextReader(); err != nil {
+			return
+		}
+	}
+}
+
+func (c *websocketClient) close() {
+	c.closeOnce.Do(func() {
+		_ = c.conn.Close()
+		close(c.send)
+	})
+}
@@ -0,0 +1,83 @@
+package websocket_sink_impl
+
+import (
+	"encoding/json"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"github.com/bcdevtools/consvp/schema"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestWebsocketSink_Broadcast(t *testing.T) {
+	sink, err := NewWebsocketSink("127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() {
+		_ = sink.Close()
+	}()
+
+	address := sink.(*websocketSinkImpl).listener.Addr().String()
+
+	readSnapshot := func(conn *websocket.Conn) schema.SnapshotV1 {
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		_, bz, err := conn.ReadMessage()
+		require.NoError(t, err)
+
+		var snapshot schema.SnapshotV1
+		require.NoError(t, json.Unmarshal(bz, &snapshot))
+		return snapshot
+	}
+
+	waitForClients := func(want string) {
+		require.Eventually(t, func() bool {
+			return sink.Status() == want
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	conn1, _, err := websocket.DefaultDialer.Dial("ws://"+address+PathWebsocket, nil)
+	require.NoError(t, err)
+	defer func() {
+		_ = conn1.Close()
+	}()
+	waitForClients("1 clients")
+
+	err, shouldStop := sink.Broadcast(&enginetypes.NextBlockVotingInformation{
+		HeightRoundStep: "10/0/1",
+	})
+	require.NoError(t, err)
+	require.False(t, shouldStop)
+	require.Equal(t, int64(10), readSnapshot(conn1).Height)
+
+	// new client receives the latest snapshot right after connected
+	conn2, _, err := websocket.DefaultDialer.Dial("ws://"+address+PathWebsocket, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(10), readSnapshot(conn2).Height)
+	waitForClients("2 clients")
+
+	_ = conn2.Close()
+	waitForClients("1 clients")
+
+	err, _ = sink.Broadcast(&enginetypes.NextBlockVotingInformation{
+		HeightRoundStep: "11/0/1",
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(11), readSnapshot(conn1).Height)
+
+	// viewer page
+	resp, err := http.Get("http://" + address + "/")
+	require.NoError(t, err)
+	bz, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Contains(t, string(bz), PathWebsocket)
+
+	require.NoError(t, sink.Close())
+	err, shouldStop = sink.Broadcast(&enginetypes.NextBlockVotingInformation{
+		HeightRoundStep: "12/0/1",
+	})
+	require.Error(t, err)
+	require.True(t, shouldStop)
+}
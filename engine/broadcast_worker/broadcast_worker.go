@@ -0,0 +1,317 @@
+package broadcast_worker
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"errors"
+	pvss "github.com/bcdevtools/consvp/engine/prevote_streaming_service"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"github.com/bcdevtools/consvp/utils"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultMinBackoff = 1 * time.Second
+	DefaultMaxBackoff = 1 * time.Minute
+
+	// latencyEwmaWeight is the weight of the latest sample when computing the average latency.
+	latencyEwmaWeight = 0.2
+)
+
+// BroadcastFunc broadcasts the given voting information.
+// It returns shouldStop=true if the broadcasting should be stopped permanently.
+type BroadcastFunc func(*enginetypes.NextBlockVotingInformation) (err error, shouldStop bool)
+
+// ResultHandler is notified after each broadcast attempt, or when the offered content is an error which is not broadcast.
+type ResultHandler func(Result)
+
+// Result is the outcome of a broadcast attempt.
+type Result struct {
+	// Err is the error of the broadcast attempt, or the offered error content if FetchingIssue.
+	Err error
+
+	// Stopped is true if the broadcasting has been stopped permanently.
+	Stopped bool
+
+	// FetchingIssue is true if the latest offered content is an error from fetching voting information, so nothing was broadcast.
+	FetchingIssue bool
+
+	Stats Stats
+}
+
+// Stats is the statistic of the worker.
+type Stats struct {
+	Offered uint64 // number of offered contents
+	Dropped uint64 // number of outdated contents dropped, because a newer one was offered before broadcast
+	Sent    uint64 // number of successful broadcasts
+	Failed  uint64 // number of failed broadcasts
+	Skipped uint64 // number of unchanged snapshots which were not sent
+
+	Pending bool // a content is waiting to be broadcast
+
+	LastLatency    time.Duration
+	AverageLatency time.Duration
+
+	ConsecutiveFailures int
+	BackoffUntil        time.Time // zero if not backing off
+}
+
+// Worker broadcasts voting information in background, independently of the producer.
+// It keeps only the latest offered content, outdated ones are dropped,
+// and applies exponential backoff with jitter when the server is overloaded or unavailable.
+type Worker struct {
+	mutex *sync.Mutex
+
+	broadcastFunc BroadcastFunc
+	resultHandler ResultHandler
+
+	minBackoff time.Duration
+	maxBackoff time.Duration
+
+	pending    interface{} // voting information or error
+	hasPending bool
+	notifyChan chan struct{}
+	stopChan   chan struct{}
+	stopped    bool
+
+	stats Stats
+
+	nowFunc    func() time.Time    // to be replaced in tests
+	jitterFunc func(n int64) int64 // to be replaced in tests
+}
+
+// NewWorker returns a new Worker, call Run to start broadcasting.
+// Zero backoff durations are replaced by the defaults.
+func NewWorker(broadcastFunc BroadcastFunc, resultHandler ResultHandler, minBackoff, maxBackoff time.Duration) *Worker {
+	if minBackoff <= 0 {
+		minBackoff = DefaultMinBackoff
+	}
+	if maxBackoff <= 0 {
+		maxBackoff = DefaultMaxBackoff
+	}
+	if maxBackoff < minBackoff {
+		maxBackoff = minBackoff
+	}
+
+	return &Worker{
+		mutex:         &sync.Mutex{},
+		broadcastFunc: broadcastFunc,
+		resultHandler: resultHandler,
+		minBackoff:    minBackoff,
+		maxBackoff:    maxBackoff,
+		notifyChan:    make(chan struct{}, 1),
+		stopChan:      make(chan struct{}),
+		nowFunc: func() time.Time {
+			return time.Now().UTC()
+		},
+		jitterFunc: rand.Int63n,
+	}
+}
+
+// Offer replaces the pending content with the given voting information or error. It never blocks.
+func (w *Worker) Offer(content interface{}) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.stopped || content == nil {
+		return
+	}
+
+	w.stats.Offered++
+	if w.hasPending {
+		w.stats.Dropped++
+	}
+	w.pending = content
+	w.hasPending = true
+	w.stats.Pending = true
+
+	select {
+	case w.notifyChan <- struct{}{}:
+	default: // already notified
+	}
+}
+
+// Run broadcasts the offered contents until stopped. It blocks so should be run in a separated goroutine.
+func (w *Worker) Run() {
+	for {
+		select {
+		case <-w.stopChan:
+			return
+		case <-w.notifyChan:
+			break
+		}
+
+		if waitDuration := w.backoffRemaining(); waitDuration > 0 {
+			select {
+			case <-w.stopChan:
+				return
+			case <-time.After(waitDuration):
+				break
+			}
+		}
+
+		content, ok := w.takePending()
+		if !ok {
+			continue
+		}
+
+		if err, isErr := content.(error); isErr {
+			w.notify(Result{
+				Err:           err,
+				FetchingIssue: true,
+				Stats:         w.Stats(),
+			})
+			continue
+		}
+
+		startTime := w.nowFunc()
+		err, shouldStop := w.broadcastFunc(content.(*enginetypes.NextBlockVotingInformation))
+		latency := w.nowFunc().Sub(startTime)
+
+		w.recordResult(err, latency)
+
+		if shouldStop {
+			w.Stop()
+		}
+
+		w.notify(Result{
+			Err:     err,
+			Stopped: shouldStop,
+			Stats:   w.Stats(),
+		})
+
+		if shouldStop {
+			return
+		}
+	}
+}
+
+// Stop stops the worker, pending content is discarded.
+func (w *Worker) Stop() {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.stopped {
+		return
+	}
+
+	w.stopped = true
+	w.pending = nil
+	w.hasPending = false
+	w.stats.Pending = false
+	close(w.stopChan)
+}
+
+// IsStopped returns true if the worker is stopped.
+func (w *Worker) IsStopped() bool {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.stopped
+}
+
+// Stats returns the current statistic.
+func (w *Worker) Stats() Stats {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	return w.stats
+}
+
+func (w *Worker) takePending() (interface{}, bool) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if !w.hasPending {
+		return nil, false
+	}
+
+	content := w.pending
+	w.pending = nil
+	w.hasPending = false
+	w.stats.Pending = false
+
+	return content, true
+}
+
+func (w *Worker) backoffRemaining() time.Duration {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.stats.BackoffUntil.IsZero() {
+		return 0
+	}
+
+	return w.stats.BackoffUntil.Sub(w.nowFunc())
+}
+
+// recordResult updates the statistic and the backoff state, following the result of a broadcast attempt.
+func (w *Worker) recordResult(err error, latency time.Duration) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if errors.Is(err, pvss.ErrSnapshotUnchanged) { // nothing was sent
+		w.stats.Skipped++
+		return
+	}
+
+	w.stats.LastLatency = latency
+	if w.stats.AverageLatency == 0 {
+		w.stats.AverageLatency = latency
+	} else {
+		w.stats.AverageLatency = time.Duration(latencyEwmaWeight*float64(latency) + (1-latencyEwmaWeight)*float64(w.stats.AverageLatency))
+	}
+
+	statusCode, hasStatusCode := utils.GetHttpResponseStatusCode(err)
+	if err == nil || (hasStatusCode && statusCode == http.StatusNotModified) {
+		w.stats.Sent++
+		w.stats.ConsecutiveFailures = 0
+		w.stats.BackoffUntil = time.Time{}
+		return
+	}
+
+	w.stats.Failed++
+	w.stats.ConsecutiveFailures++
+
+	if !shouldBackoff(err) {
+		w.stats.BackoffUntil = time.Time{}
+		return
+	}
+
+	w.stats.BackoffUntil = w.nowFunc().Add(w.backoffDuration(w.stats.ConsecutiveFailures))
+}
+
+// backoffDuration returns the exponential backoff duration with jitter, for the given number of consecutive failures.
+// The result is within [d/2, d] where d = min(maxBackoff, minBackoff * 2^(failures-1)).
+//
+// CONTRACT: caller must hold the mutex.
+func (w *Worker) backoffDuration(consecutiveFailures int) time.Duration {
+	duration := w.minBackoff
+	for i := 1; i < consecutiveFailures && duration < w.maxBackoff; i++ {
+		duration *= 2
+	}
+	if duration > w.maxBackoff {
+		duration = w.maxBackoff
+	}
+
+	half := duration / 2
+	return half + time.Duration(w.jitterFunc(int64(half)+1))
+}
+
+func (w *Worker) notify(result Result) {
+	if w.resultHandler != nil {
+		w.resultHandler(result)
+	}
+}
+
+// shouldBackoff returns true if the error indicates the server is overloaded or unavailable,
+// including rate limit (429), server errors (5xx) and transport errors.
+func shouldBackoff(err error) bool {
+	statusCode, hasStatusCode := utils.GetHttpResponseStatusCode(err)
+	if !hasStatusCode {
+		return true // transport error, like connection refused or timed out
+	}
+	return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
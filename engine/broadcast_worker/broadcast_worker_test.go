@@ -0,0 +1,205 @@
+package broadcast_worker
+
+import (
+	"fmt"
+	pvss "github.com/bcdevtools/consvp/engine/prevote_streaming_service"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"github.com/bcdevtools/consvp/utils"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func newTestVotingInfo(heightRoundStep string) *enginetypes.NextBlockVotingInformation {
+	return &enginetypes.NextBlockVotingInformation{
+		HeightRoundStep: heightRoundStep,
+	}
+}
+
+func TestWorker_CoalesceLatestValue(t *testing.T) {
+	broadcastCalled := make(chan string, 10)
+	releaseBroadcast := make(chan struct{})
+	results := make(chan Result, 10)
+
+	worker := NewWorker(func(info *enginetypes.NextBlockVotingInformation) (error, bool) {
+		broadcastCalled <- info.HeightRoundStep
+		<-releaseBroadcast
+		return nil, false
+	}, func(result Result) {
+		results <- result
+	}, 0, 0)
+	go worker.Run()
+	defer worker.Stop()
+
+	worker.Offer(newTestVotingInfo("1/0/1"))
+	require.Equal(t, "1/0/1", <-broadcastCalled)
+
+	// slow broadcast must not block the producer
+	for i := 2; i <= 5; i++ {
+		worker.Offer(newTestVotingInfo(fmt.Sprintf("%d/0/1", i)))
+	}
+	require.True(t, worker.Stats().Pending)
+
+	releaseBroadcast <- struct{}{}
+	<-results
+
+	require.Equal(t, "5/0/1", <-broadcastCalled, "only the latest content should be broadcast")
+	releaseBroadcast <- struct{}{}
+	result := <-results
+
+	require.NoError(t, result.Err)
+	require.Equal(t, uint64(5), result.Stats.Offered)
+	require.Equal(t, uint64(3), result.Stats.Dropped)
+	require.Equal(t, uint64(2), result.Stats.Sent)
+	require.Equal(t, uint64(0), result.Stats.Failed)
+	require.False(t, result.Stats.Pending)
+}
+
+func TestWorker_BackoffOnRetryableErrors(t *testing.T) {
+	results := make(chan Result, 10)
+	var broadcastTimes []time.Time
+
+	worker := NewWorker(func(info *enginetypes.NextBlockVotingInformation) (error, bool) {
+		broadcastTimes = append(broadcastTimes, time.Now())
+		if len(broadcastTimes) < 3 {
+			return &utils.HttpResponseError{StatusCode: http.StatusTooManyRequests, Message: "slow down"}, false
+		}
+		return nil, false
+	}, func(result Result) {
+		results <- result
+	}, 50*time.Millisecond, time.Second)
+	worker.jitterFunc = func(n int64) int64 {
+		return n - 1 // no jitter, always the maximum
+	}
+	go worker.Run()
+	defer worker.Stop()
+
+	worker.Offer(newTestVotingInfo("1/0/1"))
+	result := <-results
+	require.Error(t, result.Err)
+	require.Equal(t, 1, result.Stats.ConsecutiveFailures)
+	require.False(t, result.Stats.BackoffUntil.IsZero())
+
+	worker.Offer(newTestVotingInfo("1/0/2"))
+	result = <-results
+	require.Error(t, result.Err)
+	require.Equal(t, 2, result.Stats.ConsecutiveFailures)
+
+	worker.Offer(newTestVotingInfo("1/0/3"))
+	result = <-results
+	require.NoError(t, result.Err)
+	require.Equal(t, 0, result.Stats.ConsecutiveFailures)
+	require.True(t, result.Stats.BackoffUntil.IsZero())
+	require.Equal(t, uint64(2), result.Stats.Failed)
+	require.Equal(t, uint64(1), result.Stats.Sent)
+
+	require.Len(t, broadcastTimes, 3)
+	require.GreaterOrEqual(t, broadcastTimes[1].Sub(broadcastTimes[0]), 45*time.Millisecond)
+	require.GreaterOrEqual(t, broadcastTimes[2].Sub(broadcastTimes[1]), 95*time.Millisecond)
+}
+
+func TestWorker_SkippedUnchangedSnapshot(t *testing.T) {
+	results := make(chan Result, 10)
+
+	worker := NewWorker(func(info *enginetypes.NextBlockVotingInformation) (error, bool) {
+		if info.HeightRoundStep == "1/0/1" {
+			return nil, false
+		}
+		return pvss.ErrSnapshotUnchanged, false
+	}, func(result Result) {
+		results <- result
+	}, 0, 0)
+	go worker.Run()
+	defer worker.Stop()
+
+	worker.Offer(newTestVotingInfo("1/0/1"))
+	result := <-results
+	require.NoError(t, result.Err)
+
+	worker.Offer(newTestVotingInfo("1/0/2"))
+	result = <-results
+	require.ErrorIs(t, result.Err, pvss.ErrSnapshotUnchanged)
+	require.Equal(t, uint64(1), result.Stats.Sent)
+	require.Equal(t, uint64(1), result.Stats.Skipped)
+	require.Equal(t, uint64(0), result.Stats.Failed)
+	require.Equal(t, 0, result.Stats.ConsecutiveFailures)
+}
+
+func TestWorker_StopAndFetchingIssue(t *testing.T) {
+	results := make(chan Result, 10)
+
+	worker := NewWorker(func(info *enginetypes.NextBlockVotingInformation) (error, bool) {
+		return fmt.Errorf("session not found"), true
+	}, func(result Result) {
+		results <- result
+	}, 0, 0)
+
+	runReturned := make(chan struct{})
+	go func() {
+		worker.Run()
+		close(runReturned)
+	}()
+
+	worker.Offer(fmt.Errorf("failed to fetch"))
+	result := <-results
+	require.True(t, result.FetchingIssue)
+	require.ErrorContains(t, result.Err, "failed to fetch")
+	require.False(t, worker.IsStopped())
+
+	worker.Offer(newTestVotingInfo("1/0/1"))
+	result = <-results
+	require.True(t, result.Stopped)
+	require.ErrorContains(t, result.Err, "session not found")
+
+	select {
+	case <-runReturned:
+	case <-time.After(time.Second):
+		t.Fatal("Run should return after stopped")
+	}
+	require.True(t, worker.IsStopped())
+
+	worker.Offer(newTestVotingInfo("1/0/2")) // no-op
+	require.Equal(t, uint64(2), worker.Stats().Offered)
+}
+
+func TestWorker_backoffDuration(t *testing.T) {
+	worker := NewWorker(nil, nil, time.Second, 10*time.Second)
+
+	worker.jitterFunc = func(n int64) int64 {
+		return n - 1
+	}
+	require.Equal(t, time.Second, worker.backoffDuration(1))
+	require.Equal(t, 2*time.Second, worker.backoffDuration(2))
+	require.Equal(t, 4*time.Second, worker.backoffDuration(3))
+	require.Equal(t, 8*time.Second, worker.backoffDuration(4))
+	require.Equal(t, 10*time.Second, worker.backoffDuration(5))
+	require.Equal(t, 10*time.Second, worker.backoffDuration(100))
+
+	worker.jitterFunc = func(int64) int64 {
+		return 0
+	}
+	require.Equal(t, 500*time.Millisecond, worker.backoffDuration(1))
+	require.Equal(t, 5*time.Second, worker.backoffDuration(100))
+}
+
+func Test_shouldBackoff(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{fmt.Errorf("connection refused"), true},
+		{&utils.HttpResponseError{StatusCode: http.StatusTooManyRequests}, true},
+		{&utils.HttpResponseError{StatusCode: http.StatusInternalServerError}, true},
+		{&utils.HttpResponseError{StatusCode: http.StatusBadGateway}, true},
+		{&utils.HttpResponseError{StatusCode: http.StatusServiceUnavailable}, true},
+		{&utils.HttpResponseError{StatusCode: http.StatusGatewayTimeout}, true},
+		{&utils.HttpResponseError{StatusCode: http.StatusBadRequest}, false},
+		{&utils.HttpResponseError{StatusCode: http.StatusNotModified}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			require.Equal(t, tt.want, shouldBackoff(tt.err))
+		})
+	}
+}
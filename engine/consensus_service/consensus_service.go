@@ -11,6 +11,10 @@ type ConsensusService interface {
 	// Output voting information is sorted descending by voting power.
 	GetNextBlockVotingInformation(lightValidators enginetypes.LightValidators) (nextBlockVotingInfo *enginetypes.NextBlockVotingInformation, err error)
 
+	// GetSignedBlocksWindow returns the signing status of validators over the window of recent committed blocks,
+	// which ends at the given latest committed height.
+	GetSignedBlocksWindow(lightValidators enginetypes.LightValidators, latestHeight int64, windowSize int) (*enginetypes.SignedBlocksWindow, error)
+
 	// Shutdown must be called when the service is no longer needed.
 	Shutdown() error
 }
@@ -7,9 +7,11 @@ import (
 	"github.com/bcdevtools/consvp/engine/rpc_client"
 	enginetypes "github.com/bcdevtools/consvp/engine/types"
 	"github.com/pkg/errors"
+	tmtypes "github.com/tendermint/tendermint/types"
 	"regexp"
 	"sort"
 	"strings"
+	"time"
 )
 
 var _ consensus_service.ConsensusService = (*defaultConsensusServiceClientImpl)(nil) // ensure defaultConsensusServiceClientImpl implements ConsensusService interface
@@ -39,6 +41,7 @@ func (s *defaultConsensusServiceClientImpl) GetNextBlockVotingInformation(lightV
 		err = errors.Wrap(err, "failed to get consensus state")
 		return
 	}
+	capturedAtUTC := time.Now().UTC()
 
 	round, err := consensusState.GetRound()
 	if err != nil {
@@ -60,8 +63,10 @@ func (s *defaultConsensusServiceClientImpl) GetNextBlockVotingInformation(lightV
 		}
 
 		var fingerprintBlockHash string
+		var preVoteTime time.Time
 		if voted {
 			fingerprintBlockHash = extractFingerprintBlockHashVotedOn(preVote)
+			preVoteTime = extractVoteTime(preVote)
 
 			if fingerprintBlockHash == "000000000000" {
 				votedZeroes = true
@@ -78,6 +83,8 @@ func (s *defaultConsensusServiceClientImpl) GetNextBlockVotingInformation(lightV
 			VotingBlockHash: fingerprintBlockHash,
 			PreVoted:        voted,
 			VotedZeroes:     votedZeroes,
+			PreVoteTime:     preVoteTime,
+			PreVote:         preVote,
 		})
 
 		// assert index is correct
@@ -94,6 +101,11 @@ func (s *defaultConsensusServiceClientImpl) GetNextBlockVotingInformation(lightV
 		}
 
 		validatorVoteStates[i].PreCommitVoted = committed
+		validatorVoteStates[i].PreCommit = preCommit
+		if committed {
+			validatorVoteStates[i].PreCommitBlockHash = extractFingerprintBlockHashVotedOn(preCommit)
+			validatorVoteStates[i].PreCommitTime = extractVoteTime(preCommit)
+		}
 	}
 
 	preVotePercent, err := consensusState.GetPreVotePercent(round)
@@ -121,16 +133,79 @@ func (s *defaultConsensusServiceClientImpl) GetNextBlockVotingInformation(lightV
 		PreCommitPercent:          preCommitPercent,
 		HeightRoundStep:           heightRoundStep,
 		StartTimeUTC:              startTimeUTC,
+		CapturedAtUTC:             capturedAtUTC,
 	}
 
 	return
 }
 
+// GetSignedBlocksWindow returns the signing status of validators over the window of recent committed blocks,
+// which ends at the given latest committed height.
+func (s *defaultConsensusServiceClientImpl) GetSignedBlocksWindow(lightValidators enginetypes.LightValidators, latestHeight int64, windowSize int) (*enginetypes.SignedBlocksWindow, error) {
+	if windowSize < 1 {
+		return nil, fmt.Errorf("window size must be positive")
+	}
+
+	fromHeight := latestHeight - int64(windowSize) + 1
+	if fromHeight < 1 {
+		fromHeight = 1
+	}
+	if latestHeight < fromHeight {
+		return nil, fmt.Errorf("no committed block at height %d", latestHeight)
+	}
+
+	var heights []int64
+	for height := fromHeight; height <= latestHeight; height++ {
+		heights = append(heights, height)
+	}
+
+	commits, err := s.rpcClient.Commits(heights)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get commits")
+	}
+
+	return buildSignedBlocksWindow(lightValidators, fromHeight, latestHeight, commits), nil
+}
+
 // Shutdown must be called when the service is no longer needed.
 func (s *defaultConsensusServiceClientImpl) Shutdown() error {
 	return s.rpcClient.Shutdown()
 }
 
+func buildSignedBlocksWindow(lightValidators enginetypes.LightValidators, fromHeight, toHeight int64, commits map[int64]*tmtypes.Commit) *enginetypes.SignedBlocksWindow {
+	window := &enginetypes.SignedBlocksWindow{
+		FromHeight:   fromHeight,
+		ToHeight:     toHeight,
+		SignedBlocks: make(map[string][]bool),
+	}
+
+	for _, lightValidator := range lightValidators {
+		window.SignedBlocks[lightValidator.Address] = make([]bool, window.Size())
+	}
+
+	for height := fromHeight; height <= toHeight; height++ {
+		commit, found := commits[height]
+		if !found || commit == nil {
+			continue
+		}
+
+		for _, commitSig := range commit.Signatures {
+			if commitSig.BlockIDFlag != tmtypes.BlockIDFlagCommit {
+				continue
+			}
+
+			signedBlocks, tracked := window.SignedBlocks[strings.ToUpper(commitSig.ValidatorAddress.String())]
+			if !tracked {
+				continue
+			}
+
+			signedBlocks[height-fromHeight] = true
+		}
+	}
+
+	return window
+}
+
 var regexpContainsFingerprintBlockHash = regexp.MustCompile(`\s+[a-fA-F\d]{12}\s+[a-fA-F\d]{12}\s+@\s+\d{4}`)
 
 func extractFingerprintBlockHashVotedOn(voteString string) (blockHash string) {
@@ -145,3 +220,20 @@ func extractFingerprintBlockHashVotedOn(voteString string) (blockHash string) {
 
 	return
 }
+
+var regexpVoteTime = regexp.MustCompile(`@\s+(\d{4}-\d{2}-\d{2}T[^\s}]+)`)
+
+// extractVoteTime returns the timestamp of the vote, zero if not available.
+func extractVoteTime(voteString string) time.Time {
+	matches := regexpVoteTime.FindStringSubmatch(voteString)
+	if len(matches) < 2 {
+		return time.Time{}
+	}
+
+	voteTime, err := time.Parse(time.RFC3339Nano, matches[1])
+	if err != nil {
+		return time.Time{}
+	}
+
+	return voteTime.UTC()
+}
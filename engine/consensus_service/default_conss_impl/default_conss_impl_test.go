@@ -1,6 +1,14 @@
 package default_conss_impl
 
-import "testing"
+//goland:noinspection SpellCheckingInspection
+import (
+	"encoding/hex"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"github.com/stretchr/testify/require"
+	tmtypes "github.com/tendermint/tendermint/types"
+	"testing"
+	"time"
+)
 
 func Test_extractFingerprintBlockHashVotedOn(t *testing.T) {
 	//goland:noinspection SpellCheckingInspection
@@ -43,3 +51,79 @@ func Test_extractFingerprintBlockHashVotedOn(t *testing.T) {
 		})
 	}
 }
+
+func Test_extractVoteTime(t *testing.T) {
+	require.Equal(t,
+		time.Date(2017, 12, 25, 3, 0, 1, 234000000, time.UTC),
+		extractVoteTime(`Vote{56789:6AF1F4111082 12345/02/SIGNED_MSG_TYPE_PREVOTE(Prevote) 8B01023386C3 000000000000 @ 2017-12-25T03:00:01.234Z}`),
+	)
+	require.True(t, extractVoteTime(`Vote{56789:6AF1F4111082 12345/02/SIGNED_MSG_TYPE_PREVOTE(Prevote) 8B01023386C3 000000000000 # 2017-12-25T03:00:01.234Z}`).IsZero())
+	require.True(t, extractVoteTime(`Vote{56789:6AF1F4111082 12345/02/SIGNED_MSG_TYPE_PREVOTE(Prevote) 8B01023386C3 000000000000 @ 2017-12-25}`).IsZero())
+	require.True(t, extractVoteTime("nil-Vote").IsZero())
+}
+
+func Test_buildSignedBlocksWindow(t *testing.T) {
+	//goland:noinspection SpellCheckingInspection
+	const addr1 = "0A1B2C3D4E5F60718293A4B5C6D7E8F901234567"
+	//goland:noinspection SpellCheckingInspection
+	const addr2 = "1111111111111111111111111111111111111111"
+	const addr3 = "2222222222222222222222222222222222222222"
+
+	commitSig := func(address string, flag tmtypes.BlockIDFlag) tmtypes.CommitSig {
+		bz, err := hex.DecodeString(address)
+		if err != nil {
+			panic(err)
+		}
+		return tmtypes.CommitSig{
+			BlockIDFlag:      flag,
+			ValidatorAddress: bz,
+		}
+	}
+
+	lightValidators := enginetypes.LightValidators{
+		{Index: 0, Address: addr1},
+		{Index: 1, Address: addr2},
+		{Index: 2, Address: addr3},
+	}
+
+	commits := map[int64]*tmtypes.Commit{
+		10: {
+			Height: 10,
+			Signatures: []tmtypes.CommitSig{
+				commitSig(addr1, tmtypes.BlockIDFlagCommit),
+				commitSig(addr2, tmtypes.BlockIDFlagAbsent),
+				commitSig(addr3, tmtypes.BlockIDFlagCommit),
+			},
+		},
+		11: {
+			Height: 11,
+			Signatures: []tmtypes.CommitSig{
+				commitSig(addr1, tmtypes.BlockIDFlagCommit),
+				commitSig(addr2, tmtypes.BlockIDFlagCommit),
+				commitSig(addr3, tmtypes.BlockIDFlagNil),
+			},
+		},
+		// height 12 is missing
+	}
+
+	window := buildSignedBlocksWindow(lightValidators, 10, 12, commits)
+
+	require.Equal(t, int64(10), window.FromHeight)
+	require.Equal(t, int64(12), window.ToHeight)
+	require.Equal(t, 3, window.Size())
+	require.Equal(t, []bool{true, true, false}, window.GetSignedBlocks(addr1))
+	require.Equal(t, []bool{false, true, false}, window.GetSignedBlocks(addr2))
+	require.Equal(t, []bool{true, false, false}, window.GetSignedBlocks(addr3))
+	require.Equal(t, 2, window.CountSignedBlocks(addr1))
+	require.Equal(t, 0, window.CountSignedBlocks("3333333333333333333333333333333333333333"))
+}
+
+func Test_defaultConsensusServiceClientImpl_GetSignedBlocksWindow_invalidWindowSize(t *testing.T) {
+	svc := NewDefaultConsensusServiceClientImpl(nil)
+
+	for _, windowSize := range []int{0, -1} {
+		window, err := svc.GetSignedBlocksWindow(nil, 100, windowSize)
+		require.ErrorContains(t, err, "window size must be positive")
+		require.Nil(t, window)
+	}
+}
@@ -0,0 +1,346 @@
+package replay_conss_impl
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"fmt"
+	"github.com/bcdevtools/consvp/engine/consensus_service"
+	"github.com/bcdevtools/consvp/engine/recording"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"github.com/pkg/errors"
+	"sync"
+	"time"
+)
+
+var _ consensus_service.ConsensusService = (*replayConsensusServiceImpl)(nil) // ensure replayConsensusServiceImpl implements ConsensusService interface
+var _ ReplayController = (*replayConsensusServiceImpl)(nil)                   // ensure replayConsensusServiceImpl implements ReplayController interface
+
+const (
+	minSpeed = 1.0 / 16
+	maxSpeed = 64.0
+)
+
+// ReplayController controls the playback of a recording.
+type ReplayController interface {
+	// TogglePause pauses or resumes the playback.
+	TogglePause()
+
+	// SpeedUp doubles the playback speed.
+	SpeedUp()
+
+	// SlowDown halves the playback speed.
+	SlowDown()
+
+	// Seek moves the playback position forward (positive) or backward (negative).
+	Seek(delta time.Duration)
+
+	// CurrentRecord returns the record at the current playback position, following the playback clock.
+	// Records might be skipped when the playback speed is high, it is intended for interactive rendering.
+	// Returns false if the recording is empty.
+	CurrentRecord() (record ReplayedRecord, ok bool)
+
+	// NextRecord moves the playback position to the next record and returns it, so each record is returned exactly once
+	// regardless of the playback clock. Wait is the recorded interval since the previous record, scaled by the playback speed,
+	// the caller should wait for it before rendering the record.
+	// Returns false if there is no more record.
+	NextRecord() (record ReplayedRecord, wait time.Duration, ok bool)
+
+	// IsEnded returns true if the playback reached the end of the recording.
+	IsEnded() bool
+
+	// Status returns the playback status, to be rendered by the caller.
+	Status() PlaybackStatus
+}
+
+// PlaybackState is the state of the playback.
+type PlaybackState int
+
+const (
+	PlaybackStatePlaying PlaybackState = iota
+	PlaybackStatePaused
+	PlaybackStateEnded
+)
+
+// PlaybackStatus is the status of the playback.
+type PlaybackStatus struct {
+	State    PlaybackState
+	Speed    float64
+	Position time.Duration // relative to the first record
+	Duration time.Duration // of the whole recording
+}
+
+// ReplayedRecord is a voting information or error record returned by the playback.
+type ReplayedRecord struct {
+	// Index is the index of the record, among the voting information and error records.
+	Index int
+
+	// VotingInfo is the recorded voting information, nil if Err is set.
+	// The start time of the round is shifted, so the duration of the round when rendered is the same as when it was recorded.
+	VotingInfo *enginetypes.NextBlockVotingInformation
+
+	// Err is the recorded error.
+	Err error
+}
+
+// replayConsensusServiceImpl is an implementation of ConsensusService,
+// which returns the voting information from a recording, following the playback clock.
+type replayConsensusServiceImpl struct {
+	mutex *sync.Mutex
+
+	// records holds voting information and error records, ordered by time.
+	records []recording.Record
+
+	// validatorsOfRecords holds the validator set in effect of each record, to resolve the validators of the recorded votes.
+	validatorsOfRecords []enginetypes.LightValidators
+
+	speed    float64
+	paused   bool
+	position time.Duration // playback position, relative to the first record
+	duration time.Duration // duration of the recording
+
+	nextRecordIndex int // index of the record to be returned by NextRecord
+
+	lastTick time.Time        // wall clock time of the last playback position update
+	nowFunc  func() time.Time // to be replaced in tests
+}
+
+// NewReplayConsensusService returns an implementation of ConsensusService which replays the given recording.
+func NewReplayConsensusService(rec *recording.Recording, speed float64) *replayConsensusServiceImpl {
+	// records prior to the first validator set, if any, are resolved against the first one
+	var lightValidators enginetypes.LightValidators
+	for _, record := range rec.Records {
+		if record.Type == recording.RecordTypeValidators {
+			lightValidators = recording.ToLightValidators(record.Validators)
+			break
+		}
+	}
+
+	var records []recording.Record
+	var validatorsOfRecords []enginetypes.LightValidators
+	for _, record := range rec.Records {
+		if record.Type == recording.RecordTypeValidators {
+			lightValidators = recording.ToLightValidators(record.Validators)
+		} else if (record.Type == recording.RecordTypeVotingInfo && record.VotingInfo != nil) || record.Type == recording.RecordTypeError {
+			records = append(records, record)
+			validatorsOfRecords = append(validatorsOfRecords, lightValidators)
+		}
+	}
+
+	var duration time.Duration
+	if len(records) > 0 {
+		duration = records[len(records)-1].Time.Sub(records[0].Time)
+	}
+
+	s := &replayConsensusServiceImpl{
+		mutex:               &sync.Mutex{},
+		records:             records,
+		validatorsOfRecords: validatorsOfRecords,
+		speed:               normalizeSpeed(speed),
+		duration:            duration,
+		nowFunc: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+	s.lastTick = s.nowFunc()
+
+	return s
+}
+
+// GetNextBlockVotingInformation returns the voting information recorded at the current playback position.
+// The given light validators are ignored, because the recorded voting information already contains them.
+func (s *replayConsensusServiceImpl) GetNextBlockVotingInformation(_ enginetypes.LightValidators) (*enginetypes.NextBlockVotingInformation, error) {
+	record, ok := s.CurrentRecord()
+	if !ok {
+		return nil, fmt.Errorf("recording is empty")
+	}
+
+	return record.VotingInfo, record.Err
+}
+
+// GetSignedBlocksWindow is not supported while replaying,
+// the signed blocks window is included in the recorded voting information if it was enabled when recording.
+func (s *replayConsensusServiceImpl) GetSignedBlocksWindow(enginetypes.LightValidators, int64, int) (*enginetypes.SignedBlocksWindow, error) {
+	return nil, fmt.Errorf("not supported while replaying")
+}
+
+// Shutdown must be called when the service is no longer needed.
+func (s *replayConsensusServiceImpl) Shutdown() error {
+	return nil
+}
+
+// TogglePause pauses or resumes the playback.
+func (s *replayConsensusServiceImpl) TogglePause() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.advance()
+	s.paused = !s.paused
+}
+
+// SpeedUp doubles the playback speed.
+func (s *replayConsensusServiceImpl) SpeedUp() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.advance()
+	s.speed = normalizeSpeed(s.speed * 2)
+}
+
+// SlowDown halves the playback speed.
+func (s *replayConsensusServiceImpl) SlowDown() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.advance()
+	s.speed = normalizeSpeed(s.speed / 2)
+}
+
+// Seek moves the playback position forward (positive) or backward (negative).
+func (s *replayConsensusServiceImpl) Seek(delta time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.advance()
+	s.position = s.clampPosition(s.position + delta)
+}
+
+// CurrentRecord returns the record at the current playback position, following the playback clock.
+func (s *replayConsensusServiceImpl) CurrentRecord() (record ReplayedRecord, ok bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if len(s.records) < 1 {
+		return
+	}
+
+	now := s.advance()
+	return s.replayedRecord(s.currentRecordIndex(), now), true
+}
+
+// NextRecord moves the playback position to the next record and returns it, so each record is returned exactly once.
+func (s *replayConsensusServiceImpl) NextRecord() (record ReplayedRecord, wait time.Duration, ok bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.nextRecordIndex >= len(s.records) {
+		return
+	}
+
+	index := s.nextRecordIndex
+	s.nextRecordIndex++
+
+	if index > 0 {
+		wait = time.Duration(float64(s.records[index].Time.Sub(s.records[index-1].Time)) / s.speed)
+	}
+	s.position = s.clampPosition(s.records[index].Time.Sub(s.records[0].Time))
+	now := s.nowFunc()
+	s.lastTick = now
+
+	return s.replayedRecord(index, now.Add(wait)), wait, true
+}
+
+// IsEnded returns true if the playback reached the end of the recording.
+func (s *replayConsensusServiceImpl) IsEnded() bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.advance()
+	return s.position >= s.duration
+}
+
+// Status returns the playback status, to be rendered by the caller.
+func (s *replayConsensusServiceImpl) Status() PlaybackStatus {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.advance()
+
+	state := PlaybackStatePlaying
+	if s.position >= s.duration {
+		state = PlaybackStateEnded
+	} else if s.paused {
+		state = PlaybackStatePaused
+	}
+
+	return PlaybackStatus{
+		State:    state,
+		Speed:    s.speed,
+		Position: s.position,
+		Duration: s.duration,
+	}
+}
+
+// advance moves the playback position following the wall clock, returns the current wall clock time.
+//
+// CONTRACT: caller must hold the mutex.
+func (s *replayConsensusServiceImpl) advance() time.Time {
+	now := s.nowFunc()
+	if !s.paused {
+		elapsed := now.Sub(s.lastTick)
+		s.position = s.clampPosition(s.position + time.Duration(float64(elapsed)*s.speed))
+	}
+	s.lastTick = now
+	return now
+}
+
+// replayedRecord returns the record at the given index,
+// the start time of the round is shifted so the duration of the round at the render time is the same as recorded.
+//
+// CONTRACT: caller must hold the mutex.
+func (s *replayConsensusServiceImpl) replayedRecord(index int, renderAt time.Time) ReplayedRecord {
+	record := s.records[index]
+	if record.Type == recording.RecordTypeError {
+		return ReplayedRecord{
+			Index: index,
+			Err:   errors.New(record.Error),
+		}
+	}
+
+	votingInfo := record.VotingInfo.ToNextBlockVotingInformation(s.validatorsOfRecords[index])
+	votingInfo.StartTimeUTC = votingInfo.StartTimeUTC.Add(renderAt.Sub(record.Time))
+	votingInfo.CapturedAtUTC = record.Time.UTC()
+	return ReplayedRecord{
+		Index:      index,
+		VotingInfo: votingInfo,
+	}
+}
+
+// currentRecordIndex returns the index of the last record which was recorded before or at the playback position.
+//
+// CONTRACT: caller must hold the mutex.
+func (s *replayConsensusServiceImpl) currentRecordIndex() int {
+	if len(s.records) < 1 {
+		return -1
+	}
+
+	playbackTime := s.records[0].Time.Add(s.position)
+
+	index := 0
+	for i, record := range s.records {
+		if record.Time.After(playbackTime) {
+			break
+		}
+		index = i
+	}
+
+	return index
+}
+
+func (s *replayConsensusServiceImpl) clampPosition(position time.Duration) time.Duration {
+	if position < 0 {
+		return 0
+	}
+	if position > s.duration {
+		return s.duration
+	}
+	return position
+}
+
+func normalizeSpeed(speed float64) float64 {
+	if speed < minSpeed {
+		return minSpeed
+	}
+	if speed > maxSpeed {
+		return maxSpeed
+	}
+	return speed
+}
@@ -0,0 +1,237 @@
+package replay_conss_impl
+
+import (
+	"fmt"
+	"github.com/bcdevtools/consvp/engine/recording"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func Test_replayConsensusServiceImpl(t *testing.T) {
+	recordedAt := time.Date(2023, 12, 25, 3, 0, 0, 0, time.UTC)
+
+	votingInfo := func(heightRoundStep string, startTime time.Time) *recording.VotingInfo {
+		return &recording.VotingInfo{
+			HeightRoundStep: heightRoundStep,
+			StartTime:       startTime,
+		}
+	}
+
+	rec := &recording.Recording{
+		Records: []recording.Record{
+			{
+				Type:       recording.RecordTypeVotingInfo,
+				Time:       recordedAt,
+				VotingInfo: votingInfo("100/0/1", recordedAt.Add(-1*time.Second)),
+			},
+			{
+				Type:       recording.RecordTypeValidators,
+				Time:       recordedAt.Add(5 * time.Second),
+				Validators: []recording.Validator{{Index: 0}},
+			},
+			{
+				Type:       recording.RecordTypeVotingInfo,
+				Time:       recordedAt.Add(10 * time.Second),
+				VotingInfo: votingInfo("101/0/1", recordedAt.Add(8*time.Second)),
+			},
+			{
+				Type:  recording.RecordTypeError,
+				Time:  recordedAt.Add(20 * time.Second),
+				Error: "pseudo error",
+			},
+		},
+	}
+
+	wallClock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	svc := NewReplayConsensusService(rec, 1)
+	svc.nowFunc = func() time.Time {
+		return wallClock
+	}
+	svc.lastTick = wallClock
+
+	currentRecordIndex := func() int {
+		record, ok := svc.CurrentRecord()
+		require.True(t, ok)
+		return record.Index
+	}
+
+	require.Len(t, svc.records, 3, "validators record should be excluded from playback")
+	require.Equal(t, 20*time.Second, svc.duration)
+
+	info, err := svc.GetNextBlockVotingInformation(nil)
+	require.NoError(t, err)
+	require.Equal(t, "100/0/1", info.HeightRoundStep)
+	require.Equal(t, 1*time.Second, wallClock.Sub(info.StartTimeUTC), "duration of round must be the same as recorded")
+
+	wallClock = wallClock.Add(11 * time.Second)
+	info, err = svc.GetNextBlockVotingInformation(nil)
+	require.NoError(t, err)
+	require.Equal(t, "101/0/1", info.HeightRoundStep)
+	require.Equal(t, 1, currentRecordIndex())
+	require.Equal(t, 2*time.Second, wallClock.Sub(info.StartTimeUTC), "duration of round must be the same as recorded")
+
+	svc.TogglePause()
+	wallClock = wallClock.Add(time.Hour)
+	require.Equal(t, 1, currentRecordIndex(), "must not advance when paused")
+	require.Equal(t, PlaybackStatePaused, svc.Status().State)
+
+	svc.Seek(-1 * time.Hour)
+	require.Equal(t, 0, currentRecordIndex(), "seek must be clamped to the beginning")
+
+	svc.Seek(9 * time.Second)
+	require.Equal(t, 0, currentRecordIndex())
+
+	svc.TogglePause()
+	svc.SpeedUp()
+	wallClock = wallClock.Add(500 * time.Millisecond) // 1 second at 2x speed
+	require.Equal(t, 1, currentRecordIndex())
+	require.False(t, svc.IsEnded())
+	require.Equal(t, PlaybackStatus{
+		State:    PlaybackStatePlaying,
+		Speed:    2,
+		Position: 10 * time.Second,
+		Duration: 20 * time.Second,
+	}, svc.Status())
+
+	svc.Seek(time.Hour)
+	require.True(t, svc.IsEnded())
+	require.Equal(t, PlaybackStateEnded, svc.Status().State)
+
+	_, err = svc.GetNextBlockVotingInformation(nil)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "pseudo error")
+
+	for i := 0; i < 20; i++ {
+		svc.SlowDown()
+	}
+	require.Equal(t, minSpeed, svc.speed)
+	for i := 0; i < 20; i++ {
+		svc.SpeedUp()
+	}
+	require.Equal(t, maxSpeed, svc.speed)
+}
+
+func Test_replayConsensusServiceImpl_NextRecord(t *testing.T) {
+	recordedAt := time.Date(2023, 12, 25, 3, 0, 0, 0, time.UTC)
+
+	rec := &recording.Recording{}
+	for i := 0; i < 10; i++ {
+		rec.Records = append(rec.Records, recording.Record{
+			Type: recording.RecordTypeVotingInfo,
+			Time: recordedAt.Add(time.Duration(i) * time.Second),
+			VotingInfo: &recording.VotingInfo{
+				HeightRoundStep: fmt.Sprintf("%d/0/1", 100+i),
+				StartTime:       recordedAt.Add(time.Duration(i)*time.Second - 500*time.Millisecond),
+			},
+		})
+	}
+	rec.Records = append(rec.Records, recording.Record{
+		Type:  recording.RecordTypeError,
+		Time:  recordedAt.Add(10 * time.Second),
+		Error: "pseudo error",
+	})
+
+	wallClock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	svc := NewReplayConsensusService(rec, 16)
+	svc.nowFunc = func() time.Time {
+		return wallClock
+	}
+
+	for i := 0; i < 10; i++ {
+		record, wait, ok := svc.NextRecord()
+		require.True(t, ok)
+		require.Equal(t, i, record.Index, "each record must be returned exactly once, regardless of the speed")
+		require.NoError(t, record.Err)
+		require.Equal(t, fmt.Sprintf("%d/0/1", 100+i), record.VotingInfo.HeightRoundStep)
+		require.Equal(t, recordedAt.Add(time.Duration(i)*time.Second), record.VotingInfo.CapturedAtUTC, "must be the recorded time")
+		if i == 0 {
+			require.Zero(t, wait)
+		} else {
+			require.Equal(t, time.Second/16, wait, "recorded interval scaled by the speed")
+		}
+		require.Equal(t, 500*time.Millisecond, wallClock.Add(wait).Sub(record.VotingInfo.StartTimeUTC), "duration of round when rendered must be the same as recorded")
+
+		current, ok := svc.CurrentRecord()
+		require.True(t, ok)
+		require.Equal(t, i, current.Index, "playback position must follow")
+
+		wallClock = wallClock.Add(time.Hour) // wall clock must not affect
+	}
+
+	record, _, ok := svc.NextRecord()
+	require.True(t, ok)
+	require.Equal(t, 10, record.Index)
+	require.Nil(t, record.VotingInfo)
+	require.EqualError(t, record.Err, "pseudo error")
+
+	_, _, ok = svc.NextRecord()
+	require.False(t, ok)
+	require.True(t, svc.IsEnded())
+}
+
+func Test_replayConsensusServiceImpl_EmptyRecording(t *testing.T) {
+	svc := NewReplayConsensusService(&recording.Recording{}, 1)
+
+	_, err := svc.GetNextBlockVotingInformation(nil)
+	require.Error(t, err)
+	require.True(t, svc.IsEnded())
+	_, ok := svc.CurrentRecord()
+	require.False(t, ok)
+	_, _, ok = svc.NextRecord()
+	require.False(t, ok)
+}
+
+func Test_replayConsensusServiceImpl_ResolveValidators(t *testing.T) {
+	recordedAt := time.Date(2023, 12, 25, 3, 0, 0, 0, time.UTC)
+
+	votingInfoRecord := func(at time.Duration) recording.Record {
+		return recording.Record{
+			Type: recording.RecordTypeVotingInfo,
+			Time: recordedAt.Add(at),
+			VotingInfo: &recording.VotingInfo{
+				HeightRoundStep: "100/0/1",
+				Votes: []recording.Vote{
+					{ValidatorIndex: 0, ValidatorAddress: "AA", PreVoted: true},
+					{ValidatorIndex: 1, ValidatorAddress: "BB"},
+				},
+			},
+		}
+	}
+	validatorsRecord := func(at time.Duration, moniker string) recording.Record {
+		return recording.Record{
+			Type:       recording.RecordTypeValidators,
+			Time:       recordedAt.Add(at),
+			Validators: []recording.Validator{{Index: 0, Address: "AA", Moniker: moniker, VotingPower: 10}},
+		}
+	}
+
+	svc := NewReplayConsensusService(&recording.Recording{
+		Records: []recording.Record{
+			votingInfoRecord(0),
+			validatorsRecord(1*time.Second, "Val1"),
+			votingInfoRecord(2 * time.Second),
+			validatorsRecord(3*time.Second, "Val1 renamed"),
+			votingInfoRecord(4 * time.Second),
+		},
+	}, 1)
+
+	var monikers []string
+	for {
+		record, _, ok := svc.NextRecord()
+		if !ok {
+			break
+		}
+		voteStates := record.VotingInfo.SortedValidatorVoteStates
+		require.Len(t, voteStates, 2)
+		require.True(t, voteStates[0].PreVoted)
+		require.Equal(t, int64(10), voteStates[0].Validator.VotingPower)
+		require.Equal(t, enginetypes.LightValidator{Index: 1, Address: "BB"}, voteStates[1].Validator, "not found in the validator set")
+		monikers = append(monikers, voteStates[0].Validator.Moniker)
+	}
+
+	require.Equal(t, []string{"Val1", "Val1", "Val1 renamed"}, monikers, "must resolve against the latest validator set, or the first one")
+}
@@ -0,0 +1,191 @@
+package metrics
+
+//goland:noinspection SpellCheckingInspection
+import (
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"github.com/bcdevtools/consvp/schema"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// namespace is the prefix of all the metric names.
+const namespace = "cvp"
+
+// Broadcast result label values.
+const (
+	BroadcastResultSuccess = "success"
+	BroadcastResultFailure = "failure"
+	BroadcastResultSkipped = "skipped"
+)
+
+var (
+	descUp             = prometheus.NewDesc(namespace+"_up", "Whether the latest fetch of the voting information succeeded: 1 succeeded, 0 failed.", nil, nil)
+	descLastUpdateTime = prometheus.NewDesc(namespace+"_last_update_timestamp_seconds", "Unix time of the latest successful fetch of the voting information.", nil, nil)
+
+	descHeight           = prometheus.NewDesc(namespace+"_height", "Height of the block being voted.", nil, nil)
+	descRound            = prometheus.NewDesc(namespace+"_round", "Round of the block being voted.", nil, nil)
+	descStep             = prometheus.NewDesc(namespace+"_step", "Consensus step of the round.", nil, nil)
+	descPreVotePercent   = prometheus.NewDesc(namespace+"_prevote_percent", "Percent of voting power pre-voted.", nil, nil)
+	descPreCommitPercent = prometheus.NewDesc(namespace+"_precommit_percent", "Percent of voting power pre-committed.", nil, nil)
+
+	validatorLabels        = []string{"moniker", "address"}
+	descValidatorPreVote   = prometheus.NewDesc(namespace+"_validator_prevote", "Pre-vote state of the validator: 1 voted for a block, 0.5 voted for nil block, 0 missing.", validatorLabels, nil)
+	descValidatorPreCommit = prometheus.NewDesc(namespace+"_validator_precommit", "Pre-commit state of the validator: 1 voted, 0 missing.", validatorLabels, nil)
+	descValidatorVP        = prometheus.NewDesc(namespace+"_validator_voting_power", "Voting power of the validator.", validatorLabels, nil)
+)
+
+// Metrics exports the consensus and vote state in Prometheus format.
+//
+// Consensus and vote state are taken from the latest voting information at the time of scraping,
+// so series of validators which left the validator set disappear immediately.
+// They are not exported while the latest fetch failed, to not serve stale values.
+type Metrics struct {
+	mutex        *sync.RWMutex
+	latest       *enginetypes.NextBlockVotingInformation
+	latestTime   time.Time // time of the latest successful fetch
+	fetchFailing bool      // the latest fetch failed
+
+	registry *prometheus.Registry
+
+	rpcDuration *prometheus.HistogramVec
+	rpcErrors   *prometheus.CounterVec
+	broadcasts  *prometheus.CounterVec
+}
+
+var _ prometheus.Collector = (*Metrics)(nil)
+
+// NewMetrics returns a new Metrics with its own registry.
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		mutex:    &sync.RWMutex{},
+		registry: prometheus.NewRegistry(),
+		rpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "rpc_duration_seconds",
+			Help:      "Duration of the requests to the RPC server, by RPC method.",
+			Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+		}, []string{"method"}),
+		rpcErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "rpc_errors_total",
+			Help:      "Number of the failed requests to the RPC server, by RPC method.",
+		}, []string{"method"}),
+		broadcasts: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "broadcasts_total",
+			Help:      "Number of the broadcast attempts, by sink and result.",
+		}, []string{"sink", "result"}),
+	}
+
+	m.registry.MustRegister(m, m.rpcDuration, m.rpcErrors, m.broadcasts)
+
+	return m
+}
+
+// Handler returns the HTTP handler serving the metrics.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// UpdateVotingInformation sets the latest voting information to be exported.
+func (m *Metrics) UpdateVotingInformation(information *enginetypes.NextBlockVotingInformation) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.latest = information
+	m.latestTime = time.Now()
+	m.fetchFailing = false
+}
+
+// ObserveFetchFailure marks the latest fetch of the voting information as failed,
+// consensus and vote state are not exported until the next successful fetch.
+func (m *Metrics) ObserveFetchFailure() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.fetchFailing = true
+}
+
+// ObserveRpc records duration and result of a request to the RPC server, compatible with rpc_client.RpcObserver.
+func (m *Metrics) ObserveRpc(method string, duration time.Duration, err error) {
+	m.rpcDuration.WithLabelValues(method).Observe(duration.Seconds())
+	if err != nil {
+		m.rpcErrors.WithLabelValues(method).Inc()
+	}
+}
+
+// ObserveBroadcast records the result of a broadcast attempt to the sink, result is one of BroadcastResult*.
+func (m *Metrics) ObserveBroadcast(sinkName, result string) {
+	m.broadcasts.WithLabelValues(sinkName, result).Inc()
+}
+
+// Describe implements prometheus.Collector.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	ch <- descUp
+	ch <- descLastUpdateTime
+	ch <- descHeight
+	ch <- descRound
+	ch <- descStep
+	ch <- descPreVotePercent
+	ch <- descPreCommitPercent
+	ch <- descValidatorPreVote
+	ch <- descValidatorPreCommit
+	ch <- descValidatorVP
+}
+
+// Collect implements prometheus.Collector.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.mutex.RLock()
+	latest := m.latest
+	latestTime := m.latestTime
+	fetchFailing := m.fetchFailing
+	m.mutex.RUnlock()
+
+	gauge := func(desc *prometheus.Desc, value float64, labelValues ...string) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, value, labelValues...)
+	}
+
+	if fetchFailing {
+		gauge(descUp, 0)
+	} else if latest != nil {
+		gauge(descUp, 1)
+	}
+	if !latestTime.IsZero() {
+		gauge(descLastUpdateTime, float64(latestTime.UnixNano())/1e9)
+	}
+
+	if latest == nil || fetchFailing {
+		return
+	}
+
+	snapshot, err := latest.ToSnapshotV1(time.Now())
+	if err != nil {
+		return // malformed, better no value than zero height
+	}
+
+	gauge(descHeight, float64(snapshot.Height))
+	gauge(descRound, float64(snapshot.Round))
+	gauge(descStep, float64(snapshot.Step))
+	gauge(descPreVotePercent, snapshot.PreVotePercent)
+	gauge(descPreCommitPercent, snapshot.PreCommitPercent)
+
+	for _, validator := range snapshot.Validators {
+		gauge(descValidatorPreVote, voteStateValue(validator.PreVote), validator.Moniker, validator.Address)
+		gauge(descValidatorPreCommit, voteStateValue(validator.PreCommit), validator.Moniker, validator.Address)
+		gauge(descValidatorVP, float64(validator.VotingPower), validator.Moniker, validator.Address)
+	}
+}
+
+func voteStateValue(voteState schema.VoteStateV1) float64 {
+	switch voteState {
+	case schema.VoteStateV1Voted:
+		return 1
+	case schema.VoteStateV1VotedZeroes:
+		return 0.5
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,99 @@
+package metrics
+
+import (
+	"fmt"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func scrape(t *testing.T, m *Metrics) string {
+	recorder := httptest.NewRecorder()
+	m.Handler().ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	bz, err := io.ReadAll(recorder.Body)
+	require.NoError(t, err)
+	return string(bz)
+}
+
+func TestMetrics(t *testing.T) {
+	m := NewMetrics()
+
+	require.NotContains(t, scrape(t, m), "cvp_height", "no voting information yet")
+	require.NotContains(t, scrape(t, m), "cvp_up", "not fetched yet")
+
+	validator := func(index int, votingPower int64) enginetypes.LightValidator {
+		return enginetypes.LightValidator{
+			Index:       index,
+			Address:     fmt.Sprintf("%040X", index),
+			Moniker:     fmt.Sprintf("val%d", index),
+			VotingPower: votingPower,
+		}
+	}
+
+	m.UpdateVotingInformation(&enginetypes.NextBlockVotingInformation{
+		HeightRoundStep:  "100/2/6",
+		PreVotePercent:   75.5,
+		PreCommitPercent: 10,
+		SortedValidatorVoteStates: []enginetypes.ValidatorVoteState{
+			{Validator: validator(0, 30), PreVoted: true, PreCommitVoted: true, VotingBlockHash: "C0FFEE000000"},
+			{Validator: validator(1, 20), PreVoted: true, VotedZeroes: true},
+			{Validator: validator(2, 10)},
+		},
+	})
+	m.ObserveRpc("consensus_state", 120*time.Millisecond, nil)
+	m.ObserveRpc("consensus_state", time.Second, fmt.Errorf("timeout"))
+	m.ObserveBroadcast("webhook", BroadcastResultSuccess)
+	m.ObserveBroadcast("webhook", BroadcastResultFailure)
+	m.ObserveBroadcast("webhook", BroadcastResultFailure)
+
+	output := scrape(t, m)
+	for _, line := range []string{
+		"cvp_height 100",
+		"cvp_round 2",
+		"cvp_step 6",
+		"cvp_prevote_percent 75.5",
+		"cvp_precommit_percent 10",
+		`cvp_validator_prevote{address="0000000000000000000000000000000000000000",moniker="val0"} 1`,
+		`cvp_validator_prevote{address="0000000000000000000000000000000000000001",moniker="val1"} 0.5`,
+		`cvp_validator_prevote{address="0000000000000000000000000000000000000002",moniker="val2"} 0`,
+		`cvp_validator_precommit{address="0000000000000000000000000000000000000000",moniker="val0"} 1`,
+		`cvp_validator_voting_power{address="0000000000000000000000000000000000000001",moniker="val1"} 20`,
+		`cvp_rpc_duration_seconds_count{method="consensus_state"} 2`,
+		`cvp_rpc_errors_total{method="consensus_state"} 1`,
+		`cvp_broadcasts_total{result="success",sink="webhook"} 1`,
+		`cvp_broadcasts_total{result="failure",sink="webhook"} 2`,
+	} {
+		require.Contains(t, output, line+"\n")
+	}
+
+	// validators left the set are no longer exported
+	m.UpdateVotingInformation(&enginetypes.NextBlockVotingInformation{
+		HeightRoundStep: "101/0/1",
+		SortedValidatorVoteStates: []enginetypes.ValidatorVoteState{
+			{Validator: validator(0, 30)},
+		},
+	})
+	output = scrape(t, m)
+	require.Contains(t, output, "cvp_height 101\n")
+	require.NotContains(t, output, `moniker="val1"`)
+	require.Contains(t, output, "cvp_up 1\n")
+	require.Contains(t, output, "cvp_last_update_timestamp_seconds ")
+
+	// stale values are not exported while fetching fails
+	m.ObserveFetchFailure()
+	output = scrape(t, m)
+	require.Contains(t, output, "cvp_up 0\n")
+	require.Contains(t, output, "cvp_last_update_timestamp_seconds ")
+	require.NotContains(t, output, "cvp_height")
+	require.NotContains(t, output, "cvp_validator_prevote")
+
+	m.UpdateVotingInformation(&enginetypes.NextBlockVotingInformation{
+		HeightRoundStep: "102/0/1",
+	})
+	output = scrape(t, m)
+	require.Contains(t, output, "cvp_up 1\n")
+	require.Contains(t, output, "cvp_height 102\n")
+}
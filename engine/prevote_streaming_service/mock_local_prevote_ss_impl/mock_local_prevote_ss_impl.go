@@ -5,6 +5,8 @@ import (
 	"fmt"
 	ss "github.com/bcdevtools/consvp/engine/prevote_streaming_service"
 	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"github.com/bcdevtools/consvp/utils"
+	corecodec "github.com/bcdevtools/cvp-streaming-core/codec"
 	coretypes "github.com/bcdevtools/cvp-streaming-core/types"
 	"math/rand"
 	"time"
@@ -13,17 +15,20 @@ import (
 var _ ss.PreVoteStreamingService = (*mockLocalPreVoteStreamingServiceImpl)(nil)
 
 type mockLocalPreVoteStreamingServiceImpl struct {
-	chainId       string
-	sessionId     coretypes.PreVoteStreamingSessionId
-	sessionKey    coretypes.PreVoteStreamingSessionKey
-	sessionExpiry time.Time
-	stopped       bool
+	chainId               string
+	sessionId             coretypes.PreVoteStreamingSessionId
+	sessionKey            coretypes.PreVoteStreamingSessionKey
+	sessionDuration       time.Duration
+	sessionExpiry         time.Time
+	sessionRenewedHandler ss.SessionRenewedHandler
+	stopped               bool
 }
 
 func NewMockLocalPreVoteStreamingService(chainId string, sessionDuration time.Duration) ss.PreVoteStreamingService {
 	return &mockLocalPreVoteStreamingServiceImpl{
-		chainId:       chainId,
-		sessionExpiry: time.Now().UTC().Add(sessionDuration),
+		chainId:         chainId,
+		sessionDuration: sessionDuration,
+		sessionExpiry:   time.Now().UTC().Add(sessionDuration),
 	}
 }
 
@@ -53,7 +58,13 @@ func (m *mockLocalPreVoteStreamingServiceImpl) BroadcastPreVote(*enginetypes.Nex
 	}
 
 	if time.Now().UTC().After(m.sessionExpiry) {
-		return fmt.Errorf("session timed out"), true
+		if m.sessionRenewedHandler == nil {
+			return fmt.Errorf("session timed out"), true
+		}
+
+		shareViewUrl, _ := m.OpenSession(nil)
+		m.sessionExpiry = time.Now().UTC().Add(m.sessionDuration)
+		m.sessionRenewedHandler(shareViewUrl, "session expired")
 	}
 
 	r4 := rand.Uint32() % 4
@@ -61,7 +72,25 @@ func (m *mockLocalPreVoteStreamingServiceImpl) BroadcastPreVote(*enginetypes.Nex
 		return nil, false
 	}
 
-	return fmt.Errorf("mock server returns status code %d", 303+rand.Uint32()%202), false
+	statusCode := int(303 + rand.Uint32()%202)
+	return &utils.HttpResponseError{
+		StatusCode: statusCode,
+		Message:    fmt.Sprintf("mock server returns status code %d", statusCode),
+	}, false
+}
+
+func (m *mockLocalPreVoteStreamingServiceImpl) UpdateLightValidators(enginetypes.LightValidators) {
+}
+
+func (m *mockLocalPreVoteStreamingServiceImpl) SetWatchlist(enginetypes.Watchlist) {
+}
+
+func (m *mockLocalPreVoteStreamingServiceImpl) CodecVersion() corecodec.CvpCodecVersion {
+	return corecodec.NewProxyCvpCodec().GetVersion()
+}
+
+func (m *mockLocalPreVoteStreamingServiceImpl) SetSessionRenewedHandler(handler ss.SessionRenewedHandler) {
+	m.sessionRenewedHandler = handler
 }
 
 func (m *mockLocalPreVoteStreamingServiceImpl) Stop() {
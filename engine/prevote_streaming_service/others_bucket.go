@@ -0,0 +1,98 @@
+package prevote_streaming_service
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// When the validator set exceeds the streaming limit, the validators not being streamed are aggregated into an "others" bucket,
+// registered as a pseudo validator so any streaming server can render it.
+// Because the codec can not express the bucket, the truncation is also provided explicitly via the headers below,
+// so streaming servers understanding them never have to guess it from the pseudo validator.
+const (
+	// HeaderOthersBucket is the header of the registration request, provided only when the validator set is truncated.
+	HeaderOthersBucket = "X-Cvp-Others-Bucket"
+
+	// HeaderOthersBucketVotes is the header of the broadcast request, provided only when the session was registered as truncated.
+	HeaderOthersBucketVotes = "X-Cvp-Others-Bucket-Votes"
+)
+
+// OthersBucket describes the validators not being streamed, provided when registering a session with truncated validator set.
+type OthersBucket struct {
+	// Index is the streaming index of the pseudo validator representing the bucket.
+	Index int `json:"index"`
+
+	// Count is the number of validators aggregated into the bucket.
+	Count int `json:"count"`
+
+	// VotingPower is the total voting power of the validators aggregated into the bucket.
+	VotingPower int64 `json:"voting_power"`
+
+	// TotalVotingPower is the total voting power of the whole validator set.
+	TotalVotingPower int64 `json:"total_voting_power"`
+}
+
+// OthersBucketVotes is the exact voting power of the validators aggregated into the bucket those voted, provided with each broadcast.
+type OthersBucketVotes struct {
+	PreVotedVotingPower       int64 `json:"pre_voted_voting_power"`
+	PreCommitVotedVotingPower int64 `json:"pre_commit_voted_voting_power"`
+}
+
+// ValidateBasic returns error if the bucket is malformed.
+func (b OthersBucket) ValidateBasic() error {
+	if b.Index < 0 {
+		return fmt.Errorf("negative index")
+	}
+	if b.Count < 1 {
+		return fmt.Errorf("bucket must contain at least one validator")
+	}
+	if b.VotingPower < 0 || b.VotingPower > b.TotalVotingPower {
+		return fmt.Errorf("voting power must be between 0 and total voting power")
+	}
+	return nil
+}
+
+// ValidateBasic returns error if the votes are malformed or exceed voting power of the given bucket.
+func (v OthersBucketVotes) ValidateBasic(bucket OthersBucket) error {
+	if v.PreVotedVotingPower < 0 || v.PreVotedVotingPower > bucket.VotingPower {
+		return fmt.Errorf("pre-voted voting power must be between 0 and voting power of the bucket")
+	}
+	if v.PreCommitVotedVotingPower < 0 || v.PreCommitVotedVotingPower > bucket.VotingPower {
+		return fmt.Errorf("pre-commit voted voting power must be between 0 and voting power of the bucket")
+	}
+	return nil
+}
+
+// EncodeHeader encodes the given value to be used as the value of HeaderOthersBucket or HeaderOthersBucketVotes.
+func EncodeHeader(value any) string {
+	bz, err := json.Marshal(value)
+	if err != nil {
+		panic(err) // only plain structs are encoded
+	}
+	return string(bz)
+}
+
+// DecodeOthersBucketHeader decodes and validates the value of HeaderOthersBucket.
+func DecodeOthersBucketHeader(header string) (*OthersBucket, error) {
+	var bucket OthersBucket
+	if err := json.Unmarshal([]byte(header), &bucket); err != nil {
+		return nil, fmt.Errorf("malformed %s header", HeaderOthersBucket)
+	}
+	if err := bucket.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("invalid %s header: %v", HeaderOthersBucket, err)
+	}
+	return &bucket, nil
+}
+
+// DecodeOthersBucketVotesHeader decodes the value of HeaderOthersBucketVotes and validates it against the registered bucket.
+func DecodeOthersBucketVotesHeader(header string, bucket OthersBucket) (*OthersBucketVotes, error) {
+	var votes OthersBucketVotes
+	if err := json.Unmarshal([]byte(header), &votes); err != nil {
+		return nil, fmt.Errorf("malformed %s header", HeaderOthersBucketVotes)
+	}
+	if err := votes.ValidateBasic(bucket); err != nil {
+		return nil, fmt.Errorf("invalid %s header: %v", HeaderOthersBucketVotes, err)
+	}
+	return &votes, nil
+}
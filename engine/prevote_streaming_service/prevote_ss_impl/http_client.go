@@ -2,6 +2,7 @@ package prevote_ss_impl
 
 //goland:noinspection SpellCheckingInspection
 import (
+	ss "github.com/bcdevtools/consvp/engine/prevote_streaming_service"
 	coreconstants "github.com/bcdevtools/cvp-streaming-core/constants"
 	coreutils "github.com/bcdevtools/cvp-streaming-core/utils"
 	"io"
@@ -10,38 +11,47 @@ import (
 
 type preVotedStreamingHttpClient interface {
 	BaseUrl() string
-	RegisterPreVotedStreamingSession(chainId string, body io.Reader) (*http.Response, error)
+	RegisterPreVotedStreamingSession(chainId string, othersBucket *ss.OthersBucket, body io.Reader) (*http.Response, error)
 	ResumePreVotedStreamingSession(sessionId string, body io.Reader) (*http.Response, error)
-	BroadcastPreVote(sessionId, sessionKey string, body io.Reader) (*http.Response, error)
+	BroadcastPreVote(sessionId, sessionKey string, othersBucketVotes *ss.OthersBucketVotes, body io.Reader) (*http.Response, error)
 }
 
 var _ preVotedStreamingHttpClient = (*preVotedStreamingHttpClientImpl)(nil)
 
 type preVotedStreamingHttpClientImpl struct {
-	baseUrl string
+	baseUrl    string
+	httpClient *http.Client
 }
 
 func (c *preVotedStreamingHttpClientImpl) BaseUrl() string {
 	return c.baseUrl
 }
 
-func (c *preVotedStreamingHttpClientImpl) RegisterPreVotedStreamingSession(chainId string, body io.Reader) (*http.Response, error) {
-	return http.Post(
+func (c *preVotedStreamingHttpClientImpl) RegisterPreVotedStreamingSession(chainId string, othersBucket *ss.OthersBucket, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequest(
+		"POST",
 		coreutils.GetRemoteUrlRegisterPreVoteStreamingSession(c.baseUrl, chainId),
-		coreconstants.STREAMING_CONTENT_TYPE,
 		body,
 	)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", coreconstants.STREAMING_CONTENT_TYPE)
+	if othersBucket != nil {
+		req.Header.Set(ss.HeaderOthersBucket, ss.EncodeHeader(othersBucket))
+	}
+	return c.httpClient.Do(req)
 }
 
 func (c *preVotedStreamingHttpClientImpl) ResumePreVotedStreamingSession(sessionId string, body io.Reader) (*http.Response, error) {
-	return http.Post(
+	return c.httpClient.Post(
 		coreutils.GetRemoteUrlResumePreVoteStreamingSession(c.baseUrl, sessionId),
 		coreconstants.STREAMING_CONTENT_TYPE,
 		body,
 	)
 }
 
-func (c *preVotedStreamingHttpClientImpl) BroadcastPreVote(sessionId, sessionKey string, body io.Reader) (*http.Response, error) {
+func (c *preVotedStreamingHttpClientImpl) BroadcastPreVote(sessionId, sessionKey string, othersBucketVotes *ss.OthersBucketVotes, body io.Reader) (*http.Response, error) {
 	req, err := http.NewRequest(
 		"POST",
 		coreutils.GetRemoteUrlBroadcastPreVoteDuringStreamingSession(c.baseUrl, sessionId),
@@ -52,5 +62,8 @@ func (c *preVotedStreamingHttpClientImpl) BroadcastPreVote(sessionId, sessionKey
 	}
 	req.Header.Set("Content-Type", coreconstants.STREAMING_CONTENT_TYPE)
 	req.Header.Set(coreconstants.STREAMING_HEADER_SESSION_KEY, sessionKey)
-	return http.DefaultClient.Do(req)
+	if othersBucketVotes != nil {
+		req.Header.Set(ss.HeaderOthersBucketVotes, ss.EncodeHeader(othersBucketVotes))
+	}
+	return c.httpClient.Do(req)
 }
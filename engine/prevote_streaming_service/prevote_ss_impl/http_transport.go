@@ -0,0 +1,109 @@
+package prevote_ss_impl
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"github.com/pkg/errors"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// DefaultHttpTimeout is the default timeout of each request to the streaming server.
+const DefaultHttpTimeout = 30 * time.Second
+
+// HttpTransportOptions are the options of the HTTP transport used to connect to the streaming server.
+type HttpTransportOptions struct {
+	// Timeout of each request, DefaultHttpTimeout is used if zero.
+	Timeout time.Duration
+
+	// ProxyUrl is the HTTP proxy, proxy is taken from environment variables if empty.
+	ProxyUrl string
+
+	// CaFile is the path to the PEM-encoded certificate authority file,
+	// trusted in addition to the system certificate pool.
+	CaFile string
+
+	// Headers are added to each request.
+	Headers map[string]string
+}
+
+// NewHttpClient returns an HTTP client configured by the given options.
+func NewHttpClient(options HttpTransportOptions) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if len(options.ProxyUrl) > 0 {
+		proxyUrl, err := url.Parse(options.ProxyUrl)
+		if err != nil {
+			return nil, errors.Wrap(err, "bad proxy url")
+		}
+		if len(proxyUrl.Scheme) < 1 || len(proxyUrl.Host) < 1 {
+			return nil, fmt.Errorf("bad proxy url %s, must be absolute", options.ProxyUrl)
+		}
+		transport.Proxy = http.ProxyURL(proxyUrl)
+	}
+
+	if len(options.CaFile) > 0 {
+		caPem, err := os.ReadFile(options.CaFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read CA file")
+		}
+
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil || rootCAs == nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if !rootCAs.AppendCertsFromPEM(caPem) {
+			return nil, fmt.Errorf("no certificate found in CA file %s", options.CaFile)
+		}
+
+		transport.TLSClientConfig = &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			RootCAs:    rootCAs,
+		}
+	}
+
+	timeout := options.Timeout
+	if timeout <= 0 {
+		timeout = DefaultHttpTimeout
+	}
+
+	var roundTripper http.RoundTripper = transport
+	if len(options.Headers) > 0 {
+		headers := make(http.Header)
+		for key, value := range options.Headers {
+			headers.Set(key, value)
+		}
+		roundTripper = &headersRoundTripper{
+			headers: headers,
+			next:    transport,
+		}
+	}
+
+	return &http.Client{
+		Transport: roundTripper,
+		Timeout:   timeout,
+	}, nil
+}
+
+// headersRoundTripper adds the custom headers to each request, without overriding the existing ones.
+type headersRoundTripper struct {
+	headers http.Header
+	next    http.RoundTripper
+}
+
+func (rt *headersRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for key, values := range rt.headers {
+		if len(req.Header.Values(key)) > 0 {
+			continue
+		}
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+	return rt.next.RoundTrip(req)
+}
@@ -0,0 +1,75 @@
+package prevote_ss_impl
+
+import (
+	"encoding/pem"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewHttpClient(t *testing.T) {
+	var receivedHeaders http.Header
+	tlsServer := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedHeaders = r.Header.Clone()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer tlsServer.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	require.NoError(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: tlsServer.Certificate().Raw,
+	}), 0o600))
+
+	t.Run("untrusted certificate", func(t *testing.T) {
+		httpClient, err := NewHttpClient(HttpTransportOptions{})
+		require.NoError(t, err)
+		require.Equal(t, DefaultHttpTimeout, httpClient.Timeout)
+
+		_, err = httpClient.Get(tlsServer.URL)
+		require.Error(t, err)
+	})
+
+	t.Run("custom CA and headers", func(t *testing.T) {
+		httpClient, err := NewHttpClient(HttpTransportOptions{
+			Timeout: 5 * time.Second,
+			CaFile:  caFile,
+			Headers: map[string]string{
+				"Authorization": "Bearer token",
+				"X-Session-Key": "must-not-override",
+			},
+		})
+		require.NoError(t, err)
+		require.Equal(t, 5*time.Second, httpClient.Timeout)
+
+		req, err := http.NewRequest(http.MethodGet, tlsServer.URL, nil)
+		require.NoError(t, err)
+		req.Header.Set("X-Session-Key", "original")
+
+		resp, err := httpClient.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+
+		require.Equal(t, "Bearer token", receivedHeaders.Get("Authorization"))
+		require.Equal(t, "original", receivedHeaders.Get("X-Session-Key"))
+		require.Equal(t, "original", req.Header.Get("X-Session-Key"))
+		require.Empty(t, req.Header.Get("Authorization"), "original request must not be modified")
+	})
+
+	t.Run("bad options", func(t *testing.T) {
+		_, err := NewHttpClient(HttpTransportOptions{CaFile: filepath.Join(t.TempDir(), "not-exists.pem")})
+		require.Error(t, err)
+
+		emptyCaFile := filepath.Join(t.TempDir(), "empty.pem")
+		require.NoError(t, os.WriteFile(emptyCaFile, []byte("no cert"), 0o600))
+		_, err = NewHttpClient(HttpTransportOptions{CaFile: emptyCaFile})
+		require.ErrorContains(t, err, "no certificate found")
+
+		_, err = NewHttpClient(HttpTransportOptions{ProxyUrl: "proxy.local"})
+		require.ErrorContains(t, err, "must be absolute")
+	})
+}
@@ -1,6 +1,7 @@
 package prevote_ss_impl
 
 import (
+	ss "github.com/bcdevtools/consvp/engine/prevote_streaming_service"
 	"io"
 	"net/http"
 )
@@ -14,6 +15,7 @@ type mockPreVotedStreamingHttpClientImpl struct {
 
 	previousRegistrationChainId string
 	previousRegistrationPayload io.Reader
+	previousRegistrationBucket  *ss.OthersBucket
 
 	previousResumeSessionId string
 	previousResumePayload   io.Reader
@@ -21,14 +23,16 @@ type mockPreVotedStreamingHttpClientImpl struct {
 	previousBroadcastSessionId  string
 	previousBroadcastSessionKey string
 	previousBroadcastPayload    io.Reader
+	previousBroadcastVotes      *ss.OthersBucketVotes
 }
 
 func (c *mockPreVotedStreamingHttpClientImpl) BaseUrl() string {
 	return c.baseUrl
 }
 
-func (c *mockPreVotedStreamingHttpClientImpl) RegisterPreVotedStreamingSession(chainId string, payload io.Reader) (*http.Response, error) {
+func (c *mockPreVotedStreamingHttpClientImpl) RegisterPreVotedStreamingSession(chainId string, othersBucket *ss.OthersBucket, payload io.Reader) (*http.Response, error) {
 	c.previousRegistrationChainId = chainId
+	c.previousRegistrationBucket = othersBucket
 	c.previousRegistrationPayload = payload
 
 	return c.nextResponse, c.nextError
@@ -41,8 +45,9 @@ func (c *mockPreVotedStreamingHttpClientImpl) ResumePreVotedStreamingSession(ses
 	return c.nextResponse, c.nextError
 }
 
-func (c *mockPreVotedStreamingHttpClientImpl) BroadcastPreVote(sessionId, sessionKey string, payload io.Reader) (*http.Response, error) {
+func (c *mockPreVotedStreamingHttpClientImpl) BroadcastPreVote(sessionId, sessionKey string, othersBucketVotes *ss.OthersBucketVotes, payload io.Reader) (*http.Response, error) {
 	c.previousBroadcastSessionId = sessionId
+	c.previousBroadcastVotes = othersBucketVotes
 	c.previousBroadcastSessionKey = sessionKey
 	c.previousBroadcastPayload = payload
 
@@ -7,6 +7,7 @@ import (
 	"github.com/bcdevtools/consvp/constants"
 	ss "github.com/bcdevtools/consvp/engine/prevote_streaming_service"
 	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"github.com/bcdevtools/consvp/utils"
 	corecodec "github.com/bcdevtools/cvp-streaming-core/codec"
 	coretypes "github.com/bcdevtools/cvp-streaming-core/types"
 	coreutils "github.com/bcdevtools/cvp-streaming-core/utils"
@@ -14,11 +15,19 @@ import (
 	"github.com/tendermint/tendermint/libs/json"
 	"io"
 	"net/http"
+	"sync"
 	"time"
 )
 
 var _ ss.PreVoteStreamingService = (*preVoteStreamingServiceImpl)(nil)
 
+// DefaultKeyframeInterval is the maximum interval between two broadcasts of the full snapshot.
+// Unchanged snapshots are skipped, except one every keyframe interval, to keep the duration displayed by viewers up-to-date.
+//
+// Delta payload, sending only the changed validator vote states, is not supported
+// because none of the codec versions V1, V2, V3 defines a delta format, so every broadcast is a full snapshot.
+const DefaultKeyframeInterval = 10 * time.Second
+
 type preVoteStreamingServiceImpl struct {
 	// chainId is the chain ID that the upstream RPC server belong to.
 	chainId string
@@ -31,27 +40,75 @@ type preVoteStreamingServiceImpl struct {
 
 	codec corecodec.CvpCodec
 
+	// fallbackCodecs are the older codec versions to fall back to, in order,
+	// when the streaming server rejects the current codec.
+	fallbackCodecs []corecodec.CvpCodec
+
 	httpClient preVotedStreamingHttpClient
 
+	// registeredLightValidators is the validator set registered with the active session,
+	// nil if unknown, like when the session was resumed.
+	registeredLightValidators enginetypes.LightValidators
+
+	// latestLightValidators is the latest validator set provided via UpdateLightValidators.
+	latestLightValidators      enginetypes.LightValidators
+	latestLightValidatorsMutex *sync.Mutex
+
+	sessionRenewedHandler ss.SessionRenewedHandler
+
+	// watchlist validators are always streamed when the validator set exceeds the streaming limit.
+	watchlist enginetypes.Watchlist
+
+	// selection is the validators being streamed within the active session, nil if unknown.
+	selection              *validatorSelection
+	maxStreamingValidators int
+
+	// lastSnapshot is the encoded content of the last successful broadcast, excluding the duration.
+	// Used to skip broadcasting unchanged snapshot.
+	lastSnapshot     []byte
+	lastKeyframeTime time.Time
+	keyframeInterval time.Duration
+
 	stopped bool
 }
 
 // NewPreVoteStreamingService creates a new PreVoteStreamingService.
-func NewPreVoteStreamingService(chainId string, upstreamServerUrl string, optionalCodec corecodec.CvpCodec) ss.PreVoteStreamingService {
+// If codec is not provided, the codec version is negotiated: starting from the newest version,
+// fallback to the older versions when the streaming server rejects with status 415.
+// Optional HTTP client can be built by NewHttpClient, default HTTP client is used if nil.
+func NewPreVoteStreamingService(chainId string, upstreamServerUrl string, optionalCodec corecodec.CvpCodec, optionalHttpClient *http.Client) ss.PreVoteStreamingService {
 	var codec corecodec.CvpCodec
+	var fallbackCodecs []corecodec.CvpCodec
 	if optionalCodec != nil {
 		codec = optionalCodec
 	} else {
-		codec = corecodec.NewProxyCvpCodec()
+		candidates := getCodecNegotiationCandidates()
+		codec = candidates[0]
+		fallbackCodecs = candidates[1:]
+	}
+
+	var httpClient *http.Client
+	if optionalHttpClient != nil {
+		httpClient = optionalHttpClient
+	} else {
+		httpClient = http.DefaultClient
 	}
 
 	return &preVoteStreamingServiceImpl{
 		chainId: chainId,
 
-		codec: codec,
+		codec:          codec,
+		fallbackCodecs: fallbackCodecs,
+
+		keyframeInterval: DefaultKeyframeInterval,
+
+		maxStreamingValidators: defaultMaxStreamingValidators,
+
+		latestLightValidatorsMutex: &sync.Mutex{},
 
 		httpClient: &preVotedStreamingHttpClientImpl{
-			baseUrl: upstreamServerUrl,
+			baseUrl:    upstreamServerUrl,
+			httpClient: httpClient,
 		},
 	}
 }
@@ -62,49 +119,72 @@ func NewPreVoteStreamingService(chainId string, upstreamServerUrl string, option
 // If a session had been started, it no-op and returns the URL for the existing session.
 func (s *preVoteStreamingServiceImpl) OpenSession(lightValidators enginetypes.LightValidators) (shareViewUrl string, err error) {
 	if len(s.sessionKey) < 1 {
-		var streamingLightValidators coretypes.StreamingLightValidators
-		streamingLightValidators = transformLightValidatorsToStreamingLightValidators(lightValidators)
+		for {
+			err = s.registerSession(lightValidators)
+			if err == nil {
+				break
+			}
+			if !isUnsupportedCodecError(err) || !s.fallbackCodec() {
+				return "", err
+			}
+		}
+	}
 
-		encoded := s.codec.EncodeStreamingLightValidators(streamingLightValidators)
+	return coreutils.GetPublicUrlViewPreVoteStreamingSession(s.httpClient.BaseUrl(), string(s.sessionId)), nil
+}
 
-		resp, errRegister := s.httpClient.RegisterPreVotedStreamingSession(s.chainId, bytes.NewBuffer(encoded))
-		if errRegister != nil {
-			return "", errors.Wrap(errRegister, "failed to register pre-vote streaming session")
-		}
-		defer func() {
-			if resp.Body != nil {
-				_ = resp.Body.Close()
-			}
-		}()
+// registerSession registers a new session with the given validator set, using the current codec.
+func (s *preVoteStreamingServiceImpl) registerSession(lightValidators enginetypes.LightValidators) error {
+	selection := newValidatorSelection(lightValidators, s.watchlist, s.maxStreamingValidators)
 
-		err = genericHandleStatusCode(resp, http.StatusCreated, "register pre-vote streaming session")
-		if err != nil {
-			return "", err
-		}
+	var streamingLightValidators coretypes.StreamingLightValidators
+	if selection.isTruncated() {
+		streamingLightValidators = selection.transformLightValidators(lightValidators)
+	} else {
+		streamingLightValidators = transformLightValidatorsToStreamingLightValidators(lightValidators)
+	}
 
-		bz, errRead := io.ReadAll(resp.Body)
-		if errRead != nil {
-			return "", errors.Wrap(errRead, "failed to read response body")
-		}
+	encoded := s.codec.EncodeStreamingLightValidators(streamingLightValidators)
 
-		var registrationResponse coretypes.PreVoteStreamingSessionRegistrationResponse
-		errUnmarshal := json.Unmarshal(bz, &registrationResponse)
-		if errUnmarshal != nil {
-			return "", errors.Wrap(errUnmarshal, "failed to unmarshal response body")
+	resp, errRegister := s.httpClient.RegisterPreVotedStreamingSession(s.chainId, selection.othersBucket(), bytes.NewBuffer(encoded))
+	if errRegister != nil {
+		return errors.Wrap(errRegister, "failed to register pre-vote streaming session")
+	}
+	defer func() {
+		if resp.Body != nil {
+			_ = resp.Body.Close()
 		}
+	}()
 
-		if err := registrationResponse.SessionId.ValidateBasic(); err != nil {
-			return "", errors.Wrap(err, "invalid session ID")
-		}
-		if err := registrationResponse.SessionKey.ValidateBasic(); err != nil {
-			return "", errors.Wrap(err, "invalid session key")
-		}
+	err := genericHandleStatusCode(resp, http.StatusCreated, "register pre-vote streaming session")
+	if err != nil {
+		return err
+	}
 
-		s.sessionId = registrationResponse.SessionId
-		s.sessionKey = registrationResponse.SessionKey
+	bz, errRead := io.ReadAll(resp.Body)
+	if errRead != nil {
+		return errors.Wrap(errRead, "failed to read response body")
 	}
 
-	return coreutils.GetPublicUrlViewPreVoteStreamingSession(s.httpClient.BaseUrl(), string(s.sessionId)), nil
+	var registrationResponse coretypes.PreVoteStreamingSessionRegistrationResponse
+	errUnmarshal := json.Unmarshal(bz, &registrationResponse)
+	if errUnmarshal != nil {
+		return errors.Wrap(errUnmarshal, "failed to unmarshal response body")
+	}
+
+	if err := registrationResponse.SessionId.ValidateBasic(); err != nil {
+		return errors.Wrap(err, "invalid session ID")
+	}
+	if err := registrationResponse.SessionKey.ValidateBasic(); err != nil {
+		return errors.Wrap(err, "invalid session key")
+	}
+
+	s.sessionId = registrationResponse.SessionId
+	s.sessionKey = registrationResponse.SessionKey
+	s.registeredLightValidators = lightValidators
+	s.selection = selection
+
+	return nil
 }
 
 // ExposeSessionIdAndKey returns the session ID and session key. Can be used to ResumeSession.
@@ -163,12 +243,170 @@ func (s *preVoteStreamingServiceImpl) BroadcastPreVote(information *enginetypes.
 		return fmt.Errorf("service is already marked as stopped"), true
 	}
 
+	latestLightValidators := s.getLatestLightValidators()
+	if len(latestLightValidators) > 0 {
+		if s.registeredLightValidators == nil {
+			// resumed session, assume it was registered with the current validator set
+			s.registeredLightValidators = latestLightValidators
+			s.selection = newValidatorSelection(latestLightValidators, s.watchlist, s.maxStreamingValidators)
+		} else if !s.registeredLightValidators.IsSameValidatorSet(latestLightValidators) {
+			if err := s.renewSession(latestLightValidators, "validator set changed"); err != nil {
+				return err, !isRetryableRenewError(err)
+			}
+		}
+	}
+
+	err, shouldStop = s.broadcastPreVote(information)
+	for isUnsupportedCodecError(err) && s.fallbackCodec() {
+		err, shouldStop = s.broadcastPreVote(information)
+	}
+	if statusCode, ok := utils.GetHttpResponseStatusCode(err); ok && statusCode == http.StatusUnauthorized && len(s.registeredLightValidators) > 0 {
+		if errRenew := s.renewSession(s.registeredLightValidators, "session expired"); errRenew != nil {
+			return errRenew, !isRetryableRenewError(errRenew)
+		}
+
+		return s.broadcastPreVote(information)
+	}
+
+	return
+}
+
+// UpdateLightValidators provides the latest validator set.
+// If it differs from the validator set registered with the active session,
+// a new session will be registered with the new validator set before the next broadcast.
+func (s *preVoteStreamingServiceImpl) UpdateLightValidators(lightValidators enginetypes.LightValidators) {
+	s.latestLightValidatorsMutex.Lock()
+	defer s.latestLightValidatorsMutex.Unlock()
+
+	s.latestLightValidators = lightValidators
+}
+
+// SetWatchlist sets the validators to be always streamed, when the validator set exceeds the streaming limit
+// and only the top validators by voting power are streamed. Must be called before OpenSession.
+func (s *preVoteStreamingServiceImpl) SetWatchlist(watchlist enginetypes.Watchlist) {
+	s.watchlist = watchlist
+}
+
+// CodecVersion returns version of the codec in use, which is the negotiated version if codec was not specified.
+func (s *preVoteStreamingServiceImpl) CodecVersion() corecodec.CvpCodecVersion {
+	return s.codec.GetVersion()
+}
+
+// SetSessionRenewedHandler sets the handler to be notified when a new session has been registered automatically,
+// because the active session has expired or the validator set has changed.
+func (s *preVoteStreamingServiceImpl) SetSessionRenewedHandler(handler ss.SessionRenewedHandler) {
+	s.sessionRenewedHandler = handler
+}
+
+func (s *preVoteStreamingServiceImpl) getLatestLightValidators() enginetypes.LightValidators {
+	s.latestLightValidatorsMutex.Lock()
+	defer s.latestLightValidatorsMutex.Unlock()
+
+	return s.latestLightValidators
+}
+
+// renewSession registers a new session with the given validator set, to replace the active session.
+// The streaming server does not support extending a session so a new share view URL is issued.
+func (s *preVoteStreamingServiceImpl) renewSession(lightValidators enginetypes.LightValidators, reason string) error {
+	previousSessionId, previousSessionKey := s.sessionId, s.sessionKey
+	s.sessionId, s.sessionKey = "", ""
+	s.lastSnapshot = nil // the new session requires the full snapshot
+
+	shareViewUrl, err := s.OpenSession(lightValidators)
+	if err != nil {
+		// keep the previous session, to retry on next broadcast
+		s.sessionId, s.sessionKey = previousSessionId, previousSessionKey
+		return errors.Wrapf(err, "failed to renew session (%s)", reason)
+	}
+
+	if s.sessionRenewedHandler != nil {
+		s.sessionRenewedHandler(shareViewUrl, reason)
+	}
+
+	return nil
+}
+
+// fallbackCodec switches to the next older codec version. Returns false if there is no more version to fall back to.
+func (s *preVoteStreamingServiceImpl) fallbackCodec() bool {
+	if len(s.fallbackCodecs) < 1 {
+		return false
+	}
+
+	s.codec = s.fallbackCodecs[0]
+	s.fallbackCodecs = s.fallbackCodecs[1:]
+	return true
+}
+
+// getCodecNegotiationCandidates returns the codecs to be tried in order, starting from the default one which is the newest version,
+// then fallback through V3, V2 and V1.
+func getCodecNegotiationCandidates() []corecodec.CvpCodec {
+	candidates := []corecodec.CvpCodec{corecodec.NewProxyCvpCodec()}
+
+	//goland:noinspection GoDeprecation
+	for _, codec := range []corecodec.CvpCodec{
+		corecodec.GetCvpCodecV3(),
+		corecodec.GetCvpCodecV2(),
+		corecodec.GetCvpCodecV1(),
+	} {
+		var duplicated bool
+		for _, candidate := range candidates {
+			if candidate.GetVersion() == codec.GetVersion() {
+				duplicated = true
+				break
+			}
+		}
+		if !duplicated {
+			candidates = append(candidates, corecodec.WrapCvpCodecInProxy(codec))
+		}
+	}
+
+	return candidates
+}
+
+// isUnsupportedCodecError returns true if the streaming server rejected the content with status 415,
+// due to deprecated codec version or unsupported content type.
+func isUnsupportedCodecError(err error) bool {
+	statusCode, ok := utils.GetHttpResponseStatusCode(err)
+	return ok && statusCode == http.StatusUnsupportedMediaType
+}
+
+// isRetryableRenewError returns true if the failure on renewing session is temporary,
+// like the streaming server is overloaded or unavailable.
+func isRetryableRenewError(err error) bool {
+	statusCode, ok := utils.GetHttpResponseStatusCode(err)
+	if !ok {
+		return true // transport error
+	}
+	return statusCode == http.StatusTooManyRequests || statusCode >= http.StatusInternalServerError
+}
+
+func (s *preVoteStreamingServiceImpl) broadcastPreVote(information *enginetypes.NextBlockVotingInformation) (err error, shouldStop bool) {
 	var si *coretypes.StreamingNextBlockVotingInformation
 	si = transformNextBlockVotingInformationToStreamingNextBlockVotingInformation(information)
+	var othersBucketVotes *ss.OthersBucketVotes
+	if s.selection != nil && s.selection.isTruncated() {
+		si.ValidatorVoteStates, othersBucketVotes = s.selection.transformValidatorVoteStates(information.SortedValidatorVoteStates)
+	}
+
+	duration := si.Duration
+	si.Duration = 0
+	snapshot := s.codec.EncodeStreamingNextBlockVotingInformation(si)
+	si.Duration = duration
+	if othersBucketVotes != nil {
+		// the exact votes of the bucket are not encoded, must be considered when detecting unchanged snapshot
+		snapshot = append(snapshot, ss.EncodeHeader(othersBucketVotes)...)
+	}
+
+	now := time.Now().UTC()
+	if bytes.Equal(snapshot, s.lastSnapshot) && now.Sub(s.lastKeyframeTime) < s.keyframeInterval {
+		err = ss.ErrSnapshotUnchanged
+		shouldStop = false
+		return
+	}
 
 	encoded := s.codec.EncodeStreamingNextBlockVotingInformation(si)
 
-	resp, err := s.httpClient.BroadcastPreVote(string(s.sessionId), string(s.sessionKey), bytes.NewBuffer(encoded))
+	resp, err := s.httpClient.BroadcastPreVote(string(s.sessionId), string(s.sessionKey), othersBucketVotes, bytes.NewBuffer(encoded))
 	if err != nil {
 		err = errors.Wrap(err, "failed to broadcast pre-vote")
 		shouldStop = false
@@ -181,13 +419,24 @@ func (s *preVoteStreamingServiceImpl) BroadcastPreVote(information *enginetypes.
 	}()
 
 	if resp.StatusCode == http.StatusNotModified {
-		err = fmt.Errorf("upstream status has not changed, probably due to duplicated or outdated content")
+		err = &utils.HttpResponseError{
+			StatusCode: resp.StatusCode,
+			Message:    "upstream status has not changed, probably due to duplicated or outdated content",
+		}
 	} else if resp.StatusCode == http.StatusNotFound {
-		err = fmt.Errorf("session not found, please start a new streaming session")
+		err = &utils.HttpResponseError{
+			StatusCode: resp.StatusCode,
+			Message:    "session not found, please start a new streaming session",
+		}
 	} else {
 		err = genericHandleStatusCode(resp, http.StatusOK, "broadcast pre-vote")
 	}
 
+	if err == nil || resp.StatusCode == http.StatusNotModified { // server is holding this snapshot
+		s.lastSnapshot = snapshot
+		s.lastKeyframeTime = now
+	}
+
 	if err != nil {
 		shouldStop = true
 		switch resp.StatusCode {
@@ -224,27 +473,35 @@ func (s *preVoteStreamingServiceImpl) IsStopped() bool {
 func genericHandleStatusCode(resp *http.Response, acceptedStatusCode int, actionName string) error {
 	if resp.StatusCode == acceptedStatusCode {
 		return nil
-	} else if resp.StatusCode == http.StatusBadRequest { // 400
-		return fmt.Errorf("bad request")
+	}
+
+	var message string
+	if resp.StatusCode == http.StatusBadRequest { // 400
+		message = "bad request"
 	} else if resp.StatusCode == http.StatusUnauthorized { // 401
-		return fmt.Errorf("session timed out")
+		message = "session timed out"
 	} else if resp.StatusCode == http.StatusForbidden { // 403
-		return fmt.Errorf("mis-match session key")
+		message = "mis-match session key"
 	} else if resp.StatusCode == http.StatusUnsupportedMediaType { // 415
-		return fmt.Errorf("deprecated codec version or unsupported content type")
+		message = "deprecated codec version or unsupported content type"
 	} else if resp.StatusCode == http.StatusUpgradeRequired { // 426
-		return fmt.Errorf("'%s' binary upgrade is required", constants.BINARY_NAME)
+		message = fmt.Sprintf("'%s' binary upgrade is required", constants.BINARY_NAME)
 	} else if resp.StatusCode == http.StatusTooManyRequests { // 429
-		return fmt.Errorf("slow down")
+		message = "slow down"
 	} else if resp.StatusCode == http.StatusInternalServerError { // 500
-		return fmt.Errorf("internal server issue")
+		message = "internal server issue"
 	} else if resp.StatusCode == http.StatusBadGateway || // 502
 		resp.StatusCode == http.StatusServiceUnavailable { // 503
-		return fmt.Errorf("upstream server unavailable")
+		message = "upstream server unavailable"
 	} else if resp.StatusCode == http.StatusGatewayTimeout { // 504
-		return fmt.Errorf("timed out connecting to upstream server")
+		message = "timed out connecting to upstream server"
 	} else {
-		return errors.Errorf("failed to [%s], server returned status code: %d", actionName, resp.StatusCode)
+		message = fmt.Sprintf("failed to [%s], server returned status code: %d", actionName, resp.StatusCode)
+	}
+
+	return &utils.HttpResponseError{
+		StatusCode: resp.StatusCode,
+		Message:    message,
 	}
 }
 
@@ -4,6 +4,7 @@ package prevote_ss_impl
 import (
 	"bytes"
 	"fmt"
+	ss "github.com/bcdevtools/consvp/engine/prevote_streaming_service"
 	enginetypes "github.com/bcdevtools/consvp/engine/types"
 	corecodec "github.com/bcdevtools/cvp-streaming-core/codec"
 	coreconstants "github.com/bcdevtools/cvp-streaming-core/constants"
@@ -13,6 +14,8 @@ import (
 	"github.com/tendermint/tendermint/libs/json"
 	"io"
 	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -32,7 +35,7 @@ func (suite *PreVoteStreamingServiceTestSuite) SetupSuite() {
 }
 
 func (suite *PreVoteStreamingServiceTestSuite) Refresh() {
-	suite.ss = NewPreVoteStreamingService("cosmoshub-4", coreconstants.STREAMING_BASE_URL_LOCAL, nil).(*preVoteStreamingServiceImpl)
+	suite.ss = NewPreVoteStreamingService("cosmoshub-4", coreconstants.STREAMING_BASE_URL_LOCAL, nil, nil).(*preVoteStreamingServiceImpl)
 
 	// use mock HTTP client for mocking response
 	suite.httpClient = &mockPreVotedStreamingHttpClientImpl{
@@ -50,11 +53,11 @@ func (suite *PreVoteStreamingServiceTestSuite) RandomSession() {
 }
 
 func (suite *PreVoteStreamingServiceTestSuite) Test_InitDefault() {
-	ssWithDefaultCodec := NewPreVoteStreamingService("cosmoshub-4", coreconstants.STREAMING_BASE_URL_LOCAL, nil).(*preVoteStreamingServiceImpl)
+	ssWithDefaultCodec := NewPreVoteStreamingService("cosmoshub-4", coreconstants.STREAMING_BASE_URL_LOCAL, nil, nil).(*preVoteStreamingServiceImpl)
 	codecUsedByDefault := ssWithDefaultCodec.codec.GetVersion()
 
 	//goland:noinspection GoDeprecation
-	ssWithV1Codec := NewPreVoteStreamingService("cosmoshub-4", coreconstants.STREAMING_BASE_URL_LOCAL, corecodec.GetCvpCodecV1()).(*preVoteStreamingServiceImpl)
+	ssWithV1Codec := NewPreVoteStreamingService("cosmoshub-4", coreconstants.STREAMING_BASE_URL_LOCAL, corecodec.GetCvpCodecV1(), nil).(*preVoteStreamingServiceImpl)
 	v1CodecVersion := ssWithV1Codec.codec.GetVersion()
 
 	suite.NotEqual(v1CodecVersion, codecUsedByDefault)
@@ -273,6 +276,7 @@ func (suite *PreVoteStreamingServiceTestSuite) Test_OpenSession() {
 			shareViewUrl, err := suite.ss.OpenSession(tt.lightValidators)
 
 			suite.Equal(suite.ss.chainId, suite.httpClient.previousRegistrationChainId, "chain ID should be passed to HTTP client")
+			suite.Nil(suite.httpClient.previousRegistrationBucket, "others bucket must not be provided when validator set is not truncated")
 			bzPayload, errReadPayload := io.ReadAll(suite.httpClient.previousRegistrationPayload)
 			if suite.NoError(errReadPayload) {
 				if suite.NotEmpty(bzPayload) {
@@ -833,6 +837,7 @@ func (suite *PreVoteStreamingServiceTestSuite) Test_BroadcastPreVote() {
 			suite.Equal(string(suite.ss.sessionId), suite.httpClient.previousBroadcastSessionId, "session ID should be passed to HTTP client")
 			suite.NotEmpty(suite.httpClient.previousBroadcastSessionKey)
 			suite.Equal(string(suite.ss.sessionKey), suite.httpClient.previousBroadcastSessionKey, "session key should be passed to HTTP client")
+			suite.Nil(suite.httpClient.previousBroadcastVotes, "others bucket votes must not be provided when validator set is not truncated")
 			bzPayload, errReadPayload := io.ReadAll(suite.httpClient.previousBroadcastPayload)
 			if suite.NoError(errReadPayload) {
 				if suite.NotEmpty(bzPayload) {
@@ -1151,6 +1156,187 @@ func (suite *PreVoteStreamingServiceTestSuite) Test_IsStopped() {
 	})
 }
 
+func (suite *PreVoteStreamingServiceTestSuite) Test_BroadcastPreVote_SkipUnchangedSnapshot() {
+	defer func() {
+		suite.Refresh() // reset all state before coming to next test
+	}()
+
+	suite.RandomSession()
+
+	newInformation := func(preVoted bool) *enginetypes.NextBlockVotingInformation {
+		return &enginetypes.NextBlockVotingInformation{
+			SortedValidatorVoteStates: []enginetypes.ValidatorVoteState{
+				{
+					Validator: enginetypes.LightValidator{
+						Index:   0,
+						Moniker: "moniker",
+					},
+					PreVoted: preVoted,
+				},
+			},
+			HeightRoundStep: "1/0/1",
+			StartTimeUTC:    time.Now().UTC().Add(-1 * time.Second),
+		}
+	}
+
+	broadcast := func(information *enginetypes.NextBlockVotingInformation) (sent bool, err error) {
+		suite.httpClient.previousBroadcastPayload = nil
+		suite.httpClient.nextResponse = &http.Response{
+			StatusCode: http.StatusOK,
+		}
+		err, _ = suite.ss.BroadcastPreVote(information)
+		return suite.httpClient.previousBroadcastPayload != nil, err
+	}
+
+	sent, err := broadcast(newInformation(false))
+	suite.Require().NoError(err)
+	suite.True(sent)
+
+	time.Sleep(10 * time.Millisecond) // duration changed but snapshot is the same
+	sent, err = broadcast(newInformation(false))
+	suite.ErrorIs(err, ss.ErrSnapshotUnchanged)
+	suite.False(sent)
+
+	sent, err = broadcast(newInformation(true))
+	suite.Require().NoError(err)
+	suite.True(sent, "changed snapshot must be sent")
+
+	// keyframe
+	suite.ss.lastKeyframeTime = suite.ss.lastKeyframeTime.Add(-DefaultKeyframeInterval)
+	sent, err = broadcast(newInformation(true))
+	suite.Require().NoError(err)
+	suite.True(sent, "unchanged snapshot must be sent periodically")
+
+	// failed broadcast does not count
+	suite.httpClient.nextResponse = &http.Response{
+		StatusCode: http.StatusInternalServerError,
+	}
+	err, _ = suite.ss.BroadcastPreVote(newInformation(false))
+	suite.Require().Error(err)
+	sent, err = broadcast(newInformation(false))
+	suite.Require().NoError(err)
+	suite.True(sent)
+}
+
+func (suite *PreVoteStreamingServiceTestSuite) Test_CodecNegotiation() {
+	defer func() {
+		suite.Refresh() // reset all state before coming to next test
+	}()
+
+	lightValidators := enginetypes.LightValidators{
+		{
+			Index:                     0,
+			Address:                   "AA",
+			VotingPower:               100,
+			VotingPowerDisplayPercent: 100,
+			Moniker:                   "Val1",
+		},
+	}
+	information := &enginetypes.NextBlockVotingInformation{
+		HeightRoundStep: "1/0/1",
+		SortedValidatorVoteStates: []enginetypes.ValidatorVoteState{
+			{
+				Validator: lightValidators[0],
+			},
+		},
+	}
+
+	// server only accepts content encoded by the given codec versions
+	newServer := func(acceptedVersions ...corecodec.CvpCodecVersion) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			bz, _ := io.ReadAll(r.Body)
+			version, detected := corecodec.DetectEncodingVersion(bz)
+
+			var accepted bool
+			for _, acceptedVersion := range acceptedVersions {
+				if detected && version == acceptedVersion {
+					accepted = true
+					break
+				}
+			}
+			if !accepted {
+				w.WriteHeader(http.StatusUnsupportedMediaType)
+				return
+			}
+
+			if !strings.Contains(r.URL.Path, "register-session") {
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+
+			sessionId, sessionKey, err := coretypes.NewPreVoteStreamingSession("cosmoshub-4")
+			suite.Require().NoError(err)
+			bz, err = json.Marshal(coretypes.PreVoteStreamingSessionRegistrationResponse{
+				SessionId:  sessionId,
+				SessionKey: sessionKey,
+			})
+			suite.Require().NoError(err)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write(bz)
+		}))
+	}
+
+	suite.Run("use the newest version by default", func() {
+		server := newServer(corecodec.CvpCodecVersionV3, corecodec.CvpCodecVersionV2, corecodec.CvpCodecVersionV1)
+		defer server.Close()
+
+		pvs := NewPreVoteStreamingService("cosmoshub-4", server.URL, nil, nil)
+		_, err := pvs.OpenSession(lightValidators)
+		suite.Require().NoError(err)
+		suite.Equal(corecodec.NewProxyCvpCodec().GetVersion(), pvs.CodecVersion())
+	})
+
+	suite.Run("fallback to older versions on register", func() {
+		server := newServer(corecodec.CvpCodecVersionV1)
+		defer server.Close()
+
+		pvs := NewPreVoteStreamingService("cosmoshub-4", server.URL, nil, nil)
+		_, err := pvs.OpenSession(lightValidators)
+		suite.Require().NoError(err)
+		suite.Equal(corecodec.CvpCodecVersionV1, pvs.CodecVersion())
+
+		err, shouldStop := pvs.BroadcastPreVote(information)
+		suite.NoError(err)
+		suite.False(shouldStop)
+	})
+
+	suite.Run("fallback to older versions on broadcast", func() {
+		server := newServer(corecodec.CvpCodecVersionV2)
+		defer server.Close()
+
+		pvs := NewPreVoteStreamingService("cosmoshub-4", server.URL, nil, nil).(*preVoteStreamingServiceImpl)
+		var err error
+		pvs.sessionId, pvs.sessionKey, err = coretypes.NewPreVoteStreamingSession("cosmoshub-4")
+		suite.Require().NoError(err)
+
+		err, shouldStop := pvs.BroadcastPreVote(information)
+		suite.NoError(err)
+		suite.False(shouldStop)
+		suite.Equal(corecodec.CvpCodecVersionV2, pvs.CodecVersion())
+	})
+
+	suite.Run("no fallback when codec specified", func() {
+		server := newServer(corecodec.CvpCodecVersionV2)
+		defer server.Close()
+
+		pvs := NewPreVoteStreamingService("cosmoshub-4", server.URL, corecodec.WrapCvpCodecInProxy(corecodec.GetCvpCodecV3()), nil)
+		_, err := pvs.OpenSession(lightValidators)
+		suite.Require().Error(err)
+		suite.Contains(err.Error(), "deprecated codec version or unsupported content type")
+		suite.Equal(corecodec.CvpCodecVersionV3, pvs.CodecVersion())
+	})
+
+	suite.Run("no more version to fallback", func() {
+		server := newServer()
+		defer server.Close()
+
+		pvs := NewPreVoteStreamingService("cosmoshub-4", server.URL, nil, nil)
+		_, err := pvs.OpenSession(lightValidators)
+		suite.Require().Error(err)
+		suite.Equal(corecodec.CvpCodecVersionV1, pvs.CodecVersion())
+	})
+}
+
 var _ io.ReadCloser = (*mockClosedReadCloser)(nil)
 
 type mockClosedReadCloser struct {
@@ -0,0 +1,183 @@
+package prevote_ss_impl
+
+//goland:noinspection SpellCheckingInspection
+import (
+	ss "github.com/bcdevtools/consvp/engine/prevote_streaming_service"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	coreconstants "github.com/bcdevtools/cvp-streaming-core/constants"
+	coretypes "github.com/bcdevtools/cvp-streaming-core/types"
+	"sort"
+)
+
+// defaultMaxStreamingValidators is the maximum number of validators can be streamed, including the "others" pseudo validator.
+const defaultMaxStreamingValidators = coreconstants.MAX_VALIDATORS
+
+// validatorSelection is the validators being streamed within a session.
+//
+// When the validator set does not exceed the streaming limit, all validators are streamed using their original index.
+// Otherwise, the watchlist validators & the top validators by voting power are streamed, re-indexed from zero,
+// and the remaining validators are aggregated into an "others" pseudo validator at the last index.
+type validatorSelection struct {
+	// streamingIndexByIndex maps index of the validator in the validator set to the index used for streaming.
+	streamingIndexByIndex map[int]int
+
+	// othersIndex is the streaming index of the "others" pseudo validator, -1 if the validator set is not truncated.
+	othersIndex       int
+	othersCount       int
+	othersVotingPower int64
+	totalVotingPower  int64
+}
+
+// newValidatorSelection selects the validators to be streamed, with at most maxValidators entries including the "others" pseudo validator.
+func newValidatorSelection(lightValidators enginetypes.LightValidators, watchlist enginetypes.Watchlist, maxValidators int) *validatorSelection {
+	selection := &validatorSelection{
+		streamingIndexByIndex: make(map[int]int, len(lightValidators)),
+		othersIndex:           -1,
+	}
+
+	if len(lightValidators) <= maxValidators {
+		for _, lv := range lightValidators {
+			selection.streamingIndexByIndex[lv.Index] = lv.Index
+		}
+		return selection
+	}
+
+	// watchlist first, then the top by voting power
+	candidates := append(enginetypes.LightValidators{}, lightValidators...)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		iWatched, jWatched := watchlist.Contains(candidates[i]), watchlist.Contains(candidates[j])
+		if iWatched != jWatched {
+			return iWatched
+		}
+		return candidates[i].VotingPower > candidates[j].VotingPower
+	})
+
+	slots := maxValidators - 1 // reserved for the "others" pseudo validator
+	selected := make(map[int]bool, slots)
+	for _, lv := range candidates[:slots] {
+		selected[lv.Index] = true
+	}
+
+	// keep the original order
+	for _, lv := range lightValidators {
+		selection.totalVotingPower += lv.VotingPower
+		if selected[lv.Index] {
+			selection.streamingIndexByIndex[lv.Index] = len(selection.streamingIndexByIndex)
+		} else {
+			selection.othersCount++
+			selection.othersVotingPower += lv.VotingPower
+		}
+	}
+	selection.othersIndex = len(selection.streamingIndexByIndex)
+
+	return selection
+}
+
+// isTruncated returns true if the validator set exceeds the streaming limit and the remaining validators are aggregated.
+func (vs *validatorSelection) isTruncated() bool {
+	return vs.othersIndex >= 0
+}
+
+// transformLightValidators returns the streaming light validators of the selection.
+func (vs *validatorSelection) transformLightValidators(lightValidators enginetypes.LightValidators) coretypes.StreamingLightValidators {
+	var streamingLightValidators coretypes.StreamingLightValidators
+	for _, lightValidator := range lightValidators {
+		streamingIndex, selected := vs.streamingIndexByIndex[lightValidator.Index]
+		if !selected {
+			continue
+		}
+		streamingLightValidators = append(streamingLightValidators, coretypes.StreamingLightValidator{
+			Index:                     streamingIndex,
+			VotingPowerDisplayPercent: lightValidator.VotingPowerDisplayPercent,
+			Moniker:                   lightValidator.Moniker,
+		})
+	}
+
+	if vs.isTruncated() {
+		streamingLightValidators = append(streamingLightValidators, coretypes.StreamingLightValidator{
+			Index:                     vs.othersIndex,
+			VotingPowerDisplayPercent: vs.percentOfTotal(vs.othersVotingPower),
+			Moniker:                   ss.GetOthersBucketMoniker(vs.othersCount),
+		})
+	}
+
+	return streamingLightValidators
+}
+
+// othersBucket returns the description of the "others" pseudo validator to be provided when registering the session,
+// nil if the validator set is not truncated.
+func (vs *validatorSelection) othersBucket() *ss.OthersBucket {
+	if !vs.isTruncated() {
+		return nil
+	}
+	return &ss.OthersBucket{
+		Index:            vs.othersIndex,
+		Count:            vs.othersCount,
+		VotingPower:      vs.othersVotingPower,
+		TotalVotingPower: vs.totalVotingPower,
+	}
+}
+
+// transformValidatorVoteStates returns the streaming vote states of the selection,
+// and the exact voted voting power of the "others" pseudo validator, nil if the validator set is not truncated.
+// Since the codec can only carry a boolean per validator, the "others" pseudo validator is marked as voted
+// when more than 2/3 voting power of the aggregated validators voted, for streaming servers not understanding the bucket.
+func (vs *validatorSelection) transformValidatorVoteStates(voteStates []enginetypes.ValidatorVoteState) ([]coretypes.StreamingValidatorVoteState, *ss.OthersBucketVotes) {
+	var streamingVoteStates []coretypes.StreamingValidatorVoteState
+	var othersPreVoted, othersVotedZeroes, othersPreCommitVoted int64
+
+	for _, voteState := range voteStates {
+		streamingIndex, selected := vs.streamingIndexByIndex[voteState.Validator.Index]
+		if !selected {
+			if voteState.PreVoted {
+				othersPreVoted += voteState.Validator.VotingPower
+			}
+			if voteState.VotedZeroes {
+				othersVotedZeroes += voteState.Validator.VotingPower
+			}
+			if voteState.PreCommitVoted {
+				othersPreCommitVoted += voteState.Validator.VotingPower
+			}
+			continue
+		}
+
+		var blockHash string
+		if len(voteState.VotingBlockHash) > 0 {
+			blockHash = voteState.VotingBlockHash[:4]
+		}
+		streamingVoteStates = append(streamingVoteStates, coretypes.StreamingValidatorVoteState{
+			ValidatorIndex:    streamingIndex,
+			PreVotedBlockHash: blockHash,
+			PreVoted:          voteState.PreVoted,
+			VotedZeroes:       voteState.VotedZeroes,
+			PreCommitVoted:    voteState.PreCommitVoted,
+		})
+	}
+
+	if !vs.isTruncated() {
+		return streamingVoteStates, nil
+	}
+
+	streamingVoteStates = append(streamingVoteStates, coretypes.StreamingValidatorVoteState{
+		ValidatorIndex: vs.othersIndex,
+		PreVoted:       othersPreVoted*3 > vs.othersVotingPower*2,
+		VotedZeroes:    othersVotedZeroes*3 > vs.othersVotingPower*2,
+		PreCommitVoted: othersPreCommitVoted*3 > vs.othersVotingPower*2,
+	})
+
+	return streamingVoteStates, &ss.OthersBucketVotes{
+		PreVotedVotingPower:       othersPreVoted,
+		PreCommitVotedVotingPower: othersPreCommitVoted,
+	}
+}
+
+func (vs *validatorSelection) percentOfTotal(votingPower int64) float64 {
+	if vs.totalVotingPower < 1 {
+		return 0
+	}
+	percent := float64(votingPower) * 100 / float64(vs.totalVotingPower)
+	if percent > 100 {
+		percent = 100
+	}
+	return percent
+}
@@ -0,0 +1,101 @@
+package prevote_ss_impl
+
+import (
+	"fmt"
+	ss "github.com/bcdevtools/consvp/engine/prevote_streaming_service"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func newTestLightValidators(votingPowers ...int64) enginetypes.LightValidators {
+	var lightValidators enginetypes.LightValidators
+	for i, votingPower := range votingPowers {
+		lightValidators = append(lightValidators, enginetypes.LightValidator{
+			Index:       i,
+			Moniker:     fmt.Sprintf("val%d", i),
+			Address:     fmt.Sprintf("%040X", i),
+			VotingPower: votingPower,
+		})
+	}
+	return lightValidators
+}
+
+func Test_newValidatorSelection(t *testing.T) {
+	t.Run("not exceeds the limit", func(t *testing.T) {
+		lightValidators := newTestLightValidators(30, 20, 10)
+
+		selection := newValidatorSelection(lightValidators, nil, 3)
+		require.False(t, selection.isTruncated())
+		require.Equal(t, map[int]int{0: 0, 1: 1, 2: 2}, selection.streamingIndexByIndex)
+		require.Equal(t, transformLightValidatorsToStreamingLightValidators(lightValidators), selection.transformLightValidators(lightValidators))
+		require.Nil(t, selection.othersBucket())
+	})
+
+	t.Run("top by voting power and watchlist", func(t *testing.T) {
+		lightValidators := newTestLightValidators(50, 40, 30, 5, 20, 10, 1)
+		watchlist := enginetypes.Watchlist{"val6", fmt.Sprintf("%040x", 5)}
+
+		selection := newValidatorSelection(lightValidators, watchlist, 5)
+		require.True(t, selection.isTruncated())
+
+		// 4 slots: 2 watched + top 2 by voting power, keep the original order
+		require.Equal(t, map[int]int{0: 0, 1: 1, 5: 2, 6: 3}, selection.streamingIndexByIndex)
+		require.Equal(t, 4, selection.othersIndex)
+		require.Equal(t, 3, selection.othersCount)
+		require.Equal(t, int64(55), selection.othersVotingPower)
+		require.Equal(t, int64(156), selection.totalVotingPower)
+
+		streamingLightValidators := selection.transformLightValidators(lightValidators)
+		require.Len(t, streamingLightValidators, 5)
+		require.Equal(t, "val5", streamingLightValidators[2].Moniker)
+		require.Equal(t, 2, streamingLightValidators[2].Index)
+
+		others := streamingLightValidators[4]
+		require.Equal(t, 4, others.Index)
+		require.Equal(t, ss.GetOthersBucketMoniker(3), others.Moniker)
+		require.InDelta(t, 55.0*100/156, others.VotingPowerDisplayPercent, 0.0001)
+		require.Equal(t, &ss.OthersBucket{
+			Index:            4,
+			Count:            3,
+			VotingPower:      55,
+			TotalVotingPower: 156,
+		}, selection.othersBucket())
+
+		voteState := func(index int, preVoted bool) enginetypes.ValidatorVoteState {
+			return enginetypes.ValidatorVoteState{
+				Validator:       lightValidators[index],
+				PreVoted:        preVoted,
+				PreCommitVoted:  preVoted,
+				VotingBlockHash: "C0FFEE000000",
+			}
+		}
+
+		// others: 30 + 20 of 55 pre-voted, more than 2/3
+		streamingVoteStates, othersVotes := selection.transformValidatorVoteStates([]enginetypes.ValidatorVoteState{
+			voteState(0, true), voteState(1, false), voteState(2, true), voteState(3, false), voteState(4, true), voteState(5, true), voteState(6, false),
+		})
+		require.Len(t, streamingVoteStates, 5)
+		require.Equal(t, 0, streamingVoteStates[0].ValidatorIndex)
+		require.Equal(t, "C0FF", streamingVoteStates[0].PreVotedBlockHash)
+		require.Equal(t, 2, streamingVoteStates[2].ValidatorIndex)
+		require.True(t, streamingVoteStates[2].PreVoted)
+		require.Equal(t, 3, streamingVoteStates[3].ValidatorIndex)
+		require.False(t, streamingVoteStates[3].PreVoted)
+		require.Equal(t, 4, streamingVoteStates[4].ValidatorIndex)
+		require.True(t, streamingVoteStates[4].PreVoted)
+		require.True(t, streamingVoteStates[4].PreCommitVoted)
+		require.Empty(t, streamingVoteStates[4].PreVotedBlockHash)
+		require.Equal(t, &ss.OthersBucketVotes{
+			PreVotedVotingPower:       50,
+			PreCommitVotedVotingPower: 50,
+		}, othersVotes)
+
+		// others: 30 of 55 pre-voted, not more than 2/3
+		streamingVoteStates, othersVotes = selection.transformValidatorVoteStates([]enginetypes.ValidatorVoteState{
+			voteState(2, true), voteState(3, false), voteState(4, false),
+		})
+		require.False(t, streamingVoteStates[len(streamingVoteStates)-1].PreVoted)
+		require.Equal(t, int64(30), othersVotes.PreVotedVotingPower)
+	})
+}
@@ -2,7 +2,10 @@ package prevote_streaming_service
 
 //goland:noinspection SpellCheckingInspection
 import (
+	"errors"
+	"fmt"
 	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	corecodec "github.com/bcdevtools/cvp-streaming-core/codec"
 	coretypes "github.com/bcdevtools/cvp-streaming-core/types"
 )
 
@@ -28,9 +31,38 @@ type PreVoteStreamingService interface {
 	// It returns shouldStop=true if the broadcasting should be stopped.
 	BroadcastPreVote(*enginetypes.NextBlockVotingInformation) (err error, shouldStop bool)
 
+	// UpdateLightValidators provides the latest validator set.
+	// If it differs from the validator set registered with the active session,
+	// a new session will be registered with the new validator set before the next broadcast.
+	UpdateLightValidators(lightValidators enginetypes.LightValidators)
+
+	// CodecVersion returns version of the codec in use, which is the negotiated version if codec was not specified.
+	CodecVersion() corecodec.CvpCodecVersion
+
+	// SetWatchlist sets the validators to be always streamed, when the validator set exceeds the streaming limit
+	// and only the top validators by voting power are streamed. Must be called before OpenSession.
+	SetWatchlist(watchlist enginetypes.Watchlist)
+
+	// SetSessionRenewedHandler sets the handler to be notified when a new session has been registered automatically,
+	// because the active session has expired or the validator set has changed.
+	SetSessionRenewedHandler(handler SessionRenewedHandler)
+
 	// Stop tells the service to stop.
 	Stop()
 
 	// IsStopped returns true if the service is stopped.
 	IsStopped() bool
 }
+
+// ErrSnapshotUnchanged is returned by BroadcastPreVote when the snapshot was not sent
+// because it has not changed since the previous broadcast.
+var ErrSnapshotUnchanged = errors.New("snapshot has not changed since the previous broadcast, skipped")
+
+// GetOthersBucketMoniker returns the moniker of the pseudo validator which aggregates the given number of validators not being streamed,
+// when the validator set exceeds the streaming limit. It is for display only, see OthersBucket.
+func GetOthersBucketMoniker(othersCount int) string {
+	return fmt.Sprintf("+%d others", othersCount)
+}
+
+// SessionRenewedHandler is notified with the new share view URL and the reason, when a new session has been registered automatically.
+type SessionRenewedHandler func(shareViewUrl string, reason string)
@@ -0,0 +1,98 @@
+package recording
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"encoding/json"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"github.com/pkg/errors"
+	"os"
+	"sync"
+	"time"
+)
+
+// Recorder persists every snapshot of the next block voting information into a file, for replaying later.
+type Recorder struct {
+	mutex   *sync.Mutex
+	file    *os.File
+	encoder *json.Encoder
+}
+
+// NewRecorder creates a new Recorder, which writes records into the given file.
+// The file will be truncated if exists.
+func NewRecorder(filePath string, chainId, consensusVersion, moniker string) (*Recorder, error) {
+	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open recording file")
+	}
+
+	recorder := &Recorder{
+		mutex:   &sync.Mutex{},
+		file:    file,
+		encoder: json.NewEncoder(file),
+	}
+
+	err = recorder.write(Record{
+		Type: RecordTypeHeader,
+		Time: time.Now().UTC(),
+		Header: &Header{
+			Version:          recordingFormatVersion,
+			ChainId:          chainId,
+			ConsensusVersion: consensusVersion,
+			Moniker:          moniker,
+		},
+	})
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	return recorder, nil
+}
+
+// RecordLightValidators records the validator set.
+func (r *Recorder) RecordLightValidators(lightValidators enginetypes.LightValidators) error {
+	return r.write(Record{
+		Type:       RecordTypeValidators,
+		Time:       time.Now().UTC(),
+		Validators: NewValidators(lightValidators),
+	})
+}
+
+// RecordVotingInfo records the content to be rendered, accept both voting information and error.
+func (r *Recorder) RecordVotingInfo(content interface{}) error {
+	record := Record{
+		Time: time.Now().UTC(),
+	}
+
+	switch c := content.(type) {
+	case *enginetypes.NextBlockVotingInformation:
+		record.Type = RecordTypeVotingInfo
+		record.VotingInfo = NewVotingInfo(c)
+	case error:
+		record.Type = RecordTypeError
+		record.Error = c.Error()
+	default:
+		return errors.Errorf("not supported content type %T", content)
+	}
+
+	return r.write(record)
+}
+
+// Close closes the recording file.
+func (r *Recorder) Close() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.file.Close()
+}
+
+func (r *Recorder) write(record Record) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if err := r.encoder.Encode(record); err != nil {
+		return errors.Wrap(err, "failed to write record")
+	}
+
+	return nil
+}
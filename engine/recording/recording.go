@@ -0,0 +1,110 @@
+package recording
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	"os"
+	"time"
+)
+
+// RecordType is the type of record.
+type RecordType string
+
+const (
+	// RecordTypeHeader is the first record of a recording, holds information of the recorded chain.
+	RecordTypeHeader RecordType = "header"
+	// RecordTypeValidators holds the validator set at the time of recording.
+	RecordTypeValidators RecordType = "validators"
+	// RecordTypeVotingInfo holds a snapshot of the next block voting information.
+	RecordTypeVotingInfo RecordType = "voting-info"
+	// RecordTypeError holds an error occurred when fetching the next block voting information.
+	RecordTypeError RecordType = "error"
+)
+
+// recordingFormatVersion is the version of the recording file format.
+const recordingFormatVersion = 1
+
+// Record is a single line of a recording file, encoded as JSON.
+type Record struct {
+	Type RecordType `json:"type"`
+	Time time.Time  `json:"time"`
+
+	Header     *Header     `json:"header,omitempty"`
+	Validators []Validator `json:"validators,omitempty"`
+	VotingInfo *VotingInfo `json:"voting_info,omitempty"`
+	Error      string      `json:"error,omitempty"`
+}
+
+// Header holds information of the recorded chain.
+type Header struct {
+	Version          int    `json:"version"`
+	ChainId          string `json:"chain_id"`
+	ConsensusVersion string `json:"consensus_version"`
+	Moniker          string `json:"moniker"`
+}
+
+// Recording is a recording loaded from file.
+type Recording struct {
+	Header Header
+
+	// Records holds all the records except the header, ordered by time.
+	Records []Record
+}
+
+// LoadRecording loads a recording from the given file.
+func LoadRecording(filePath string) (*Recording, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open recording file")
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	recording := &Recording{}
+	var foundHeader bool
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 1024*1024), 64*1024*1024) // a snapshot of large validator set can be big
+
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Bytes()
+		if len(line) < 1 {
+			continue
+		}
+
+		var record Record
+		if err := json.Unmarshal(line, &record); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode record at line %d", lineNumber)
+		}
+
+		if record.Type == RecordTypeHeader {
+			if record.Header == nil {
+				return nil, fmt.Errorf("missing header content at line %d", lineNumber)
+			}
+			if record.Header.Version > recordingFormatVersion {
+				return nil, fmt.Errorf("recording format version %d is not supported, please upgrade", record.Header.Version)
+			}
+			recording.Header = *record.Header
+			foundHeader = true
+			continue
+		}
+
+		recording.Records = append(recording.Records, record)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read recording file")
+	}
+
+	if !foundHeader {
+		return nil, fmt.Errorf("not a recording file, missing header")
+	}
+
+	return recording, nil
+}
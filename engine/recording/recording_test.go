@@ -0,0 +1,144 @@
+package recording
+
+import (
+	"fmt"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecorderAndLoadRecording(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "session.cvp")
+
+	recorder, err := NewRecorder(filePath, "cosmoshub-4", "0.34.29", "node")
+	require.NoError(t, err)
+
+	startTime := time.Date(2023, 12, 25, 3, 0, 0, 0, time.UTC)
+
+	//goland:noinspection SpellCheckingInspection
+	lightValidators := enginetypes.LightValidators{
+		{
+			Index:                     0,
+			Moniker:                   "Val1",
+			Address:                   "AA",
+			PubKey:                    "pubkey",
+			VotingPower:               100,
+			VotingPowerDisplayPercent: 100,
+			SigningInfo: &enginetypes.LightValidatorSigningInfo{
+				MissedBlocksCounter: 5,
+				MaxMissedBlocks:     500,
+				JailedUntil:         startTime.Add(-time.Hour),
+			},
+			Details: &enginetypes.LightValidatorDetails{
+				OperatorAddress:  "cosmosvaloper1xxx",
+				ConsensusAddress: "cosmosvalcons1xxx",
+				Tokens:           "1000000",
+				CommissionRate:   0.05,
+			},
+		},
+	}
+	//goland:noinspection SpellCheckingInspection
+	votingInfo := &enginetypes.NextBlockVotingInformation{
+		SortedValidatorVoteStates: []enginetypes.ValidatorVoteState{
+			{
+				Validator:          lightValidators[0],
+				VotingBlockHash:    "C0FFEE000000",
+				PreVoted:           true,
+				PreVoteTime:        startTime.Add(time.Second),
+				PreVote:            "Vote{0:AA 100/00/SIGNED_MSG_TYPE_PREVOTE(Prevote) C0FFEE000000 @ 2023-12-25T03:00:01Z}",
+				PreCommitVoted:     true,
+				PreCommitBlockHash: "000000000000",
+				PreCommitTime:      startTime.Add(2 * time.Second),
+				PreCommit:          "Vote{0:AA 100/00/SIGNED_MSG_TYPE_PRECOMMIT(Precommit) 000000000000 @ 2023-12-25T03:00:02Z}",
+			},
+		},
+		PreVotePercent:   100,
+		PreCommitPercent: 100,
+		HeightRoundStep:  "100/0/6",
+		StartTimeUTC:     startTime,
+		SignedBlocksWindow: &enginetypes.SignedBlocksWindow{
+			FromHeight:   98,
+			ToHeight:     99,
+			SignedBlocks: map[string][]bool{"AA": {true, false}},
+		},
+	}
+
+	require.NoError(t, recorder.RecordLightValidators(lightValidators))
+	require.NoError(t, recorder.RecordVotingInfo(votingInfo))
+	require.NoError(t, recorder.RecordVotingInfo(fmt.Errorf("pseudo error")))
+	require.Error(t, recorder.RecordVotingInfo("not supported"))
+	require.NoError(t, recorder.Close())
+
+	rec, err := LoadRecording(filePath)
+	require.NoError(t, err)
+
+	require.Equal(t, Header{
+		Version:          recordingFormatVersion,
+		ChainId:          "cosmoshub-4",
+		ConsensusVersion: "0.34.29",
+		Moniker:          "node",
+	}, rec.Header)
+
+	require.Len(t, rec.Records, 3)
+
+	require.Equal(t, RecordTypeValidators, rec.Records[0].Type)
+	require.Equal(t, lightValidators, ToLightValidators(rec.Records[0].Validators))
+
+	require.Equal(t, RecordTypeVotingInfo, rec.Records[1].Type)
+	require.Equal(t, votingInfo, rec.Records[1].VotingInfo.ToNextBlockVotingInformation(ToLightValidators(rec.Records[0].Validators)))
+	require.Equal(t,
+		enginetypes.LightValidator{Index: 0, Address: "AA"},
+		rec.Records[1].VotingInfo.ToNextBlockVotingInformation(nil).SortedValidatorVoteStates[0].Validator,
+		"validator not found must be provided with index and address",
+	)
+
+	require.Equal(t, RecordTypeError, rec.Records[2].Type)
+	require.Equal(t, "pseudo error", rec.Records[2].Error)
+
+	require.False(t, rec.Records[0].Time.IsZero())
+
+	content, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	for _, fieldName := range []string{`"votes"`, `"prevote_block_hash"`, `"precommit_block_hash"`, `"signed_blocks_window"`, `"operator_address"`} {
+		require.Contains(t, string(content), fieldName, "recorded format must use the stable field names")
+	}
+	require.Equal(t, 1, strings.Count(string(content), `"pubkey":`), "validators must not be re-embedded into the votes")
+}
+
+func TestLoadRecording(t *testing.T) {
+	tests := []struct {
+		name            string
+		content         string
+		wantErrContains string
+	}{
+		{
+			name:            "missing header",
+			content:         `{"type":"error","time":"2023-12-25T03:00:00Z","error":"x"}`,
+			wantErrContains: "missing header",
+		},
+		{
+			name:            "not supported version",
+			content:         `{"type":"header","time":"2023-12-25T03:00:00Z","header":{"version":999}}`,
+			wantErrContains: "not supported",
+		},
+		{
+			name:            "malformed",
+			content:         `not a json`,
+			wantErrContains: "failed to decode record at line 1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := filepath.Join(t.TempDir(), "session.cvp")
+			require.NoError(t, os.WriteFile(filePath, []byte(tt.content), 0o644))
+
+			_, err := LoadRecording(filePath)
+			require.Error(t, err)
+			require.Contains(t, err.Error(), tt.wantErrContains)
+		})
+	}
+}
@@ -0,0 +1,256 @@
+package recording
+
+//goland:noinspection SpellCheckingInspection
+import (
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"time"
+)
+
+// The types in this file define the on-disk format of the records, decoupled from the engine types,
+// so the engine types can be changed freely without breaking the recordings.
+// New fields might be added in the same format version, existing fields must never be removed or changed,
+// otherwise recordingFormatVersion must be bumped.
+
+// VotingInfo is the recorded snapshot of the next block voting information.
+type VotingInfo struct {
+	HeightRoundStep  string    `json:"height_round_step"`
+	StartTime        time.Time `json:"start_time"`
+	PreVotePercent   float64   `json:"prevote_percent"`
+	PreCommitPercent float64   `json:"precommit_percent"`
+
+	// Votes is sorted descending by voting power.
+	Votes []Vote `json:"votes"`
+
+	// SignedBlocksWindow is nil when not requested.
+	SignedBlocksWindow *SignedBlocksWindow `json:"signed_blocks_window,omitempty"`
+}
+
+// Vote is the recorded vote state of a validator.
+// The validator is referenced, to be resolved against the latest recorded validator set, to keep the records small.
+type Vote struct {
+	ValidatorIndex   int    `json:"validator_index"`
+	ValidatorAddress string `json:"validator_address"`
+
+	PreVoted         bool      `json:"prevoted"`
+	PreVotedZeroes   bool      `json:"prevoted_zeroes"`
+	PreVoteBlockHash string    `json:"prevote_block_hash,omitempty"`
+	PreVoteTime      time.Time `json:"prevote_time"`
+	PreVote          string    `json:"prevote,omitempty"` // raw vote
+
+	PreCommitted       bool      `json:"precommitted"`
+	PreCommitBlockHash string    `json:"precommit_block_hash,omitempty"`
+	PreCommitTime      time.Time `json:"precommit_time"`
+	PreCommit          string    `json:"precommit,omitempty"` // raw vote
+}
+
+// Validator is the recorded information of a validator.
+type Validator struct {
+	Index              int     `json:"index"`
+	Address            string  `json:"address"`
+	Moniker            string  `json:"moniker"`
+	PubKey             string  `json:"pubkey"`
+	VotingPower        int64   `json:"voting_power"`
+	VotingPowerPercent float64 `json:"voting_power_percent"`
+
+	SigningInfo *ValidatorSigningInfo `json:"signing_info,omitempty"`
+	Details     *ValidatorDetails     `json:"details,omitempty"`
+}
+
+// ValidatorSigningInfo is the recorded signing information of a validator from the slashing module.
+type ValidatorSigningInfo struct {
+	MissedBlocksCounter int64     `json:"missed_blocks_counter"`
+	MaxMissedBlocks     int64     `json:"max_missed_blocks"`
+	JailedUntil         time.Time `json:"jailed_until"`
+	Tombstoned          bool      `json:"tombstoned"`
+}
+
+// ValidatorDetails is the recorded staking information of a validator.
+type ValidatorDetails struct {
+	OperatorAddress         string  `json:"operator_address"`
+	ConsensusAddress        string  `json:"consensus_address"`
+	Tokens                  string  `json:"tokens"`
+	CommissionRate          float64 `json:"commission_rate"`
+	CommissionMaxRate       float64 `json:"commission_max_rate"`
+	CommissionMaxChangeRate float64 `json:"commission_max_change_rate"`
+}
+
+// SignedBlocksWindow is the recorded signing status of validators over the most recent committed blocks.
+type SignedBlocksWindow struct {
+	FromHeight int64 `json:"from_height"`
+	ToHeight   int64 `json:"to_height"`
+
+	// SignedBlocks maps validator address to the signing status of each height within the window, ordered ascending by height.
+	SignedBlocks map[string][]bool `json:"signed_blocks"`
+}
+
+// NewVotingInfo converts the next block voting information into the recorded format.
+func NewVotingInfo(votingInfo *enginetypes.NextBlockVotingInformation) *VotingInfo {
+	recorded := &VotingInfo{
+		HeightRoundStep:  votingInfo.HeightRoundStep,
+		StartTime:        votingInfo.StartTimeUTC,
+		PreVotePercent:   votingInfo.PreVotePercent,
+		PreCommitPercent: votingInfo.PreCommitPercent,
+		Votes:            make([]Vote, len(votingInfo.SortedValidatorVoteStates)),
+	}
+
+	for i, voteState := range votingInfo.SortedValidatorVoteStates {
+		recorded.Votes[i] = Vote{
+			ValidatorIndex:     voteState.Validator.Index,
+			ValidatorAddress:   voteState.Validator.Address,
+			PreVoted:           voteState.PreVoted,
+			PreVotedZeroes:     voteState.VotedZeroes,
+			PreVoteBlockHash:   voteState.VotingBlockHash,
+			PreVoteTime:        voteState.PreVoteTime,
+			PreVote:            voteState.PreVote,
+			PreCommitted:       voteState.PreCommitVoted,
+			PreCommitBlockHash: voteState.PreCommitBlockHash,
+			PreCommitTime:      voteState.PreCommitTime,
+			PreCommit:          voteState.PreCommit,
+		}
+	}
+
+	if window := votingInfo.SignedBlocksWindow; window != nil {
+		recorded.SignedBlocksWindow = &SignedBlocksWindow{
+			FromHeight:   window.FromHeight,
+			ToHeight:     window.ToHeight,
+			SignedBlocks: window.SignedBlocks,
+		}
+	}
+
+	return recorded
+}
+
+// ToNextBlockVotingInformation converts the recorded voting information back into the engine type,
+// validators of the votes are resolved by address against the given validator set, which was recorded prior to the voting information.
+// Validators not found are provided with index and address only.
+func (v VotingInfo) ToNextBlockVotingInformation(lightValidators enginetypes.LightValidators) *enginetypes.NextBlockVotingInformation {
+	lightValidatorByAddress := make(map[string]enginetypes.LightValidator, len(lightValidators))
+	for _, lightValidator := range lightValidators {
+		lightValidatorByAddress[lightValidator.Address] = lightValidator
+	}
+
+	votingInfo := &enginetypes.NextBlockVotingInformation{
+		HeightRoundStep:           v.HeightRoundStep,
+		StartTimeUTC:              v.StartTime,
+		PreVotePercent:            v.PreVotePercent,
+		PreCommitPercent:          v.PreCommitPercent,
+		SortedValidatorVoteStates: make([]enginetypes.ValidatorVoteState, len(v.Votes)),
+	}
+
+	for i, vote := range v.Votes {
+		validator, found := lightValidatorByAddress[vote.ValidatorAddress]
+		if !found {
+			validator = enginetypes.LightValidator{
+				Index:   vote.ValidatorIndex,
+				Address: vote.ValidatorAddress,
+			}
+		}
+
+		votingInfo.SortedValidatorVoteStates[i] = enginetypes.ValidatorVoteState{
+			Validator:          validator,
+			VotingBlockHash:    vote.PreVoteBlockHash,
+			PreVoted:           vote.PreVoted,
+			VotedZeroes:        vote.PreVotedZeroes,
+			PreCommitVoted:     vote.PreCommitted,
+			PreVoteTime:        vote.PreVoteTime,
+			PreCommitBlockHash: vote.PreCommitBlockHash,
+			PreCommitTime:      vote.PreCommitTime,
+			PreVote:            vote.PreVote,
+			PreCommit:          vote.PreCommit,
+		}
+	}
+
+	if window := v.SignedBlocksWindow; window != nil {
+		votingInfo.SignedBlocksWindow = &enginetypes.SignedBlocksWindow{
+			FromHeight:   window.FromHeight,
+			ToHeight:     window.ToHeight,
+			SignedBlocks: window.SignedBlocks,
+		}
+	}
+
+	return votingInfo
+}
+
+// NewValidators converts the validator set into the recorded format.
+func NewValidators(lightValidators enginetypes.LightValidators) []Validator {
+	validators := make([]Validator, len(lightValidators))
+	for i, lightValidator := range lightValidators {
+		validators[i] = newValidator(lightValidator)
+	}
+	return validators
+}
+
+// ToLightValidators converts the recorded validator set back into the engine type.
+func ToLightValidators(validators []Validator) enginetypes.LightValidators {
+	lightValidators := make(enginetypes.LightValidators, len(validators))
+	for i, validator := range validators {
+		lightValidators[i] = validator.toLightValidator()
+	}
+	return lightValidators
+}
+
+func newValidator(lightValidator enginetypes.LightValidator) Validator {
+	validator := Validator{
+		Index:              lightValidator.Index,
+		Address:            lightValidator.Address,
+		Moniker:            lightValidator.Moniker,
+		PubKey:             lightValidator.PubKey,
+		VotingPower:        lightValidator.VotingPower,
+		VotingPowerPercent: lightValidator.VotingPowerDisplayPercent,
+	}
+
+	if signingInfo := lightValidator.SigningInfo; signingInfo != nil {
+		validator.SigningInfo = &ValidatorSigningInfo{
+			MissedBlocksCounter: signingInfo.MissedBlocksCounter,
+			MaxMissedBlocks:     signingInfo.MaxMissedBlocks,
+			JailedUntil:         signingInfo.JailedUntil,
+			Tombstoned:          signingInfo.Tombstoned,
+		}
+	}
+
+	if details := lightValidator.Details; details != nil {
+		validator.Details = &ValidatorDetails{
+			OperatorAddress:         details.OperatorAddress,
+			ConsensusAddress:        details.ConsensusAddress,
+			Tokens:                  details.Tokens,
+			CommissionRate:          details.CommissionRate,
+			CommissionMaxRate:       details.CommissionMaxRate,
+			CommissionMaxChangeRate: details.CommissionMaxChangeRate,
+		}
+	}
+
+	return validator
+}
+
+func (v Validator) toLightValidator() enginetypes.LightValidator {
+	lightValidator := enginetypes.LightValidator{
+		Index:                     v.Index,
+		Moniker:                   v.Moniker,
+		Address:                   v.Address,
+		PubKey:                    v.PubKey,
+		VotingPower:               v.VotingPower,
+		VotingPowerDisplayPercent: v.VotingPowerPercent,
+	}
+
+	if signingInfo := v.SigningInfo; signingInfo != nil {
+		lightValidator.SigningInfo = &enginetypes.LightValidatorSigningInfo{
+			MissedBlocksCounter: signingInfo.MissedBlocksCounter,
+			MaxMissedBlocks:     signingInfo.MaxMissedBlocks,
+			JailedUntil:         signingInfo.JailedUntil,
+			Tombstoned:          signingInfo.Tombstoned,
+		}
+	}
+
+	if details := v.Details; details != nil {
+		lightValidator.Details = &enginetypes.LightValidatorDetails{
+			OperatorAddress:         details.OperatorAddress,
+			ConsensusAddress:        details.ConsensusAddress,
+			Tokens:                  details.Tokens,
+			CommissionRate:          details.CommissionRate,
+			CommissionMaxRate:       details.CommissionMaxRate,
+			CommissionMaxChangeRate: details.CommissionMaxChangeRate,
+		}
+	}
+
+	return lightValidator
+}
@@ -13,7 +13,9 @@ import (
 	"github.com/bcdevtools/consvp/utils"
 	cryptocodec "github.com/cosmos/cosmos-sdk/crypto/codec"
 	cryptoed25519 "github.com/cosmos/cosmos-sdk/crypto/keys/ed25519"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
 	"github.com/cosmos/cosmos-sdk/types/query"
+	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
 	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
 	"github.com/golang/protobuf/proto"
 	"github.com/pkg/errors"
@@ -31,10 +33,14 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 )
 
 var _ rpc_client.RpcClient = (*defaultRpcClientImpl)(nil) // ensure defaultRpcClientImpl implements RpcClient interface
 
+// errSlashingUnavailable is returned when querying signing information on a chain which rejected the slashing module queries.
+var errSlashingUnavailable = errors.New("slashing module is not available")
+
 // CONTRACT: must be a valid HTTP endpoint, not ends with '/'.
 type normalizedRpcHttpEndpoint string
 
@@ -62,6 +68,17 @@ type defaultRpcClientImpl struct {
 	statusNetwork string
 	statusVersion string
 	statusMoniker string
+
+	// commitsCache holds the canonical commits fetched from the RPC server, indexed by height.
+	// Guarded by mutex.
+	commitsCache map[int64]*tmtypes.Commit
+
+	// slashingUnavailable is true when the slashing module queries were rejected by the chain, so they are not queried again.
+	// Guarded by mutex.
+	slashingUnavailable bool
+
+	// observer is notified of each request to the RPC server, optional.
+	observer rpc_client.RpcObserver
 }
 
 // NewDefaultRpcClient returns the default implementation of rpc.RPC interface.
@@ -79,6 +96,7 @@ func NewDefaultRpcClient(endpoint, optionalProducerEndpoint string, useWebsocket
 		mutex:            &sync.Mutex{},
 		endpoint:         normalizedRpcHttpEndpoint(httpEndpoint),
 		producerEndpoint: normalizedRpcHttpEndpoint(producerHttpEndpoint),
+		commitsCache:     make(map[int64]*tmtypes.Commit),
 	}
 	var err error
 	if useWebsocket {
@@ -109,6 +127,21 @@ func NewDefaultRpcClient(endpoint, optionalProducerEndpoint string, useWebsocket
 	return result
 }
 
+// SetObserver sets the observer to be notified of each request to the RPC server.
+//
+// CONTRACT: must be called before the client is used by other goroutines.
+func (rpc *defaultRpcClientImpl) SetObserver(observer rpc_client.RpcObserver) {
+	rpc.observer = observer
+}
+
+// observe notifies the observer, if any, of a request to the RPC server.
+func (rpc *defaultRpcClientImpl) observe(method string, startTime time.Time, err error) {
+	if rpc.observer == nil {
+		return
+	}
+	rpc.observer(method, time.Since(startTime), err)
+}
+
 // NodeInfo returns upstream RPC server chain id, consensus version and moniker if validator.
 func (rpc *defaultRpcClientImpl) NodeInfo() (chainId, consensusVersion, moniker string) {
 	chainId = rpc.statusNetwork
@@ -146,6 +179,7 @@ func (rpc *defaultRpcClientImpl) LightValidators() ([]enginetypes.LightValidator
 			Moniker: bondedVal.Description.Moniker,
 			Address: strings.ToUpper(tmPubKey.Address().String()),
 			PubKey:  base64.StdEncoding.EncodeToString(tmPublicKey.GetEd25519()),
+			Details: lightValidatorDetails(bondedVal, tmPubKey.Address()),
 		}
 		mapper[val.Address] = &val
 	}
@@ -188,6 +222,97 @@ func (rpc *defaultRpcClientImpl) LightValidators() ([]enginetypes.LightValidator
 		return result[i].Index < result[j].Index
 	})
 
+	// signing information is optional, because slashing module is not always available (eg: consumer chains)
+	signingInfos, errSigningInfos := rpc.lightValidatorSigningInfos()
+	if errSigningInfos == nil {
+		for i, val := range result {
+			if signingInfo, found := signingInfos[val.Address]; found {
+				result[i].SigningInfo = signingInfo
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// lightValidatorDetails returns the staking information of the bonded validator, for display purpose.
+func lightValidatorDetails(bondedVal stakingtypes.Validator, consensusAddress []byte) *enginetypes.LightValidatorDetails {
+	details := &enginetypes.LightValidatorDetails{
+		OperatorAddress: bondedVal.OperatorAddress,
+	}
+	if !bondedVal.Tokens.IsNil() {
+		details.Tokens = bondedVal.Tokens.String()
+	}
+
+	// consensus address uses the same prefix as operator address, eg: cosmosvaloper => cosmosvalcons
+	if hrp, _, err := bech32.DecodeAndConvert(bondedVal.OperatorAddress); err == nil && strings.HasSuffix(hrp, "valoper") {
+		consensusHrp := strings.TrimSuffix(hrp, "valoper") + "valcons"
+		if bech32ConsensusAddress, err := bech32.ConvertAndEncode(consensusHrp, consensusAddress); err == nil {
+			details.ConsensusAddress = bech32ConsensusAddress
+		}
+	}
+
+	commissionRates := bondedVal.Commission.CommissionRates
+	if !commissionRates.Rate.IsNil() {
+		details.CommissionRate, _ = commissionRates.Rate.Float64()
+	}
+	if !commissionRates.MaxRate.IsNil() {
+		details.CommissionMaxRate, _ = commissionRates.MaxRate.Float64()
+	}
+	if !commissionRates.MaxChangeRate.IsNil() {
+		details.CommissionMaxChangeRate, _ = commissionRates.MaxChangeRate.Float64()
+	}
+
+	return details
+}
+
+// lightValidatorSigningInfos returns the signing information of validators, indexed by upper-case hex consensus address.
+// Once the chain rejected the slashing module queries, eg: consumer chains, the module is considered unavailable and not queried again.
+func (rpc *defaultRpcClientImpl) lightValidatorSigningInfos() (map[string]*enginetypes.LightValidatorSigningInfo, error) {
+	rpc.mutex.Lock()
+	slashingUnavailable := rpc.slashingUnavailable
+	rpc.mutex.Unlock()
+	if slashingUnavailable {
+		return nil, errSlashingUnavailable
+	}
+
+	slashingParams, err := rpc.SlashingParams()
+	if err != nil {
+		var abciQueryError *enginetypes.AbciQueryError
+		if errors.As(err, &abciQueryError) {
+			rpc.mutex.Lock()
+			rpc.slashingUnavailable = true
+			rpc.mutex.Unlock()
+		}
+		return nil, errors.Wrap(err, "failed to get slashing params")
+	}
+
+	signingInfos, err := rpc.SigningInfos()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get signing infos")
+	}
+
+	var maxMissedBlocks int64
+	if slashingParams.SignedBlocksWindow > 0 && !slashingParams.MinSignedPerWindow.IsNil() {
+		minSignedBlocks := slashingParams.MinSignedPerWindow.MulInt64(slashingParams.SignedBlocksWindow).RoundInt64()
+		maxMissedBlocks = slashingParams.SignedBlocksWindow - minSignedBlocks
+	}
+
+	result := make(map[string]*enginetypes.LightValidatorSigningInfo)
+	for _, signingInfo := range signingInfos {
+		_, bzConsAddr, err := bech32.DecodeAndConvert(signingInfo.Address)
+		if err != nil {
+			continue
+		}
+
+		result[strings.ToUpper(hex.EncodeToString(bzConsAddr))] = &enginetypes.LightValidatorSigningInfo{
+			MissedBlocksCounter: signingInfo.MissedBlocksCounter,
+			MaxMissedBlocks:     maxMissedBlocks,
+			JailedUntil:         signingInfo.JailedUntil,
+			Tombstoned:          signingInfo.Tombstoned,
+		}
+	}
+
 	return result, nil
 }
 
@@ -228,7 +353,9 @@ func (rpc *defaultRpcClientImpl) bondedValidatorsViaWebsocket() ([]stakingtypes.
 		retry := types.DefaultRetryCounterFetchingRpc()
 
 		for retry.Continue() {
+			startTime := time.Now()
 			resultABCIQuery, err = rpc.producerRpcWebsocketClient.ABCIQuery(context.Background(), "/cosmos.staking.v1beta1.Query/Validators", bz)
+			rpc.observe(rpc_client.RpcMethodAbciQuery, startTime, err)
 			if err == nil {
 				break
 			}
@@ -332,7 +459,9 @@ func (rpc *defaultRpcClientImpl) bondedValidatorsViaHTTP() ([]stakingtypes.Valid
 		retry := types.DefaultRetryCounterFetchingRpc()
 
 		for retry.Continue() {
+			startTime := time.Now()
 			queryValidatorsResponse, err = fetchBondedValidators(nextKey)
+			rpc.observe(rpc_client.RpcMethodAbciQuery, startTime, err)
 			if err == nil {
 				break
 			}
@@ -353,6 +482,159 @@ func (rpc *defaultRpcClientImpl) bondedValidatorsViaHTTP() ([]stakingtypes.Valid
 	return validators, nil
 }
 
+// SigningInfos returns the signing information of all validators, from the slashing module.
+func (rpc *defaultRpcClientImpl) SigningInfos() ([]slashingtypes.ValidatorSigningInfo, error) {
+	const limit uint64 = 200
+
+	var signingInfos []slashingtypes.ValidatorSigningInfo
+	var nextKey []byte
+	var stop = false
+
+	for !stop {
+		req := slashingtypes.QuerySigningInfosRequest{
+			Pagination: &query.PageRequest{
+				Limit: limit,
+				Key:   nextKey,
+			},
+		}
+
+		bz, err := req.Marshal()
+		if err != nil {
+			panic(errors.Wrap(err, "failed to marshal request, weird!"))
+		}
+
+		bz, err = rpc.producerAbciQuery("/cosmos.slashing.v1beta1.Query/SigningInfos", bz)
+		if err != nil {
+			return nil, err
+		}
+
+		var querySigningInfosResponse slashingtypes.QuerySigningInfosResponse
+		err = querySigningInfosResponse.Unmarshal(bz)
+		if err != nil {
+			return nil, errors.Wrap(err, "error unmarshal response value signing infos")
+		}
+
+		signingInfos = append(signingInfos, querySigningInfosResponse.Info...)
+		if querySigningInfosResponse.Pagination == nil {
+			break
+		}
+		nextKey = querySigningInfosResponse.Pagination.NextKey
+		stop = len(nextKey) == 0
+	}
+
+	return signingInfos, nil
+}
+
+// SlashingParams returns the parameters of the slashing module.
+func (rpc *defaultRpcClientImpl) SlashingParams() (*slashingtypes.Params, error) {
+	req := slashingtypes.QueryParamsRequest{}
+
+	bz, err := req.Marshal()
+	if err != nil {
+		panic(errors.Wrap(err, "failed to marshal request, weird!"))
+	}
+
+	bz, err = rpc.producerAbciQuery("/cosmos.slashing.v1beta1.Query/Params", bz)
+	if err != nil {
+		return nil, err
+	}
+
+	var queryParamsResponse slashingtypes.QueryParamsResponse
+	err = queryParamsResponse.Unmarshal(bz)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshal response value slashing params")
+	}
+
+	return &queryParamsResponse.Params, nil
+}
+
+// producerAbciQuery performs ABCI query to the producer RPC server, with retry.
+// Responses with non-zero code are not retried, since they are not temporary failures, eg: unknown query path.
+// It returns the response value.
+func (rpc *defaultRpcClientImpl) producerAbciQuery(path string, data []byte) ([]byte, error) {
+	var bz []byte
+	var err error
+
+	retry := types.DefaultRetryCounterFetchingRpc()
+
+	for retry.Continue() {
+		startTime := time.Now()
+		if rpc.producerRpcWebsocketClient != nil {
+			bz, err = rpc.producerAbciQueryViaWebsocket(path, data)
+		} else {
+			bz, err = rpc.producerAbciQueryViaHttp(path, data)
+		}
+		rpc.observe(rpc_client.RpcMethodAbciQuery, startTime, err)
+		if err == nil {
+			break
+		}
+
+		var abciQueryError *enginetypes.AbciQueryError
+		if errors.As(err, &abciQueryError) {
+			break
+		}
+
+		sleepRetry()
+	}
+
+	return bz, err
+}
+
+func (rpc *defaultRpcClientImpl) producerAbciQueryViaWebsocket(path string, data []byte) ([]byte, error) {
+	if rpc.producerRpcWebsocketClient == nil {
+		return nil, errors.New("Websocket client is not available")
+	}
+
+	resultABCIQuery, err := rpc.producerRpcWebsocketClient.ABCIQuery(context.Background(), path, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if resultABCIQuery.Response.Code != 0 {
+		return nil, &enginetypes.AbciQueryError{
+			Code: resultABCIQuery.Response.Code,
+			Log:  resultABCIQuery.Response.Log,
+		}
+	}
+
+	return resultABCIQuery.Response.Value, nil
+}
+
+func (rpc *defaultRpcClientImpl) producerAbciQueryViaHttp(path string, data []byte) ([]byte, error) {
+	payload := fmt.Sprintf(`{
+		"jsonrpc": "2.0",
+		"id":      "%s",
+		"method":  "abci_query",
+		"params": [
+			"%s",
+			"%s",
+			"0",
+			false
+		]
+	}`, strconv.Itoa(int(rand.Uint16())+1), path, hex.EncodeToString(data))
+
+	resp, err := http.Post(string(rpc.producerEndpoint), "application/json", bytes.NewBuffer([]byte(payload)))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error request '%s' via rpc '/abci_query' endpoint", path)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	bz, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error reading response '%s' from rpc '/abci_query' endpoint", path)
+	}
+
+	var resContent enginetypes.BaseAbciQueryResponse
+	err = json.Unmarshal(bz, &resContent)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error unmarshal response '%s' from rpc '/abci_query' endpoint", path)
+	}
+
+	return resContent.GetBuffer()
+}
+
 // ConsensusState fetches the current consensus state from the RPC server ':26657/consensus_state'.
 func (rpc *defaultRpcClientImpl) ConsensusState() (*enginetypes.RoundState, error) {
 	var resultRoundState *enginetypes.RoundState
@@ -361,11 +643,13 @@ func (rpc *defaultRpcClientImpl) ConsensusState() (*enginetypes.RoundState, erro
 	retry := types.DefaultRetryCounterFetchingRpc()
 
 	for retry.Continue() {
+		startTime := time.Now()
 		if rpc.rpcWebsocketClient != nil {
 			resultRoundState, err = rpc.consensusStateViaWebsocket()
 		} else {
 			resultRoundState, err = rpc.consensusStateViaHTTP()
 		}
+		rpc.observe(rpc_client.RpcMethodConsensusState, startTime, err)
 		if err == nil {
 			break
 		}
@@ -432,11 +716,13 @@ func (rpc *defaultRpcClientImpl) Status() (*coretypes.ResultStatus, error) {
 	retry := types.DefaultRetryCounterFetchingRpc()
 
 	for retry.Continue() {
+		startTime := time.Now()
 		if rpc.rpcWebsocketClient != nil {
 			resultStatus, err = rpc.statusViaWebsocket()
 		} else {
 			resultStatus, err = rpc.statusViaHTTP()
 		}
+		rpc.observe(rpc_client.RpcMethodStatus, startTime, err)
 		if err == nil {
 			break
 		}
@@ -519,7 +805,9 @@ func (rpc *defaultRpcClientImpl) latestValidatorsViaWebsocket(height int64) ([]*
 		retry := types.DefaultRetryCounterFetchingRpc()
 
 		for retry.Continue() {
+			startTime := time.Now()
 			resVals, err = rpc.producerRpcWebsocketClient.Validators(context.Background(), latestHeight, &page, &perPage)
+			rpc.observe(rpc_client.RpcMethodValidators, startTime, err)
 
 			if err == nil {
 				break
@@ -592,7 +880,9 @@ func (rpc *defaultRpcClientImpl) latestValidatorsViaHttp(height int64) ([]*tmtyp
 		retry := types.DefaultRetryCounterFetchingRpc()
 
 		for retry.Continue() {
+			startTime := time.Now()
 			resVals, err = fetchValidators(page)
+			rpc.observe(rpc_client.RpcMethodValidators, startTime, err)
 
 			if err == nil {
 				break
@@ -617,6 +907,164 @@ func (rpc *defaultRpcClientImpl) latestValidatorsViaHttp(height int64) ([]*tmtyp
 	return validators, nil
 }
 
+// maxConcurrentFetchingCommits is the maximum number of concurrent requests to the RPC server when fetching commits.
+const maxConcurrentFetchingCommits = 8
+
+// Commits fetches the commits of the given heights from the RPC server ':26657/commit', concurrently.
+// Canonical commits are cached, so heights those had been fetched before will not be fetched again.
+//
+// Result is a map of height to commit, all requested heights are present if no error.
+func (rpc *defaultRpcClientImpl) Commits(heights []int64) (map[int64]*tmtypes.Commit, error) {
+	result := make(map[int64]*tmtypes.Commit)
+	if len(heights) < 1 {
+		return result, nil
+	}
+
+	var missingHeights []int64
+	minHeight := heights[0]
+
+	rpc.mutex.Lock()
+	for _, height := range heights {
+		if height < minHeight {
+			minHeight = height
+		}
+		if commit, found := rpc.commitsCache[height]; found {
+			result[height] = commit
+		} else {
+			missingHeights = append(missingHeights, height)
+		}
+	}
+	rpc.mutex.Unlock()
+
+	type fetchCommitResult struct {
+		height    int64
+		commit    *tmtypes.Commit
+		canonical bool
+		err       error
+	}
+
+	fetchResultsChan := make(chan fetchCommitResult, len(missingHeights))
+	semaphore := make(chan struct{}, maxConcurrentFetchingCommits)
+	wg := &sync.WaitGroup{}
+
+	for _, height := range missingHeights {
+		wg.Add(1)
+		go func(height int64) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() {
+				<-semaphore
+			}()
+
+			var resultCommit *coretypes.ResultCommit
+			var err error
+
+			retry := types.DefaultRetryCounterFetchingRpc()
+
+			for retry.Continue() {
+				startTime := time.Now()
+				if rpc.rpcWebsocketClient != nil {
+					resultCommit, err = rpc.commitViaWebsocket(height)
+				} else {
+					resultCommit, err = rpc.commitViaHttp(height)
+				}
+				rpc.observe(rpc_client.RpcMethodCommit, startTime, err)
+				if err == nil {
+					break
+				}
+
+				sleepRetry()
+			}
+
+			if err != nil {
+				fetchResultsChan <- fetchCommitResult{height: height, err: err}
+				return
+			}
+
+			if resultCommit.Commit == nil {
+				fetchResultsChan <- fetchCommitResult{height: height, err: fmt.Errorf("empty commit at height %d", height)}
+				return
+			}
+
+			fetchResultsChan <- fetchCommitResult{
+				height:    height,
+				commit:    resultCommit.Commit,
+				canonical: resultCommit.CanonicalCommit,
+			}
+		}(height)
+	}
+
+	wg.Wait()
+	close(fetchResultsChan)
+
+	rpc.mutex.Lock()
+	defer rpc.mutex.Unlock()
+
+	var err error
+	for fetchResult := range fetchResultsChan {
+		if fetchResult.err != nil {
+			if err == nil {
+				err = errors.Wrapf(fetchResult.err, "failed to fetch commit at height %d", fetchResult.height)
+			}
+			continue
+		}
+
+		result[fetchResult.height] = fetchResult.commit
+		if fetchResult.canonical {
+			rpc.commitsCache[fetchResult.height] = fetchResult.commit
+		}
+	}
+
+	// prune the commits those are no longer needed, to prevent memory stacking
+	for height := range rpc.commitsCache {
+		if height < minHeight {
+			delete(rpc.commitsCache, height)
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (rpc *defaultRpcClientImpl) commitViaWebsocket(height int64) (*coretypes.ResultCommit, error) {
+	if rpc.rpcWebsocketClient == nil {
+		return nil, errors.New("Websocket client is not available")
+	}
+	return rpc.rpcWebsocketClient.Commit(context.Background(), &height)
+}
+
+func (rpc *defaultRpcClientImpl) commitViaHttp(height int64) (*coretypes.ResultCommit, error) {
+	resp, err := http.Get(fmt.Sprintf("%s/commit?height=%d", rpc.endpoint, height))
+	if err != nil {
+		return nil, errors.Wrap(err, "error request rpc '/commit' endpoint")
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	bz, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading response from rpc '/commit' endpoint")
+	}
+
+	var resContent enginetypes.BaseRpcResponse[coretypes.ResultCommit]
+	err = json.Unmarshal(bz, &resContent)
+	if err != nil {
+		return nil, errors.Wrap(err, "error unmarshal response from rpc '/commit' endpoint")
+	}
+
+	err = resContent.Error.GetError()
+	if err != nil {
+		return nil, err
+	}
+
+	return resContent.Result, nil
+}
+
 // Shutdown must be called when the RPC client is no longer needed.
 // It does close up all the connections to the RPC server and free resources.
 func (rpc *defaultRpcClientImpl) Shutdown() error {
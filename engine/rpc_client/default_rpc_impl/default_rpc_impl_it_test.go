@@ -64,6 +64,42 @@ func (suite *IntegrationTestSuite) Test_defaultRpcClientImpl_IT_BondedValidators
 	})
 }
 
+func (suite *IntegrationTestSuite) Test_defaultRpcClientImpl_IT_SigningInfos() {
+	testHandler := func(client *defaultRpcClientImpl) {
+		slashingParams, err := client.SlashingParams()
+		suite.Require().NoError(err)
+		suite.Greater(slashingParams.SignedBlocksWindow, int64(0))
+
+		signingInfosViaWs, err := client.SigningInfos()
+		suite.Require().NoError(err)
+		suite.Require().NotEmpty(signingInfosViaWs)
+
+		lightValidatorSigningInfos, err := client.lightValidatorSigningInfos()
+		suite.Require().NoError(err)
+		suite.Require().NotEmpty(lightValidatorSigningInfos)
+
+		lightVals, err := client.LightValidators()
+		suite.Require().NoError(err)
+
+		var countHasSigningInfo int
+		for _, lightVal := range lightVals {
+			if lightVal.SigningInfo != nil {
+				countHasSigningInfo++
+				suite.Greater(lightVal.SigningInfo.MaxMissedBlocks, int64(0))
+			}
+		}
+		suite.Greater(countHasSigningInfo, 0, "expect signing info joined by consensus address")
+	}
+
+	suite.Run("Get signing infos on Tendermint node", func() {
+		testHandler(suite.TM)
+	})
+
+	suite.Run("Get signing infos on CometBFT node", func() {
+		testHandler(suite.COMETBFT)
+	})
+}
+
 func (suite *IntegrationTestSuite) Test_defaultRpcClientImpl_IT_ConsensusState() {
 	assertResult := func(roundState *enginetypes.RoundState) {
 		suite.Require().NotNil(roundState)
@@ -159,6 +195,53 @@ func (suite *IntegrationTestSuite) Test_defaultRpcClientImpl_IT_LatestValidators
 	})
 }
 
+func (suite *IntegrationTestSuite) Test_defaultRpcClientImpl_IT_Commits() {
+	testHandler := func(client *defaultRpcClientImpl) {
+		status, err := client.Status()
+		suite.Require().NoError(err)
+
+		latestHeight := status.SyncInfo.LatestBlockHeight
+		suite.Require().Greater(latestHeight, int64(5))
+
+		heights := []int64{latestHeight - 3, latestHeight - 2, latestHeight - 1}
+
+		commitViaHTTP, err := client.commitViaHttp(heights[0])
+		suite.Require().NoError(err)
+		suite.Require().NotNil(commitViaHTTP.Commit)
+		suite.Equal(heights[0], commitViaHTTP.Commit.Height)
+		suite.NotEmpty(commitViaHTTP.Commit.Signatures)
+
+		commitViaWs, err := client.commitViaWebsocket(heights[0])
+		if suite.NoError(err) {
+			suite.Require().NotNil(commitViaWs.Commit)
+			suite.Equal(commitViaHTTP.Commit.Signatures, commitViaWs.Commit.Signatures, "mis-match commit signatures between Websocket and HTTP response")
+		}
+
+		commits, err := client.Commits(heights)
+		suite.Require().NoError(err)
+		suite.Require().Len(commits, len(heights))
+		for _, height := range heights {
+			if suite.Contains(commits, height) {
+				suite.Equal(height, commits[height].Height)
+				suite.Contains(client.commitsCache, height, "canonical commit should be cached")
+			}
+		}
+
+		// older heights should be pruned from cache
+		_, err = client.Commits([]int64{latestHeight - 1})
+		suite.Require().NoError(err)
+		suite.NotContains(client.commitsCache, latestHeight-3)
+	}
+
+	suite.Run("Get commits on Tendermint node", func() {
+		testHandler(suite.TM)
+	})
+
+	suite.Run("Get commits on CometBFT node", func() {
+		testHandler(suite.COMETBFT)
+	})
+}
+
 func (suite *IntegrationTestSuite) Test_defaultRpcClientImpl_IT_Shutdown() {
 	testHandler := func(client *defaultRpcClientImpl) {
 		suite.Require().True(client.rpcWebsocketClient.IsRunning(), "required status running at this point")
@@ -1,10 +1,19 @@
 package default_rpc_impl
 
 import (
+	"github.com/bcdevtools/consvp/engine/rpc_client"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
 	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
 	"regexp"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestNewDefaultRpcClient(t *testing.T) {
@@ -82,3 +91,63 @@ func TestNewDefaultRpcClient(t *testing.T) {
 		})
 	}
 }
+
+func Test_lightValidatorDetails(t *testing.T) {
+	operatorAddress, err := bech32.ConvertAndEncode("cosmosvaloper", make([]byte, 20))
+	require.NoError(t, err)
+
+	bondedVal := stakingtypes.Validator{
+		OperatorAddress: operatorAddress,
+		Tokens:          sdk.NewInt(1_000_000),
+		Commission: stakingtypes.Commission{
+			CommissionRates: stakingtypes.CommissionRates{
+				Rate:          sdk.NewDecWithPrec(5, 2),
+				MaxRate:       sdk.NewDecWithPrec(2, 1),
+				MaxChangeRate: sdk.NewDecWithPrec(1, 2),
+			},
+		},
+	}
+
+	details := lightValidatorDetails(bondedVal, make([]byte, 20))
+	require.Equal(t, bondedVal.OperatorAddress, details.OperatorAddress)
+	require.True(t, strings.HasPrefix(details.ConsensusAddress, "cosmosvalcons1"))
+	require.Equal(t, "1000000", details.Tokens)
+	require.Equal(t, 0.05, details.CommissionRate)
+	require.Equal(t, 0.2, details.CommissionMaxRate)
+	require.Equal(t, 0.01, details.CommissionMaxChangeRate)
+
+	details = lightValidatorDetails(stakingtypes.Validator{OperatorAddress: "invalid"}, make([]byte, 20))
+	require.Empty(t, details.ConsensusAddress)
+	require.Empty(t, details.Tokens)
+	require.Zero(t, details.CommissionRate)
+}
+
+func Test_lightValidatorSigningInfos_slashingUnavailable(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"response":{"code":6,"log":"unknown query path","codespace":"sdk"}}}`))
+	}))
+	defer server.Close()
+
+	rpc := &defaultRpcClientImpl{
+		mutex:            &sync.Mutex{},
+		producerEndpoint: normalizedRpcHttpEndpoint(server.URL),
+	}
+	var observedMethods []string
+	rpc.SetObserver(func(method string, _ time.Duration, err error) {
+		require.Error(t, err)
+		observedMethods = append(observedMethods, method)
+	})
+
+	startTime := time.Now()
+	_, err := rpc.lightValidatorSigningInfos()
+	require.ErrorContains(t, err, "unknown query path")
+	require.Less(t, time.Since(startTime), time.Second, "must not retry")
+	require.Equal(t, int32(1), atomic.LoadInt32(&requests))
+	require.Equal(t, []string{rpc_client.RpcMethodAbciQuery}, observedMethods, "each request must be observed")
+
+	_, err = rpc.lightValidatorSigningInfos()
+	require.ErrorIs(t, err, errSlashingUnavailable)
+	require.Equal(t, int32(1), atomic.LoadInt32(&requests), "must not query again")
+}
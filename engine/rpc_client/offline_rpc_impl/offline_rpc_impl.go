@@ -0,0 +1,178 @@
+package offline_rpc_impl
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"encoding/base64"
+	"fmt"
+	"github.com/bcdevtools/consvp/engine/rpc_client"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
+	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+	"github.com/pkg/errors"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+	"strings"
+)
+
+var _ rpc_client.RpcClient = (*offlineRpcClientImpl)(nil) // ensure offlineRpcClientImpl implements RpcClient interface
+
+var errNotAvailableOffline = errors.New("not available offline")
+
+// offlineRpcClientImpl is an implementation of RpcClient,
+// which serves the consensus state and validators parsed from saved RPC responses, without any live node.
+type offlineRpcClientImpl struct {
+	chainId         string
+	roundState      *enginetypes.RoundState
+	validators      []*tmtypes.Validator
+	lightValidators enginetypes.LightValidators
+}
+
+// NewOfflineRpcClient returns an implementation of RpcClient which serves the given saved RPC responses.
+//
+// The consensus state can be the response of either '/consensus_state' or '/dump_consensus_state'.
+// The validators are the responses of '/validators', one per page, optional if the consensus state is a '/dump_consensus_state',
+// because it already contains the validator set.
+func NewOfflineRpcClient(consensusStateJson []byte, optionalValidatorsJsonPages [][]byte) (*offlineRpcClientImpl, error) {
+	savedConsensusState, err := parseSavedConsensusState(consensusStateJson)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse consensus state")
+	}
+
+	validators := savedConsensusState.validators
+	if len(optionalValidatorsJsonPages) > 0 {
+		validators, err = parseSavedValidators(optionalValidatorsJsonPages)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to parse validators")
+		}
+	}
+
+	if len(validators) < 1 {
+		return nil, fmt.Errorf("validators are required, either provide the '/validators' response or use the '/dump_consensus_state' response")
+	}
+
+	lightValidators := toLightValidators(validators)
+	if err := validateValidatorsMatchVotes(savedConsensusState.roundState, lightValidators); err != nil {
+		return nil, err
+	}
+
+	return &offlineRpcClientImpl{
+		chainId:         savedConsensusState.chainId,
+		roundState:      savedConsensusState.roundState,
+		validators:      validators,
+		lightValidators: lightValidators,
+	}, nil
+}
+
+// NodeInfo returns the chain id if it is available in the saved consensus state, consensus version and moniker are unknown.
+func (rpc *offlineRpcClientImpl) NodeInfo() (chainId, consensusVersion, moniker string) {
+	return rpc.chainId, "", ""
+}
+
+// LightValidators returns the validators parsed from the saved responses,
+// moniker is the fingerprint address because the staking module information is not available offline.
+func (rpc *offlineRpcClientImpl) LightValidators() ([]enginetypes.LightValidator, error) {
+	return rpc.lightValidators, nil
+}
+
+func (rpc *offlineRpcClientImpl) BondedValidators() ([]stakingtypes.Validator, error) {
+	return nil, errNotAvailableOffline
+}
+
+func (rpc *offlineRpcClientImpl) SigningInfos() ([]slashingtypes.ValidatorSigningInfo, error) {
+	return nil, errNotAvailableOffline
+}
+
+func (rpc *offlineRpcClientImpl) SlashingParams() (*slashingtypes.Params, error) {
+	return nil, errNotAvailableOffline
+}
+
+// ConsensusState returns the saved consensus state.
+func (rpc *offlineRpcClientImpl) ConsensusState() (*enginetypes.RoundState, error) {
+	return rpc.roundState, nil
+}
+
+func (rpc *offlineRpcClientImpl) Status() (*coretypes.ResultStatus, error) {
+	return nil, errNotAvailableOffline
+}
+
+// LatestValidators returns the validators parsed from the saved responses.
+func (rpc *offlineRpcClientImpl) LatestValidators() ([]*tmtypes.Validator, error) {
+	return rpc.validators, nil
+}
+
+func (rpc *offlineRpcClientImpl) Commits([]int64) (map[int64]*tmtypes.Commit, error) {
+	return nil, errNotAvailableOffline
+}
+
+// Shutdown does nothing, there is no connection to be closed.
+func (rpc *offlineRpcClientImpl) Shutdown() error {
+	return nil
+}
+
+// toLightValidators converts the validators into light validators, keeps the same order.
+func toLightValidators(validators []*tmtypes.Validator) enginetypes.LightValidators {
+	var totalVotingPower int64
+	for _, validator := range validators {
+		totalVotingPower += validator.VotingPower
+	}
+
+	var lightValidators enginetypes.LightValidators
+	for i, validator := range validators {
+		address := strings.ToUpper(validator.Address.String())
+
+		var pubKey string
+		if validator.PubKey != nil {
+			pubKey = base64.StdEncoding.EncodeToString(validator.PubKey.Bytes())
+		}
+
+		lightValidator := enginetypes.LightValidator{
+			Index:       i,
+			Moniker:     address[:12], // fingerprint address, as the moniker is not available offline
+			Address:     address,
+			PubKey:      pubKey,
+			VotingPower: validator.VotingPower,
+		}
+
+		if totalVotingPower > 0 {
+			lightValidator.VotingPowerDisplayPercent = 100 * (float64(validator.VotingPower) / float64(totalVotingPower))
+			lightValidator.VotingPowerDisplayPercent = float64(int64(lightValidator.VotingPowerDisplayPercent*100)) / 100
+			if lightValidator.VotingPower > 0 && lightValidator.VotingPowerDisplayPercent < 0.01 {
+				lightValidator.VotingPowerDisplayPercent = 0.01
+			}
+		}
+
+		lightValidators = append(lightValidators, lightValidator)
+	}
+
+	return lightValidators
+}
+
+// validateValidatorsMatchVotes ensures the validator set is the one which voted in the current round of the consensus state,
+// so it would not be mismatched when building the voting information.
+func validateValidatorsMatchVotes(roundState *enginetypes.RoundState, lightValidators enginetypes.LightValidators) error {
+	round, err := roundState.GetRound()
+	if err != nil {
+		return errors.Wrap(err, "failed to extract current round")
+	}
+
+	if round < 0 || round >= len(roundState.Votes) {
+		return fmt.Errorf("votes of current round %d not found in consensus state", round)
+	}
+
+	preVotes := roundState.Votes[round].PreVotes
+	if len(preVotes) != len(lightValidators) {
+		return fmt.Errorf("validator set size %d does not match the number of pre-votes %d, validators must be at the same height as the consensus state", len(lightValidators), len(preVotes))
+	}
+
+	for i, preVote := range preVotes {
+		if strings.EqualFold(preVote, "nil-Vote") {
+			continue
+		}
+
+		if fingerprintAddress := lightValidators[i].GetFingerPrintAddress(); !strings.Contains(preVote, fingerprintAddress) {
+			return fmt.Errorf("validator at index %d (%s) could not be found in pre-vote %s, validators must be at the same height as the consensus state", i, fingerprintAddress, preVote)
+		}
+	}
+
+	return nil
+}
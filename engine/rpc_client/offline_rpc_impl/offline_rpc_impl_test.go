@@ -0,0 +1,216 @@
+package offline_rpc_impl
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/tendermint/crypto/ed25519"
+	"github.com/tendermint/tendermint/libs/json"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+	"strings"
+	"testing"
+)
+
+func newTestValidators(votingPowers ...int64) []*tmtypes.Validator {
+	var validators []*tmtypes.Validator
+	for i, votingPower := range votingPowers {
+		privKey := ed25519.GenPrivKeyFromSecret([]byte(fmt.Sprintf("val%d", i)))
+		validators = append(validators, tmtypes.NewValidator(privKey.PubKey(), votingPower))
+	}
+	return validators
+}
+
+func preVoteOf(index int, validator *tmtypes.Validator) string {
+	return fmt.Sprintf("Vote{%d:%s 100/00/SIGNED_MSG_TYPE_PREVOTE(Prevote) C0FFEE000000 5C8A2B6F2E6B @ 2023-12-25T03:00:01.000000000Z}", index, strings.ToUpper(validator.Address.String())[:12])
+}
+
+func TestNewOfflineRpcClient(t *testing.T) {
+	validators := newTestValidators(300, 100)
+
+	//goland:noinspection SpellCheckingInspection
+	consensusStateJson := fmt.Sprintf(`{
+  "jsonrpc": "2.0",
+  "id": -1,
+  "result": {
+    "round_state": {
+      "height/round/step": "100/0/6",
+      "start_time": "2023-12-25T03:00:00Z",
+      "height_vote_set": [
+        {
+          "round": 0,
+          "prevotes": ["%s", "nil-Vote"],
+          "prevotes_bit_array": "BA{2:x_} 300/400 = 0.75",
+          "precommits": ["nil-Vote", "nil-Vote"],
+          "precommits_bit_array": "BA{2:__} 0/400 = 0.00"
+        }
+      ]
+    }
+  }
+}`, preVoteOf(0, validators[0]))
+
+	validatorsJsonBz, err := json.Marshal(coretypes.ResultValidators{
+		BlockHeight: 100,
+		Validators:  validators,
+		Count:       len(validators),
+		Total:       len(validators),
+	})
+	require.NoError(t, err)
+
+	t.Run("consensus state with validators", func(t *testing.T) {
+		rpcClient, err := NewOfflineRpcClient([]byte(consensusStateJson), [][]byte{validatorsJsonBz})
+		require.NoError(t, err)
+
+		roundState, err := rpcClient.ConsensusState()
+		require.NoError(t, err)
+		require.Equal(t, "100/0/6", roundState.HeightRoundStep)
+		require.Len(t, roundState.Votes, 1)
+
+		lightValidators, err := rpcClient.LightValidators()
+		require.NoError(t, err)
+		require.Len(t, lightValidators, 2)
+		require.Equal(t, 0, lightValidators[0].Index)
+		require.Equal(t, strings.ToUpper(validators[0].Address.String()), lightValidators[0].Address)
+		require.Equal(t, lightValidators[0].Address[:12], lightValidators[0].Moniker)
+		require.Equal(t, int64(300), lightValidators[0].VotingPower)
+		require.Equal(t, 75.0, lightValidators[0].VotingPowerDisplayPercent)
+		require.Equal(t, 25.0, lightValidators[1].VotingPowerDisplayPercent)
+	})
+
+	t.Run("validators in multiple pages", func(t *testing.T) {
+		pageJsonBz := func(validators ...*tmtypes.Validator) []byte {
+			bz, err := json.Marshal(coretypes.ResultValidators{
+				BlockHeight: 100,
+				Validators:  validators,
+				Count:       len(validators),
+				Total:       2,
+			})
+			require.NoError(t, err)
+			return bz
+		}
+
+		_, err := NewOfflineRpcClient([]byte(consensusStateJson), [][]byte{pageJsonBz(validators[0])})
+		require.ErrorContains(t, err, "incomplete validator set 1/2")
+
+		// pages provided in any order, duplicated page is ignored
+		rpcClient, err := NewOfflineRpcClient([]byte(consensusStateJson), [][]byte{
+			pageJsonBz(validators[1]),
+			pageJsonBz(validators[0]),
+			pageJsonBz(validators[1]),
+		})
+		require.NoError(t, err)
+
+		lightValidators, err := rpcClient.LightValidators()
+		require.NoError(t, err)
+		require.Len(t, lightValidators, 2)
+		require.Equal(t, strings.ToUpper(validators[0].Address.String()), lightValidators[0].Address)
+		require.Equal(t, strings.ToUpper(validators[1].Address.String()), lightValidators[1].Address)
+
+		otherHeightJsonBz, err := json.Marshal(coretypes.ResultValidators{
+			BlockHeight: 101,
+			Validators:  validators[1:],
+			Count:       1,
+			Total:       2,
+		})
+		require.NoError(t, err)
+		_, err = NewOfflineRpcClient([]byte(consensusStateJson), [][]byte{pageJsonBz(validators[0]), otherHeightJsonBz})
+		require.ErrorContains(t, err, "page 2 is at height 101")
+	})
+
+	t.Run("consensus state without validators", func(t *testing.T) {
+		_, err := NewOfflineRpcClient([]byte(consensusStateJson), nil)
+		require.ErrorContains(t, err, "validators are required")
+	})
+
+	t.Run("mismatched validators", func(t *testing.T) {
+		otherValidatorsJsonBz, err := json.Marshal(coretypes.ResultValidators{
+			BlockHeight: 100,
+			Validators:  newTestValidators(300, 100, 1),
+			Count:       3,
+			Total:       3,
+		})
+		require.NoError(t, err)
+
+		_, err = NewOfflineRpcClient([]byte(consensusStateJson), [][]byte{otherValidatorsJsonBz})
+		require.ErrorContains(t, err, "does not match the number of pre-votes")
+
+		// same validators, different voting powers, so the order of the validator set is swapped
+		swappedValidatorsJsonBz, err := json.Marshal(coretypes.ResultValidators{
+			BlockHeight: 100,
+			Validators:  newTestValidators(100, 300),
+			Count:       2,
+			Total:       2,
+		})
+		require.NoError(t, err)
+
+		_, err = NewOfflineRpcClient([]byte(consensusStateJson), [][]byte{swappedValidatorsJsonBz})
+		require.ErrorContains(t, err, "could not be found in pre-vote")
+	})
+
+	t.Run("dump consensus state", func(t *testing.T) {
+		validatorSetJsonBz, err := json.Marshal(dumpValidatorSet{
+			Validators: validators,
+		})
+		require.NoError(t, err)
+
+		//goland:noinspection SpellCheckingInspection
+		dumpConsensusStateJson := fmt.Sprintf(`{
+  "round_state": {
+    "height": "100",
+    "round": 1,
+    "step": 4,
+    "start_time": "2023-12-25T03:00:00Z",
+    "validators": %s,
+    "votes": [
+      {
+        "round": 0,
+        "prevotes": ["nil-Vote", "nil-Vote"],
+        "prevotes_bit_array": "BA{2:__} 0/400 = 0.00",
+        "precommits": ["nil-Vote", "nil-Vote"],
+        "precommits_bit_array": "BA{2:__} 0/400 = 0.00"
+      },
+      {
+        "round": 1,
+        "prevotes": ["%s", "nil-Vote"],
+        "prevotes_bit_array": "BA{2:x_} 300/400 = 0.75",
+        "precommits": ["nil-Vote", "nil-Vote"],
+        "precommits_bit_array": "BA{2:__} 0/400 = 0.00"
+      }
+    ]
+  },
+  "peers": [
+    {
+      "node_address": "abc@1.2.3.4:26656",
+      "node_info": {
+        "network": "cosmoshub-4"
+      }
+    }
+  ]
+}`, string(validatorSetJsonBz), preVoteOf(0, validators[0]))
+
+		rpcClient, err := NewOfflineRpcClient([]byte(dumpConsensusStateJson), nil)
+		require.NoError(t, err)
+
+		chainId, _, _ := rpcClient.NodeInfo()
+		require.Equal(t, "cosmoshub-4", chainId)
+
+		roundState, err := rpcClient.ConsensusState()
+		require.NoError(t, err)
+		require.Equal(t, "100/1/4", roundState.HeightRoundStep)
+		require.Len(t, roundState.Votes, 2)
+
+		lightValidators, err := rpcClient.LightValidators()
+		require.NoError(t, err)
+		require.Len(t, lightValidators, 2)
+	})
+
+	t.Run("not a consensus state", func(t *testing.T) {
+		_, err := NewOfflineRpcClient([]byte(`{"result":{"node_info":{}}}`), [][]byte{validatorsJsonBz})
+		require.ErrorContains(t, err, "not a response of")
+
+		_, err = NewOfflineRpcClient([]byte(`{"jsonrpc":"2.0","id":-1,"error":{"code":-32603,"message":"Internal error","data":"boom"}}`), [][]byte{validatorsJsonBz})
+		require.ErrorContains(t, err, "saved response is an error")
+
+		_, err = NewOfflineRpcClient([]byte(`[]`), [][]byte{validatorsJsonBz})
+		require.Error(t, err)
+	})
+}
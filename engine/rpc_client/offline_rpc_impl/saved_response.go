@@ -0,0 +1,188 @@
+package offline_rpc_impl
+
+//goland:noinspection SpellCheckingInspection
+import (
+	stdjson "encoding/json"
+	"fmt"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"github.com/pkg/errors"
+	"github.com/tendermint/tendermint/libs/json"
+	coretypes "github.com/tendermint/tendermint/rpc/core/types"
+	tmtypes "github.com/tendermint/tendermint/types"
+	"sort"
+	"time"
+)
+
+// savedConsensusState holds the information parsed from a saved consensus state response.
+type savedConsensusState struct {
+	roundState *enginetypes.RoundState
+	validators []*tmtypes.Validator // only available in '/dump_consensus_state'
+	chainId    string               // only available in '/dump_consensus_state', when there is any peer
+}
+
+// dumpRoundState is the round state returned by '/dump_consensus_state'.
+//
+//goland:noinspection SpellCheckingInspection
+type dumpRoundState struct {
+	Height     int64                    `json:"height"`
+	Round      int32                    `json:"round"`
+	Step       uint8                    `json:"step"`
+	StartTime  time.Time                `json:"start_time"`
+	Validators *dumpValidatorSet        `json:"validators"`
+	Votes      []enginetypes.RoundVotes `json:"votes"`
+}
+
+type dumpValidatorSet struct {
+	Validators []*tmtypes.Validator `json:"validators"`
+}
+
+type dumpPeer struct {
+	NodeInfo struct {
+		Network string `json:"network"`
+	} `json:"node_info"`
+}
+
+// parseSavedConsensusState parses the saved response of '/consensus_state' or '/dump_consensus_state'.
+// The JSON-RPC envelope is optional, the 'result' or the 'round_state' object alone are accepted as well.
+func parseSavedConsensusState(bz []byte) (*savedConsensusState, error) {
+	obj, err := unwrapJsonRpcResult(bz)
+	if err != nil {
+		return nil, err
+	}
+
+	var peers []dumpPeer
+	if rawRoundState, found := obj["round_state"]; found {
+		if rawPeers, found := obj["peers"]; found {
+			if err := json.Unmarshal(rawPeers, &peers); err != nil {
+				return nil, errors.Wrap(err, "failed to unmarshal peers")
+			}
+		}
+
+		obj = nil
+		if err := stdjson.Unmarshal(rawRoundState, &obj); err != nil {
+			return nil, errors.Wrap(err, "round state is not a JSON object")
+		}
+	}
+
+	rawRoundState, err := stdjson.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, found := obj["height/round/step"]; found { // '/consensus_state'
+		var roundState enginetypes.RoundState
+		if err := json.Unmarshal(rawRoundState, &roundState); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal round state")
+		}
+
+		return &savedConsensusState{
+			roundState: &roundState,
+		}, nil
+	}
+
+	if _, found := obj["votes"]; found { // '/dump_consensus_state'
+		var dump dumpRoundState
+		if err := json.Unmarshal(rawRoundState, &dump); err != nil {
+			return nil, errors.Wrap(err, "failed to unmarshal dump round state")
+		}
+
+		result := &savedConsensusState{
+			roundState: &enginetypes.RoundState{
+				HeightRoundStep: fmt.Sprintf("%d/%d/%d", dump.Height, dump.Round, dump.Step),
+				StartTime:       dump.StartTime,
+				Votes:           dump.Votes,
+			},
+		}
+
+		if dump.Validators != nil {
+			result.validators = dump.Validators.Validators
+		}
+
+		for _, peer := range peers {
+			if len(peer.NodeInfo.Network) > 0 {
+				result.chainId = peer.NodeInfo.Network
+				break
+			}
+		}
+
+		return result, nil
+	}
+
+	return nil, fmt.Errorf("not a response of '/consensus_state' or '/dump_consensus_state'")
+}
+
+// parseSavedValidators parses the saved responses of '/validators', one per page.
+// The JSON-RPC envelope is optional, the 'result' object alone is accepted as well.
+// Pages are merged and ordered by index, which is the order of the validator set: voting power desc, then address asc.
+func parseSavedValidators(pages [][]byte) ([]*tmtypes.Validator, error) {
+	var blockHeight int64
+	var total int
+	validatorByAddress := make(map[string]*tmtypes.Validator)
+
+	for i, bz := range pages {
+		obj, err := unwrapJsonRpcResult(bz)
+		if err != nil {
+			return nil, errors.Wrapf(err, "page %d", i+1)
+		}
+
+		rawResult, err := stdjson.Marshal(obj)
+		if err != nil {
+			return nil, err
+		}
+
+		var result coretypes.ResultValidators
+		if err := json.Unmarshal(rawResult, &result); err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal validators of page %d", i+1)
+		}
+
+		if i == 0 {
+			blockHeight = result.BlockHeight
+			total = result.Total
+		} else if result.BlockHeight != blockHeight {
+			return nil, fmt.Errorf("page %d is at height %d, expected %d", i+1, result.BlockHeight, blockHeight)
+		} else if result.Total != total {
+			return nil, fmt.Errorf("page %d has total %d, expected %d", i+1, result.Total, total)
+		}
+
+		for _, validator := range result.Validators {
+			validatorByAddress[validator.Address.String()] = validator
+		}
+	}
+
+	validators := make([]*tmtypes.Validator, 0, len(validatorByAddress))
+	for _, validator := range validatorByAddress {
+		validators = append(validators, validator)
+	}
+	sort.Sort(tmtypes.ValidatorsByVotingPower(validators))
+
+	if total > len(validators) {
+		return nil, fmt.Errorf("incomplete validator set %d/%d, provide all the pages or save the response with larger 'per_page'", len(validators), total)
+	}
+
+	return validators, nil
+}
+
+// unwrapJsonRpcResult returns the 'result' object of a JSON-RPC response,
+// or the input object itself if it is not wrapped in a JSON-RPC envelope.
+func unwrapJsonRpcResult(bz []byte) (map[string]stdjson.RawMessage, error) {
+	var obj map[string]stdjson.RawMessage
+	if err := stdjson.Unmarshal(bz, &obj); err != nil {
+		return nil, errors.Wrap(err, "not a JSON object")
+	}
+
+	if rawError, found := obj["error"]; found && string(rawError) != "null" {
+		var rpcErr enginetypes.BaseRpcResponseError
+		if err := stdjson.Unmarshal(rawError, &rpcErr); err == nil && rpcErr.GetError() != nil {
+			return nil, errors.Wrap(rpcErr.GetError(), "saved response is an error")
+		}
+	}
+
+	if rawResult, found := obj["result"]; found {
+		obj = nil
+		if err := stdjson.Unmarshal(rawResult, &obj); err != nil {
+			return nil, errors.Wrap(err, "result is not a JSON object")
+		}
+	}
+
+	return obj, nil
+}
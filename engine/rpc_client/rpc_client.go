@@ -3,11 +3,26 @@ package rpc_client
 //goland:noinspection SpellCheckingInspection
 import (
 	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	slashingtypes "github.com/cosmos/cosmos-sdk/x/slashing/types"
 	stakingtypes "github.com/cosmos/cosmos-sdk/x/staking/types"
 	coretypes "github.com/tendermint/tendermint/rpc/core/types"
 	tmtypes "github.com/tendermint/tendermint/types"
+	"time"
 )
 
+// RPC method names, provided to RpcObserver.
+const (
+	RpcMethodStatus         = "status"
+	RpcMethodConsensusState = "consensus_state"
+	RpcMethodValidators     = "validators"
+	RpcMethodCommit         = "commit"
+	RpcMethodAbciQuery      = "abci_query"
+)
+
+// RpcObserver is notified of each request to the RPC server, including retries, eg: to collect metrics.
+// Method is one of RpcMethod*.
+type RpcObserver func(method string, duration time.Duration, err error)
+
 // RpcClient is the interface that abstract the interaction with the RPC server.
 //
 //goland:noinspection GoNameStartsWithPackageName
@@ -23,6 +38,12 @@ type RpcClient interface {
 	// BondedValidators returns the list of bonded validators
 	BondedValidators() ([]stakingtypes.Validator, error)
 
+	// SigningInfos returns the signing information of all validators, from the slashing module.
+	SigningInfos() ([]slashingtypes.ValidatorSigningInfo, error)
+
+	// SlashingParams returns the parameters of the slashing module.
+	SlashingParams() (*slashingtypes.Params, error)
+
 	// ConsensusState fetches the current consensus state from the RPC server ':26657/consensus_state'.
 	ConsensusState() (*enginetypes.RoundState, error)
 
@@ -34,6 +55,12 @@ type RpcClient interface {
 	// CONTRACT: must maintain the same order as the result from the RPC server.
 	LatestValidators() ([]*tmtypes.Validator, error)
 
+	// Commits fetches the commits of the given heights from the RPC server ':26657/commit', concurrently.
+	// Canonical commits are cached, so heights those had been fetched before will not be fetched again.
+	//
+	// Result is a map of height to commit, all requested heights are present if no error.
+	Commits(heights []int64) (map[int64]*tmtypes.Commit, error)
+
 	// Shutdown must be called when the RPC client is no longer needed.
 	// It does close up all the connections to the RPC server and free resources.
 	Shutdown() error
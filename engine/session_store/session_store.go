@@ -0,0 +1,202 @@
+package session_store
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"encoding/json"
+	"github.com/bcdevtools/consvp/constants"
+	coretypes "github.com/bcdevtools/cvp-streaming-core/types"
+	"github.com/pkg/errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	stateFileName = "streaming_sessions.json"
+
+	// stateFilePermission restricts the state file to the owner only, because it contains session keys.
+	stateFilePermission = 0o600
+	stateDirPermission  = 0o700
+)
+
+// StreamingSession holds the credentials of a streaming session, to be able to resume it later.
+type StreamingSession struct {
+	SessionId  coretypes.PreVoteStreamingSessionId  `json:"session_id"`
+	SessionKey coretypes.PreVoteStreamingSessionKey `json:"session_key"`
+	ChainId    string                               `json:"chain_id"`
+	ServerUrl  string                               `json:"server_url"`
+	CreatedAt  time.Time                            `json:"created_at"`
+	ExpiresAt  time.Time                            `json:"expires_at"`
+
+	// CodecVersion is the codec version negotiated with the streaming server when the session registered.
+	CodecVersion string `json:"codec_version,omitempty"`
+}
+
+// IsExpired returns true if the session is expired at the given time.
+func (s StreamingSession) IsExpired(now time.Time) bool {
+	return !now.Before(s.ExpiresAt)
+}
+
+// SessionStore persists streaming session credentials into a state file, readable by the owner only.
+type SessionStore struct {
+	mutex    *sync.Mutex
+	filePath string
+}
+
+// NewSessionStore returns a SessionStore which persists into the given state file.
+func NewSessionStore(filePath string) *SessionStore {
+	return &SessionStore{
+		mutex:    &sync.Mutex{},
+		filePath: filePath,
+	}
+}
+
+// DefaultStateFilePath returns the default path of the state file, inside the app directory of user home.
+func DefaultStateFilePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get user home directory")
+	}
+	return filepath.Join(homeDir, constants.HOME_DIR_NAME, stateFileName), nil
+}
+
+// FilePath returns the path of the state file.
+func (ss *SessionStore) FilePath() string {
+	return ss.filePath
+}
+
+// Save persists the given session, replacing the existing one with the same session ID.
+// Expired sessions are removed from the state file.
+func (ss *SessionStore) Save(session StreamingSession, now time.Time) error {
+	ss.mutex.Lock()
+	defer ss.mutex.Unlock()
+
+	sessions, err := ss.load()
+	if err != nil {
+		return err
+	}
+
+	var keep []StreamingSession
+	for _, existing := range sessions {
+		if existing.SessionId == session.SessionId || existing.IsExpired(now) {
+			continue
+		}
+		keep = append(keep, existing)
+	}
+	keep = append(keep, session)
+
+	return ss.write(keep)
+}
+
+// Latest returns the latest non-expired session of the given chain and streaming server, nil if not any.
+func (ss *SessionStore) Latest(chainId, serverUrl string, now time.Time) (*StreamingSession, error) {
+	ss.mutex.Lock()
+	defer ss.mutex.Unlock()
+
+	sessions, err := ss.load()
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.After(sessions[j].CreatedAt)
+	})
+
+	for _, session := range sessions {
+		if session.ChainId != chainId || !isSameServerUrl(session.ServerUrl, serverUrl) || session.IsExpired(now) {
+			continue
+		}
+		return &session, nil
+	}
+
+	return nil, nil
+}
+
+// Get returns the non-expired session with the given session ID, nil if not found.
+func (ss *SessionStore) Get(sessionId coretypes.PreVoteStreamingSessionId, now time.Time) (*StreamingSession, error) {
+	ss.mutex.Lock()
+	defer ss.mutex.Unlock()
+
+	sessions, err := ss.load()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, session := range sessions {
+		if session.SessionId == sessionId && !session.IsExpired(now) {
+			return &session, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// load reads all sessions from the state file, returns empty if the file does not exist.
+//
+// CONTRACT: caller must hold the mutex.
+func (ss *SessionStore) load() ([]StreamingSession, error) {
+	bz, err := os.ReadFile(ss.filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to read state file")
+	}
+
+	var sessions []StreamingSession
+	if err := json.Unmarshal(bz, &sessions); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal state file")
+	}
+
+	return sessions, nil
+}
+
+// write replaces the state file with the given sessions.
+// Content is written into a temporary file then renamed, so the state file is never partially written.
+//
+// CONTRACT: caller must hold the mutex.
+func (ss *SessionStore) write(sessions []StreamingSession) error {
+	bz, err := json.MarshalIndent(sessions, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal sessions")
+	}
+
+	if err := os.MkdirAll(filepath.Dir(ss.filePath), stateDirPermission); err != nil {
+		return errors.Wrap(err, "failed to create state directory")
+	}
+
+	tmpFile, err := os.CreateTemp(filepath.Dir(ss.filePath), stateFileName+".*.tmp")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temporary state file")
+	}
+	defer func() {
+		_ = os.Remove(tmpFile.Name())
+	}()
+
+	if err := tmpFile.Chmod(stateFilePermission); err != nil {
+		_ = tmpFile.Close()
+		return errors.Wrap(err, "failed to restrict permission of state file")
+	}
+
+	if _, err := tmpFile.Write(bz); err != nil {
+		_ = tmpFile.Close()
+		return errors.Wrap(err, "failed to write state file")
+	}
+
+	if err := tmpFile.Close(); err != nil {
+		return errors.Wrap(err, "failed to close state file")
+	}
+
+	if err := os.Rename(tmpFile.Name(), ss.filePath); err != nil {
+		return errors.Wrap(err, "failed to replace state file")
+	}
+
+	return nil
+}
+
+func isSameServerUrl(a, b string) bool {
+	return strings.EqualFold(strings.TrimSuffix(a, "/"), strings.TrimSuffix(b, "/"))
+}
@@ -0,0 +1,95 @@
+package session_store
+
+import (
+	coretypes "github.com/bcdevtools/cvp-streaming-core/types"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestSession(t *testing.T, chainId, serverUrl string, createdAt time.Time) StreamingSession {
+	sessionId, sessionKey, err := coretypes.NewPreVoteStreamingSession(chainId)
+	require.NoError(t, err)
+
+	return StreamingSession{
+		SessionId:  sessionId,
+		SessionKey: sessionKey,
+		ChainId:    chainId,
+		ServerUrl:  serverUrl,
+		CreatedAt:  createdAt,
+		ExpiresAt:  createdAt.Add(12 * time.Hour),
+
+		CodecVersion: "v3",
+	}
+}
+
+func TestSessionStore(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "sub", stateFileName)
+	store := NewSessionStore(filePath)
+
+	now := time.Date(2023, 12, 25, 3, 0, 0, 0, time.UTC)
+
+	latest, err := store.Latest("cosmoshub-4", "https://cvp.bcdev.tools", now)
+	require.NoError(t, err)
+	require.Nil(t, latest, "state file does not exist yet")
+
+	older := newTestSession(t, "cosmoshub-4", "https://cvp.bcdev.tools", now.Add(-2*time.Hour))
+	newer := newTestSession(t, "cosmoshub-4", "https://cvp.bcdev.tools", now.Add(-1*time.Hour))
+	otherChain := newTestSession(t, "osmosis-1", "https://cvp.bcdev.tools", now)
+	otherServer := newTestSession(t, "cosmoshub-4", "http://localhost:8080", now)
+	expired := newTestSession(t, "cosmoshub-4", "https://cvp.bcdev.tools", now.Add(-13*time.Hour))
+
+	for _, session := range []StreamingSession{newer, older, otherChain, otherServer, expired} {
+		require.NoError(t, store.Save(session, now.Add(-13*time.Hour)))
+	}
+
+	fileInfo, err := os.Stat(filePath)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), fileInfo.Mode().Perm())
+
+	latest, err = store.Latest("cosmoshub-4", "https://cvp.bcdev.tools/", now)
+	require.NoError(t, err)
+	require.NotNil(t, latest)
+	require.Equal(t, newer, *latest)
+
+	latest, err = store.Latest("cosmoshub-4", "http://localhost:8080", now)
+	require.NoError(t, err)
+	require.NotNil(t, latest)
+	require.Equal(t, otherServer.SessionId, latest.SessionId)
+
+	latest, err = store.Latest("juno-1", "https://cvp.bcdev.tools", now)
+	require.NoError(t, err)
+	require.Nil(t, latest)
+
+	session, err := store.Get(older.SessionId, now)
+	require.NoError(t, err)
+	require.NotNil(t, session)
+	require.Equal(t, older.SessionKey, session.SessionKey)
+
+	session, err = store.Get(expired.SessionId, now)
+	require.NoError(t, err)
+	require.Nil(t, session, "expired session should not be returned")
+
+	// saving removes expired sessions
+	require.NoError(t, store.Save(otherChain, now))
+	sessions, err := store.load()
+	require.NoError(t, err)
+	require.Len(t, sessions, 4)
+	for _, s := range sessions {
+		require.NotEqual(t, expired.SessionId, s.SessionId)
+	}
+}
+
+func TestSessionStoreCorruptedFile(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), stateFileName)
+	require.NoError(t, os.WriteFile(filePath, []byte("not json"), 0o600))
+
+	store := NewSessionStore(filePath)
+
+	_, err := store.Latest("cosmoshub-4", "https://cvp.bcdev.tools", time.Now())
+	require.Error(t, err)
+
+	require.Error(t, store.Save(newTestSession(t, "cosmoshub-4", "https://cvp.bcdev.tools", time.Now()), time.Now()))
+}
@@ -0,0 +1,61 @@
+package streaming_server
+
+//goland:noinspection SpellCheckingInspection
+import (
+	pvss "github.com/bcdevtools/consvp/engine/prevote_streaming_service"
+	corecodec "github.com/bcdevtools/cvp-streaming-core/codec"
+	coretypes "github.com/bcdevtools/cvp-streaming-core/types"
+	"time"
+)
+
+// session holds the state of a pre-vote streaming session.
+type session struct {
+	chainId    string
+	sessionKey coretypes.PreVoteStreamingSessionKey
+	expiry     time.Time
+
+	// codecVersion is the codec version used by the broadcaster when registering the session.
+	codecVersion corecodec.CvpCodecVersion
+
+	validators coretypes.StreamingLightValidators
+
+	// othersBucket is provided when the session was registered with truncated validator set,
+	// the validator at its index is the pseudo validator aggregating the validators not being streamed.
+	othersBucket *pvss.OthersBucket
+
+	// latestEncoded is the latest broadcast content in encoded form, used to detect duplicated content.
+	latestEncoded []byte
+	latest        *coretypes.StreamingNextBlockVotingInformation
+	updatedAt     time.Time
+
+	// latestOthersVotes is the latest broadcast votes of the others bucket, nil if not truncated or not provided.
+	latestOthersVotes *pvss.OthersBucketVotes
+}
+
+// isExpired returns true if the session is expired at the given time.
+func (s *session) isExpired(now time.Time) bool {
+	return !now.Before(s.expiry)
+}
+
+// hasValidatorIndex returns true if the validator with the given index was registered in the session.
+func (s *session) hasValidatorIndex(index int) bool {
+	for _, validator := range s.validators {
+		if validator.Index == index {
+			return true
+		}
+	}
+	return false
+}
+
+// isOthersBucket returns true if the validator with the given index is the pseudo validator of the others bucket.
+func (s *session) isOthersBucket(index int) bool {
+	return s.othersBucket != nil && s.othersBucket.Index == index
+}
+
+// isSameOthersVotes returns true if the given votes of the others bucket are the same as the latest broadcast.
+func (s *session) isSameOthersVotes(votes *pvss.OthersBucketVotes) bool {
+	if s.latestOthersVotes == nil || votes == nil {
+		return s.latestOthersVotes == votes
+	}
+	return *s.latestOthersVotes == *votes
+}
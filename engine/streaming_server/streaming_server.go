@@ -0,0 +1,483 @@
+package streaming_server
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"bytes"
+	"crypto/subtle"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	pvss "github.com/bcdevtools/consvp/engine/prevote_streaming_service"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	corecodec "github.com/bcdevtools/cvp-streaming-core/codec"
+	coreconstants "github.com/bcdevtools/cvp-streaming-core/constants"
+	coretypes "github.com/bcdevtools/cvp-streaming-core/types"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultSessionDuration is the default duration of a streaming session, since registered.
+	DefaultSessionDuration = 12 * time.Hour
+
+	// DefaultMaxSessions is the default maximum number of sessions can be held at the same time.
+	DefaultMaxSessions = 100
+
+	// expiredSessionRetention is the duration expired sessions are kept,
+	// so the broadcaster receives 401 (session timed out) instead of 404 (session not found).
+	expiredSessionRetention = 1 * time.Hour
+
+	// maxRequestBodyBytes is the maximum size of request body to be read.
+	maxRequestBodyBytes = 64 * 1024
+)
+
+//go:embed viewer.html
+var viewerHtml []byte
+
+// StreamingServer is a self-hosted implementation of the pre-vote streaming server,
+// serves the register, resume and broadcast endpoints which the streaming client calls, plus a viewer page.
+type StreamingServer struct {
+	mutex    *sync.RWMutex
+	sessions map[coretypes.PreVoteStreamingSessionId]*session
+
+	sessionDuration time.Duration
+	maxSessions     int
+
+	nowFunc func() time.Time // to be replaced in tests
+}
+
+// NewStreamingServer returns a new StreamingServer.
+// Sessions expire after the given duration, and at most maxSessions sessions can be held at the same time.
+func NewStreamingServer(sessionDuration time.Duration, maxSessions int) *StreamingServer {
+	if sessionDuration <= 0 {
+		panic("session duration must be positive")
+	}
+	if maxSessions < 1 {
+		panic("max sessions must be positive")
+	}
+
+	return &StreamingServer{
+		mutex:           &sync.RWMutex{},
+		sessions:        make(map[coretypes.PreVoteStreamingSessionId]*session),
+		sessionDuration: sessionDuration,
+		maxSessions:     maxSessions,
+		nowFunc: func() time.Time {
+			return time.Now().UTC()
+		},
+	}
+}
+
+// Handler returns the HTTP handler serving all the endpoints.
+func (s *StreamingServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/"+pathPrefix(coreconstants.STREAMING_PATH_REGISTER_PRE_VOTE), s.handleRegisterSession)
+	mux.HandleFunc("/"+pathPrefix(coreconstants.STREAMING_PATH_RESUME_PRE_VOTE), s.handleResumeSession)
+	mux.HandleFunc("/"+pathPrefix(coreconstants.STREAMING_PATH_BROADCAST_PRE_VOTE), s.handleBroadcastPreVote)
+	mux.HandleFunc("/"+pathPrefix(coreconstants.STREAMING_PATH_VIEW_PRE_VOTE), s.handleView)
+	return mux
+}
+
+// handleRegisterSession handles 'POST /register-session/pre-vote/:chainId', body is the encoded light validators.
+// The others bucket is provided via header when the validator set was truncated.
+func (s *StreamingServer) handleRegisterSession(w http.ResponseWriter, r *http.Request) {
+	chainId, ok := pathParam(w, r, http.MethodPost, coreconstants.STREAMING_PATH_REGISTER_PRE_VOTE)
+	if !ok {
+		return
+	}
+
+	bz, ok := readBody(w, r)
+	if !ok {
+		return
+	}
+
+	codecVersion, detected := corecodec.DetectEncodingVersion(bz)
+	if !detected {
+		http.Error(w, "deprecated codec version or unsupported content type", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	validators, err := corecodec.NewProxyCvpCodec().DecodeStreamingLightValidators(bz)
+	if err != nil {
+		http.Error(w, "failed to decode validators", http.StatusBadRequest)
+		return
+	}
+	if len(validators) < 1 || len(validators) > coreconstants.MAX_VALIDATORS {
+		http.Error(w, fmt.Sprintf("number of validators must be between 1 and %d", coreconstants.MAX_VALIDATORS), http.StatusBadRequest)
+		return
+	}
+
+	newSession := &session{
+		chainId:      chainId,
+		codecVersion: codecVersion,
+		validators:   validators,
+	}
+
+	if header := r.Header.Get(pvss.HeaderOthersBucket); len(header) > 0 {
+		othersBucket, err := pvss.DecodeOthersBucketHeader(header)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !newSession.hasValidatorIndex(othersBucket.Index) {
+			http.Error(w, fmt.Sprintf("validator index %d of the others bucket was not registered", othersBucket.Index), http.StatusBadRequest)
+			return
+		}
+		newSession.othersBucket = othersBucket
+	}
+
+	sessionId, sessionKey, err := coretypes.NewPreVoteStreamingSession(chainId)
+	if err != nil {
+		http.Error(w, "invalid chain id", http.StatusBadRequest)
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.nowFunc()
+	s.pruneExpiredSessions(now)
+	if len(s.sessions) >= s.maxSessions {
+		http.Error(w, "too many sessions", http.StatusTooManyRequests)
+		return
+	}
+
+	newSession.sessionKey = sessionKey
+	newSession.expiry = now.Add(s.sessionDuration)
+	s.sessions[sessionId] = newSession
+
+	writeJson(w, http.StatusCreated, coretypes.PreVoteStreamingSessionRegistrationResponse{
+		SessionId:  sessionId,
+		SessionKey: sessionKey,
+	})
+}
+
+// handleResumeSession handles 'POST /resume-session/pre-vote/:sessionId', body is the session key.
+func (s *StreamingServer) handleResumeSession(w http.ResponseWriter, r *http.Request) {
+	sessionId, ok := pathParam(w, r, http.MethodPost, coreconstants.STREAMING_PATH_RESUME_PRE_VOTE)
+	if !ok {
+		return
+	}
+
+	bz, ok := readBody(w, r)
+	if !ok {
+		return
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	if _, ok := s.getAuthorizedSession(w, sessionId, strings.TrimSpace(string(bz))); !ok {
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// handleBroadcastPreVote handles 'POST /broadcast/pre-vote/:sessionId',
+// session key is provided via header, body is the encoded next block voting information.
+// Votes of the others bucket are provided via header, only considered when the session was registered as truncated.
+func (s *StreamingServer) handleBroadcastPreVote(w http.ResponseWriter, r *http.Request) {
+	sessionId, ok := pathParam(w, r, http.MethodPost, coreconstants.STREAMING_PATH_BROADCAST_PRE_VOTE)
+	if !ok {
+		return
+	}
+
+	bz, ok := readBody(w, r)
+	if !ok {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	ss, ok := s.getAuthorizedSession(w, sessionId, r.Header.Get(coreconstants.STREAMING_HEADER_SESSION_KEY))
+	if !ok {
+		return
+	}
+
+	if _, detected := corecodec.DetectEncodingVersion(bz); !detected {
+		http.Error(w, "deprecated codec version or unsupported content type", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	var othersVotes *pvss.OthersBucketVotes
+	if ss.othersBucket != nil {
+		if header := r.Header.Get(pvss.HeaderOthersBucketVotes); len(header) > 0 {
+			var err error
+			othersVotes, err = pvss.DecodeOthersBucketVotesHeader(header, *ss.othersBucket)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
+	if ss.latestEncoded != nil && bytes.Equal(ss.latestEncoded, bz) && ss.isSameOthersVotes(othersVotes) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	information, err := corecodec.NewProxyCvpCodec().DecodeStreamingNextBlockVotingInformation(bz)
+	if err != nil {
+		http.Error(w, "failed to decode voting information", http.StatusBadRequest)
+		return
+	}
+
+	for _, voteState := range information.ValidatorVoteStates {
+		if !ss.hasValidatorIndex(voteState.ValidatorIndex) {
+			http.Error(w, fmt.Sprintf("validator index %d was not registered", voteState.ValidatorIndex), http.StatusBadRequest)
+			return
+		}
+	}
+
+	if ss.latest != nil && isOutdated(information.HeightRoundStep, ss.latest.HeightRoundStep) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	ss.latestEncoded = bz
+	ss.latest = information
+	ss.latestOthersVotes = othersVotes
+	ss.updatedAt = s.nowFunc()
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleView handles 'GET /pvtop/:sessionId' which serves the viewer page,
+// and 'GET /pvtop/:sessionId/update' which serves the latest voting information for the viewer page.
+func (s *StreamingServer) handleView(w http.ResponseWriter, r *http.Request) {
+	param, ok := pathParam(w, r, http.MethodGet, coreconstants.STREAMING_PATH_VIEW_PRE_VOTE)
+	if !ok {
+		return
+	}
+
+	updateSuffix := strings.TrimPrefix(coreconstants.STREAMING_PATH_VIEW_PRE_VOTE_FETCH_UPDATE, coreconstants.STREAMING_PATH_VIEW_PRE_VOTE)
+	isFetchUpdate := strings.HasSuffix(param, updateSuffix)
+	sessionId := strings.TrimSuffix(param, updateSuffix)
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	ss, found := s.sessions[coretypes.PreVoteStreamingSessionId(sessionId)]
+	if !found {
+		http.Error(w, "session not found", http.StatusNotFound)
+		return
+	}
+
+	if !isFetchUpdate {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(viewerHtml)
+		return
+	}
+
+	writeJson(w, http.StatusOK, s.toViewerUpdate(ss))
+}
+
+// getAuthorizedSession returns the session if found, the session key matches and not expired,
+// otherwise writes the corresponding error status code.
+//
+// CONTRACT: caller must hold the mutex.
+func (s *StreamingServer) getAuthorizedSession(w http.ResponseWriter, sessionId, sessionKey string) (*session, bool) {
+	ss, found := s.sessions[coretypes.PreVoteStreamingSessionId(sessionId)]
+	if !found {
+		http.Error(w, "session not found", http.StatusNotFound)
+		return nil, false
+	}
+
+	if err := coretypes.PreVoteStreamingSessionKey(sessionKey).ValidateBasic(); err != nil {
+		http.Error(w, "bad session key", http.StatusBadRequest)
+		return nil, false
+	}
+
+	if subtle.ConstantTimeCompare([]byte(ss.sessionKey), []byte(sessionKey)) != 1 {
+		http.Error(w, "mis-match session key", http.StatusForbidden)
+		return nil, false
+	}
+
+	if ss.isExpired(s.nowFunc()) {
+		http.Error(w, "session timed out", http.StatusUnauthorized)
+		return nil, false
+	}
+
+	return ss, true
+}
+
+// pruneExpiredSessions removes sessions those had been expired longer than the retention duration.
+//
+// CONTRACT: caller must hold the write-lock.
+func (s *StreamingServer) pruneExpiredSessions(now time.Time) {
+	for sessionId, ss := range s.sessions {
+		if ss.isExpired(now.Add(-expiredSessionRetention)) {
+			delete(s.sessions, sessionId)
+		}
+	}
+}
+
+// isOutdated returns true if the given height/round/step is lower than the latest one.
+func isOutdated(heightRoundStep, latestHeightRoundStep string) bool {
+	height, round, step, err := enginetypes.ParseHeightRoundStep(heightRoundStep)
+	if err != nil {
+		return false
+	}
+
+	latestHeight, latestRound, latestStep, err := enginetypes.ParseHeightRoundStep(latestHeightRoundStep)
+	if err != nil {
+		return false
+	}
+
+	if height != latestHeight {
+		return height < latestHeight
+	}
+	if round != latestRound {
+		return round < latestRound
+	}
+	return step < latestStep
+}
+
+// viewerUpdate is the content served to the viewer page.
+type viewerUpdate struct {
+	ChainId          string                 `json:"chain_id"`
+	Expiry           time.Time              `json:"expiry"`
+	Expired          bool                   `json:"expired"`
+	UpdatedAt        *time.Time             `json:"updated_at,omitempty"`
+	HeightRoundStep  string                 `json:"height_round_step,omitempty"`
+	DurationMs       int64                  `json:"duration_ms"`
+	PreVotePercent   float64                `json:"pre_vote_percent"`
+	PreCommitPercent float64                `json:"pre_commit_percent"`
+	Validators       []viewerValidatorState `json:"validators"`
+
+	// Truncated is provided when the validator set exceeded the streaming limit,
+	// the validators not being streamed are aggregated into this bucket.
+	Truncated *viewerTruncation `json:"truncated,omitempty"`
+}
+
+// viewerTruncation is the aggregation of the validators not being streamed, voted percents are of the whole validator set.
+type viewerTruncation struct {
+	OthersCount                 int     `json:"others_count"`
+	OthersVotingPowerPercent    float64 `json:"others_voting_power_percent"`
+	OthersPreVotedPercent       float64 `json:"others_pre_voted_percent"`
+	OthersPreCommitVotedPercent float64 `json:"others_pre_commit_voted_percent"`
+}
+
+type viewerValidatorState struct {
+	Index              int     `json:"index"`
+	Moniker            string  `json:"moniker"`
+	VotingPowerPercent float64 `json:"voting_power_percent"`
+	PreVoted           bool    `json:"pre_voted"`
+	VotedZeroes        bool    `json:"voted_zeroes"`
+	PreCommitVoted     bool    `json:"pre_commit_voted"`
+	PreVotedBlockHash  string  `json:"pre_voted_block_hash,omitempty"`
+}
+
+// toViewerUpdate builds the content served to the viewer page, validators are sorted descending by voting power.
+//
+// CONTRACT: caller must hold the mutex.
+func (s *StreamingServer) toViewerUpdate(ss *session) viewerUpdate {
+	now := s.nowFunc()
+
+	update := viewerUpdate{
+		ChainId:    ss.chainId,
+		Expiry:     ss.expiry,
+		Expired:    ss.isExpired(now),
+		Validators: make([]viewerValidatorState, 0, len(ss.validators)),
+	}
+
+	voteStates := make(map[int]coretypes.StreamingValidatorVoteState)
+	if ss.latest != nil {
+		updatedAt := ss.updatedAt
+		update.UpdatedAt = &updatedAt
+		update.HeightRoundStep = ss.latest.HeightRoundStep
+		update.DurationMs = (ss.latest.Duration + now.Sub(ss.updatedAt)).Milliseconds()
+		update.PreVotePercent = ss.latest.PreVotedPercent
+		update.PreCommitPercent = ss.latest.PreCommitVotedPercent
+
+		for _, voteState := range ss.latest.ValidatorVoteStates {
+			voteStates[voteState.ValidatorIndex] = voteState
+		}
+	}
+
+	for _, validator := range ss.validators {
+		if ss.isOthersBucket(validator.Index) {
+			continue
+		}
+
+		voteState := voteStates[validator.Index]
+		update.Validators = append(update.Validators, viewerValidatorState{
+			Index:              validator.Index,
+			Moniker:            strings.TrimSpace(validator.Moniker),
+			VotingPowerPercent: validator.VotingPowerDisplayPercent,
+			PreVoted:           voteState.PreVoted,
+			VotedZeroes:        voteState.VotedZeroes,
+			PreCommitVoted:     voteState.PreCommitVoted,
+			PreVotedBlockHash:  voteState.PreVotedBlockHash,
+		})
+	}
+
+	if bucket := ss.othersBucket; bucket != nil {
+		update.Truncated = &viewerTruncation{
+			OthersCount:              bucket.Count,
+			OthersVotingPowerPercent: percentOf(bucket.VotingPower, bucket.TotalVotingPower),
+		}
+		if votes := ss.latestOthersVotes; votes != nil && ss.latest != nil {
+			update.Truncated.OthersPreVotedPercent = percentOf(votes.PreVotedVotingPower, bucket.TotalVotingPower)
+			update.Truncated.OthersPreCommitVotedPercent = percentOf(votes.PreCommitVotedVotingPower, bucket.TotalVotingPower)
+		}
+	}
+
+	sort.SliceStable(update.Validators, func(i, j int) bool {
+		return update.Validators[i].VotingPowerPercent > update.Validators[j].VotingPowerPercent
+	})
+
+	return update
+}
+
+// percentOf returns the percent of the given voting power over the total voting power.
+func percentOf(votingPower, totalVotingPower int64) float64 {
+	if totalVotingPower < 1 {
+		return 0
+	}
+	return float64(votingPower) * 100 / float64(totalVotingPower)
+}
+
+// pathPrefix returns the static prefix of the given path template, which ends right before the first parameter.
+func pathPrefix(pathTemplate string) string {
+	return pathTemplate[:strings.Index(pathTemplate, ":")]
+}
+
+// pathParam validates the request method and returns the path parameter of the given path template,
+// which is the remaining part of the path after the static prefix.
+// Writes the corresponding error status code and returns false if invalid.
+func pathParam(w http.ResponseWriter, r *http.Request, method, pathTemplate string) (string, bool) {
+	if r.Method != method {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return "", false
+	}
+
+	param := strings.TrimPrefix(r.URL.Path, "/"+pathPrefix(pathTemplate))
+	if len(param) < 1 {
+		http.NotFound(w, r)
+		return "", false
+	}
+
+	return param, true
+}
+
+// readBody reads the request body, writes the corresponding error status code and returns false if failed or too large.
+func readBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	bz, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes))
+	if err != nil {
+		http.Error(w, "failed to read request body", http.StatusRequestEntityTooLarge)
+		return nil, false
+	}
+	return bz, true
+}
+
+func writeJson(w http.ResponseWriter, statusCode int, content any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(content)
+}
@@ -0,0 +1,409 @@
+package streaming_server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	pvss "github.com/bcdevtools/consvp/engine/prevote_streaming_service"
+	pvssi "github.com/bcdevtools/consvp/engine/prevote_streaming_service/prevote_ss_impl"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	corecodec "github.com/bcdevtools/cvp-streaming-core/codec"
+	coreconstants "github.com/bcdevtools/cvp-streaming-core/constants"
+	coretypes "github.com/bcdevtools/cvp-streaming-core/types"
+	coreutils "github.com/bcdevtools/cvp-streaming-core/utils"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testLightValidators = enginetypes.LightValidators{
+	{
+		Index:                     0,
+		Moniker:                   "Val1",
+		Address:                   "AA",
+		VotingPower:               100,
+		VotingPowerDisplayPercent: 25,
+	},
+	{
+		Index:                     1,
+		Moniker:                   "Val2",
+		Address:                   "BB",
+		VotingPower:               300,
+		VotingPowerDisplayPercent: 75,
+	},
+}
+
+func newTestVotingInfo(heightRoundStep string) *enginetypes.NextBlockVotingInformation {
+	return &enginetypes.NextBlockVotingInformation{
+		SortedValidatorVoteStates: []enginetypes.ValidatorVoteState{
+			{
+				Validator:       testLightValidators[1],
+				VotingBlockHash: "C0FFEE000000",
+				PreVoted:        true,
+			},
+			{
+				Validator: testLightValidators[0],
+			},
+		},
+		PreVotePercent:  75,
+		HeightRoundStep: heightRoundStep,
+		StartTimeUTC:    time.Now().UTC().Add(-2 * time.Second),
+	}
+}
+
+func TestStreamingServer(t *testing.T) {
+	server := NewStreamingServer(DefaultSessionDuration, DefaultMaxSessions)
+	now := time.Date(2023, 12, 25, 3, 0, 0, 0, time.UTC)
+	server.nowFunc = func() time.Time {
+		return now
+	}
+
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+
+	for _, codec := range []corecodec.CvpCodec{
+		corecodec.GetCvpCodecV3(),
+		corecodec.GetCvpCodecV2(),
+		corecodec.GetCvpCodecV1(),
+	} {
+		t.Run(string(codec.GetVersion()), func(t *testing.T) {
+			streamingService := pvssi.NewPreVoteStreamingService("cosmoshub-4", httpServer.URL, corecodec.WrapCvpCodecInProxy(codec), nil)
+
+			shareViewUrl, err := streamingService.OpenSession(testLightValidators)
+			require.NoError(t, err)
+
+			sessionId, sessionKey := streamingService.ExposeSessionIdAndKey()
+			require.True(t, sessionId.ForChainId("cosmoshub-4"))
+			require.Equal(t, coreutils.GetPublicUrlViewPreVoteStreamingSession(httpServer.URL, string(sessionId)), shareViewUrl)
+
+			err, shouldStop := streamingService.BroadcastPreVote(newTestVotingInfo("100/0/1"))
+			require.NoError(t, err)
+			require.False(t, shouldStop)
+
+			// outdated content
+			err, shouldStop = streamingService.BroadcastPreVote(newTestVotingInfo("99/0/6"))
+			require.ErrorContains(t, err, "has not changed")
+			require.False(t, shouldStop)
+
+			// resume from another process
+			resumedStreamingService := pvssi.NewPreVoteStreamingService("cosmoshub-4", httpServer.URL, corecodec.WrapCvpCodecInProxy(codec), nil)
+			require.NoError(t, resumedStreamingService.ResumeSession(sessionId, sessionKey))
+
+			err, shouldStop = resumedStreamingService.BroadcastPreVote(newTestVotingInfo("100/0/6"))
+			require.NoError(t, err)
+			require.False(t, shouldStop)
+
+			// viewer
+			resp, err := http.Get(shareViewUrl)
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+			require.Contains(t, resp.Header.Get("Content-Type"), "text/html")
+
+			resp, err = http.Get(coreutils.GetUrlFetchPreVoteStreamingSessionUpdate(httpServer.URL, string(sessionId)))
+			require.NoError(t, err)
+			var update viewerUpdate
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&update))
+			_ = resp.Body.Close()
+
+			require.Equal(t, "cosmoshub-4", update.ChainId)
+			require.False(t, update.Expired)
+			require.Equal(t, "100/0/6", update.HeightRoundStep)
+			require.Equal(t, 75.0, update.PreVotePercent)
+			require.Len(t, update.Validators, 2)
+			require.Equal(t, "Val2", update.Validators[0].Moniker)
+			require.True(t, update.Validators[0].PreVoted)
+			require.Equal(t, "C0FF", strings.ToUpper(update.Validators[0].PreVotedBlockHash))
+			require.Equal(t, "Val1", update.Validators[1].Moniker)
+			require.False(t, update.Validators[1].PreVoted)
+		})
+	}
+
+	t.Run("bad session key", func(t *testing.T) {
+		streamingService := pvssi.NewPreVoteStreamingService("cosmoshub-4", httpServer.URL, nil, nil)
+		_, err := streamingService.OpenSession(testLightValidators)
+		require.NoError(t, err)
+		sessionId, _ := streamingService.ExposeSessionIdAndKey()
+
+		_, otherSessionKey, err := coretypes.NewPreVoteStreamingSession("cosmoshub-4")
+		require.NoError(t, err)
+
+		resumedStreamingService := pvssi.NewPreVoteStreamingService("cosmoshub-4", httpServer.URL, nil, nil)
+		require.ErrorContains(t, resumedStreamingService.ResumeSession(sessionId, otherSessionKey), "mis-match session key")
+	})
+
+	t.Run("session not found", func(t *testing.T) {
+		sessionId, sessionKey, err := coretypes.NewPreVoteStreamingSession("cosmoshub-4")
+		require.NoError(t, err)
+
+		streamingService := pvssi.NewPreVoteStreamingService("cosmoshub-4", httpServer.URL, nil, nil)
+		require.Error(t, streamingService.ResumeSession(sessionId, sessionKey))
+
+		resp, err := http.Get(coreutils.GetPublicUrlViewPreVoteStreamingSession(httpServer.URL, string(sessionId)))
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		require.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("session expired", func(t *testing.T) {
+		streamingService := pvssi.NewPreVoteStreamingService("cosmoshub-4", httpServer.URL, nil, nil)
+		_, err := streamingService.OpenSession(testLightValidators)
+		require.NoError(t, err)
+		sessionId, sessionKey := streamingService.ExposeSessionIdAndKey()
+
+		resumedStreamingService := pvssi.NewPreVoteStreamingService("cosmoshub-4", httpServer.URL, nil, nil)
+		require.NoError(t, resumedStreamingService.ResumeSession(sessionId, sessionKey))
+
+		now = now.Add(DefaultSessionDuration)
+		defer func() {
+			now = now.Add(-DefaultSessionDuration)
+		}()
+
+		// resumed session without known validator set cannot be renewed
+		err, shouldStop := resumedStreamingService.BroadcastPreVote(newTestVotingInfo("100/0/1"))
+		require.ErrorContains(t, err, "session timed out")
+		require.True(t, shouldStop)
+
+		// renew automatically
+		var renewedShareViewUrl, renewedReason string
+		streamingService.SetSessionRenewedHandler(func(shareViewUrl string, reason string) {
+			renewedShareViewUrl = shareViewUrl
+			renewedReason = reason
+		})
+		err, shouldStop = streamingService.BroadcastPreVote(newTestVotingInfo("100/0/1"))
+		require.NoError(t, err)
+		require.False(t, shouldStop)
+		require.Equal(t, "session expired", renewedReason)
+
+		renewedSessionId, _ := streamingService.ExposeSessionIdAndKey()
+		require.NotEqual(t, sessionId, renewedSessionId)
+		require.Equal(t, coreutils.GetPublicUrlViewPreVoteStreamingSession(httpServer.URL, string(renewedSessionId)), renewedShareViewUrl)
+
+		// pruned after retention
+		now = now.Add(expiredSessionRetention)
+		defer func() {
+			now = now.Add(-expiredSessionRetention)
+		}()
+
+		_, err = pvssi.NewPreVoteStreamingService("cosmoshub-4", httpServer.URL, nil, nil).OpenSession(testLightValidators)
+		require.NoError(t, err)
+
+		server.mutex.RLock()
+		_, found := server.sessions[sessionId]
+		server.mutex.RUnlock()
+		require.False(t, found)
+	})
+
+	t.Run("validator set changed", func(t *testing.T) {
+		streamingService := pvssi.NewPreVoteStreamingService("cosmoshub-4", httpServer.URL, nil, nil)
+		_, err := streamingService.OpenSession(testLightValidators)
+		require.NoError(t, err)
+		sessionId, _ := streamingService.ExposeSessionIdAndKey()
+
+		var renewedReason string
+		streamingService.SetSessionRenewedHandler(func(_ string, reason string) {
+			renewedReason = reason
+		})
+
+		// same validator set
+		streamingService.UpdateLightValidators(testLightValidators)
+		err, _ = streamingService.BroadcastPreVote(newTestVotingInfo("100/0/1"))
+		require.NoError(t, err)
+		require.Empty(t, renewedReason)
+
+		changedLightValidators := append(enginetypes.LightValidators{}, testLightValidators...)
+		changedLightValidators[1].Address = "CC"
+		streamingService.UpdateLightValidators(changedLightValidators)
+		err, shouldStop := streamingService.BroadcastPreVote(newTestVotingInfo("101/0/1"))
+		require.NoError(t, err)
+		require.False(t, shouldStop)
+		require.Equal(t, "validator set changed", renewedReason)
+
+		renewedSessionId, _ := streamingService.ExposeSessionIdAndKey()
+		require.NotEqual(t, sessionId, renewedSessionId)
+	})
+
+	t.Run("unsupported content", func(t *testing.T) {
+		resp, err := http.Post(
+			coreutils.GetRemoteUrlRegisterPreVoteStreamingSession(httpServer.URL, "cosmoshub-4"),
+			coreconstants.STREAMING_CONTENT_TYPE,
+			bytes.NewBufferString("not encoded by any codec"),
+		)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		require.Equal(t, http.StatusUnsupportedMediaType, resp.StatusCode)
+
+		resp, err = http.Get(coreutils.GetRemoteUrlRegisterPreVoteStreamingSession(httpServer.URL, "cosmoshub-4"))
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		require.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+	})
+}
+
+func TestStreamingServerMaxSessions(t *testing.T) {
+	server := NewStreamingServer(DefaultSessionDuration, 1)
+
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+
+	_, err := pvssi.NewPreVoteStreamingService("cosmoshub-4", httpServer.URL, nil, nil).OpenSession(testLightValidators)
+	require.NoError(t, err)
+
+	_, err = pvssi.NewPreVoteStreamingService("cosmoshub-4", httpServer.URL, nil, nil).OpenSession(testLightValidators)
+	require.ErrorContains(t, err, "slow down")
+}
+
+func TestStreamingServerTruncatedValidatorSet(t *testing.T) {
+	server := NewStreamingServer(DefaultSessionDuration, DefaultMaxSessions)
+
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+
+	const validatorsCount = coreconstants.MAX_VALIDATORS + 10
+	var lightValidators enginetypes.LightValidators
+	votingInfo := &enginetypes.NextBlockVotingInformation{
+		HeightRoundStep: "100/0/1",
+		StartTimeUTC:    time.Now().UTC(),
+	}
+	for i := 0; i < validatorsCount; i++ {
+		lightValidator := enginetypes.LightValidator{
+			Index:                     i,
+			Moniker:                   fmt.Sprintf("Val%d", i),
+			Address:                   fmt.Sprintf("%040X", i),
+			VotingPower:               1,
+			VotingPowerDisplayPercent: 100.0 / validatorsCount,
+		}
+		lightValidators = append(lightValidators, lightValidator)
+		votingInfo.SortedValidatorVoteStates = append(votingInfo.SortedValidatorVoteStates, enginetypes.ValidatorVoteState{
+			Validator: lightValidator,
+			PreVoted:  i%2 == 0,
+		})
+	}
+	votingInfo.PreVotePercent = 50
+
+	streamingService := pvssi.NewPreVoteStreamingService("cosmoshub-4", httpServer.URL, nil, nil)
+	streamingService.SetWatchlist(enginetypes.Watchlist{fmt.Sprintf("Val%d", validatorsCount-1)})
+	shareViewUrl, err := streamingService.OpenSession(lightValidators)
+	require.NoError(t, err)
+
+	err, _ = streamingService.BroadcastPreVote(votingInfo)
+	require.NoError(t, err)
+
+	resp, err := http.Get(shareViewUrl + "/update")
+	require.NoError(t, err)
+	var update viewerUpdate
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&update))
+	_ = resp.Body.Close()
+
+	require.Len(t, update.Validators, coreconstants.MAX_VALIDATORS-1)
+	require.NotNil(t, update.Truncated)
+	require.Equal(t, 11, update.Truncated.OthersCount)
+	require.Equal(t, 11*100.0/validatorsCount, update.Truncated.OthersVotingPowerPercent)
+	// not streamed: from index MAX_VALIDATORS-2 to validatorsCount-2, the even ones pre-voted
+	var othersPreVoted int
+	for i := coreconstants.MAX_VALIDATORS - 2; i <= validatorsCount-2; i++ {
+		if i%2 == 0 {
+			othersPreVoted++
+		}
+	}
+	require.Equal(t, float64(othersPreVoted)*100/validatorsCount, update.Truncated.OthersPreVotedPercent)
+	require.Zero(t, update.Truncated.OthersPreCommitVotedPercent)
+
+	var watched bool
+	for _, validator := range update.Validators {
+		if validator.Moniker == fmt.Sprintf("Val%d", validatorsCount-1) {
+			watched = true
+		}
+	}
+	require.True(t, watched, "watchlist validator must be streamed")
+
+	// votes of the others bucket changed while the encoded content did not
+	for i := range votingInfo.SortedValidatorVoteStates {
+		votingInfo.SortedValidatorVoteStates[i].PreCommitVoted = i == validatorsCount-2
+	}
+	err, _ = streamingService.BroadcastPreVote(votingInfo)
+	require.NoError(t, err)
+
+	resp, err = http.Get(shareViewUrl + "/update")
+	require.NoError(t, err)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&update))
+	_ = resp.Body.Close()
+	require.Equal(t, 100.0/validatorsCount, update.Truncated.OthersPreCommitVotedPercent)
+}
+
+func TestStreamingServerOthersBucketMonikerOfRealValidator(t *testing.T) {
+	server := NewStreamingServer(DefaultSessionDuration, DefaultMaxSessions)
+
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+
+	lightValidators := enginetypes.LightValidators{
+		{Index: 0, Moniker: "Val0", Address: fmt.Sprintf("%040X", 0), VotingPower: 2, VotingPowerDisplayPercent: 50},
+		{Index: 1, Moniker: pvss.GetOthersBucketMoniker(3), Address: fmt.Sprintf("%040X", 1), VotingPower: 2, VotingPowerDisplayPercent: 50},
+	}
+
+	streamingService := pvssi.NewPreVoteStreamingService("cosmoshub-4", httpServer.URL, nil, nil)
+	shareViewUrl, err := streamingService.OpenSession(lightValidators)
+	require.NoError(t, err)
+
+	resp, err := http.Get(shareViewUrl + "/update")
+	require.NoError(t, err)
+	var update viewerUpdate
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&update))
+	_ = resp.Body.Close()
+
+	require.Nil(t, update.Truncated, "session was not registered as truncated")
+	require.Len(t, update.Validators, 2)
+}
+
+func TestStreamingServerOthersBucketHeader(t *testing.T) {
+	server := NewStreamingServer(DefaultSessionDuration, DefaultMaxSessions)
+
+	httpServer := httptest.NewServer(server.Handler())
+	defer httpServer.Close()
+
+	body := corecodec.GetCvpCodecV3().EncodeStreamingLightValidators(coretypes.StreamingLightValidators{
+		{Index: 0, VotingPowerDisplayPercent: 60, Moniker: "Val0"},
+		{Index: 1, VotingPowerDisplayPercent: 40, Moniker: pvss.GetOthersBucketMoniker(2)},
+	})
+
+	register := func(othersBucket pvss.OthersBucket) int {
+		req, err := http.NewRequest(http.MethodPost, coreutils.GetRemoteUrlRegisterPreVoteStreamingSession(httpServer.URL, "cosmoshub-4"), bytes.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set(pvss.HeaderOthersBucket, pvss.EncodeHeader(othersBucket))
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	require.Equal(t, http.StatusCreated, register(pvss.OthersBucket{Index: 1, Count: 2, VotingPower: 4, TotalVotingPower: 10}))
+	require.Equal(t, http.StatusBadRequest, register(pvss.OthersBucket{Index: 2, Count: 2, VotingPower: 4, TotalVotingPower: 10}), "index not registered")
+	require.Equal(t, http.StatusBadRequest, register(pvss.OthersBucket{Index: 1, Count: 0, VotingPower: 4, TotalVotingPower: 10}), "empty bucket")
+	require.Equal(t, http.StatusBadRequest, register(pvss.OthersBucket{Index: 1, Count: 2, VotingPower: 11, TotalVotingPower: 10}), "exceeds total voting power")
+}
+
+func Test_isOutdated(t *testing.T) {
+	tests := []struct {
+		heightRoundStep       string
+		latestHeightRoundStep string
+		want                  bool
+	}{
+		{"100/0/1", "100/0/1", false},
+		{"100/0/2", "100/0/1", false},
+		{"100/0/1", "100/0/2", true},
+		{"100/1/1", "100/0/6", false},
+		{"100/0/6", "100/1/1", true},
+		{"101/0/1", "100/3/6", false},
+		{"99/3/6", "100/0/1", true},
+		{"bad", "100/0/1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.heightRoundStep+" vs "+tt.latestHeightRoundStep, func(t *testing.T) {
+			require.Equal(t, tt.want, isOutdated(tt.heightRoundStep, tt.latestHeightRoundStep))
+		})
+	}
+}
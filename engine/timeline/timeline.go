@@ -0,0 +1,207 @@
+package timeline
+
+//goland:noinspection SpellCheckingInspection
+import (
+	"encoding/json"
+	"fmt"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"github.com/pkg/errors"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EventType is the type of timeline event.
+type EventType string
+
+const (
+	EventTypeHeightCommitted EventType = "height_committed"
+	EventTypeNewRound        EventType = "new_round"
+	EventTypePreVote         EventType = "prevote"
+	EventTypePreCommit       EventType = "precommit"
+)
+
+// nilBlockHash is the fingerprint block hash of votes for nil.
+const nilBlockHash = "000000000000"
+
+// Event is a change detected between consecutive voting information snapshots.
+type Event struct {
+	// Time is the time of the vote if available, or the start time of the new round, otherwise the time detected.
+	Time time.Time `json:"time"`
+	Type EventType `json:"type"`
+
+	// Height and Round are of the snapshot the event detected in.
+	Height int64 `json:"height"`
+	Round  int   `json:"round"`
+
+	// Address and Moniker are of the validator, empty for height & round events.
+	Address string `json:"address,omitempty"`
+	Moniker string `json:"moniker,omitempty"`
+
+	// BlockHash is the 2 bytes fingerprint of the block hash voted for, "nil" if voted for nil.
+	BlockHash string `json:"block_hash,omitempty"`
+
+	// Offset is the duration from the start of the round to the vote.
+	Offset time.Duration `json:"offset_ns,omitempty"`
+
+	// Message is the human-readable description of the event.
+	Message string `json:"message"`
+}
+
+// snapshot is the state of the latest voting information, to be compared with the next one.
+type snapshot struct {
+	height     int64
+	round      int
+	voteStates map[string]enginetypes.ValidatorVoteState
+}
+
+// Timeline diffs consecutive voting information snapshots into timestamped events,
+// keeping the most recent events for rendering and exporting.
+//
+// Timeline is not safe for concurrent use.
+type Timeline struct {
+	maxEvents int
+	events    []Event
+	previous  *snapshot
+}
+
+// NewTimeline returns a new Timeline keeping at most maxEvents recent events.
+func NewTimeline(maxEvents int) *Timeline {
+	if maxEvents < 1 {
+		panic("max events must be positive")
+	}
+
+	return &Timeline{
+		maxEvents: maxEvents,
+	}
+}
+
+// Update compares the voting information with the previous one and returns the new events, ordered by time.
+// The first voting information is used as the baseline, no event is returned.
+func (t *Timeline) Update(votingInfo *enginetypes.NextBlockVotingInformation, now time.Time) []Event {
+	height, round, _, err := enginetypes.ParseHeightRoundStep(votingInfo.HeightRoundStep)
+	if err != nil {
+		return nil
+	}
+
+	current := &snapshot{
+		height:     height,
+		round:      round,
+		voteStates: make(map[string]enginetypes.ValidatorVoteState, len(votingInfo.SortedValidatorVoteStates)),
+	}
+	for _, voteState := range votingInfo.SortedValidatorVoteStates {
+		current.voteStates[voteState.Validator.Address] = voteState
+	}
+
+	previous := t.previous
+	t.previous = current
+	if previous == nil || height < previous.height {
+		return nil // baseline, or node switched
+	}
+
+	roundStartTime := votingInfo.StartTimeUTC
+	if roundStartTime.IsZero() {
+		roundStartTime = now
+	}
+
+	var events []Event
+	previousVoteStates := previous.voteStates
+
+	if height > previous.height {
+		message := fmt.Sprintf("height %d committed", height-1)
+		if height-previous.height > 1 {
+			message = fmt.Sprintf("heights %d-%d committed", previous.height, height-1)
+		}
+		events = append(events, Event{
+			Time:    roundStartTime,
+			Type:    EventTypeHeightCommitted,
+			Height:  height,
+			Round:   round,
+			Message: message,
+		})
+		previousVoteStates = nil // votes of the new height
+	} else if round != previous.round {
+		events = append(events, Event{
+			Time:    roundStartTime,
+			Type:    EventTypeNewRound,
+			Height:  height,
+			Round:   round,
+			Message: fmt.Sprintf("round %d→%d", previous.round, round),
+		})
+		previousVoteStates = nil // votes of the new round
+	}
+
+	voteEvent := func(eventType EventType, voteState enginetypes.ValidatorVoteState, blockHash string, voteTime time.Time) Event {
+		if voteTime.IsZero() {
+			voteTime = now
+		}
+
+		if blockHash == nilBlockHash {
+			blockHash = "nil"
+		} else if len(blockHash) >= 4 {
+			blockHash = blockHash[:4]
+		}
+
+		verb := "prevoted"
+		if eventType == EventTypePreCommit {
+			verb = "precommitted"
+		}
+
+		offset := voteTime.Sub(roundStartTime)
+		messageParts := []string{voteState.Validator.Moniker, verb}
+		if len(blockHash) > 0 { // might not be available, eg: parsing the raw vote failed
+			messageParts = append(messageParts, blockHash)
+		}
+		messageParts = append(messageParts, fmt.Sprintf("at %+.1fs", offset.Seconds()))
+		return Event{
+			Time:      voteTime,
+			Type:      eventType,
+			Height:    height,
+			Round:     round,
+			Address:   voteState.Validator.Address,
+			Moniker:   voteState.Validator.Moniker,
+			BlockHash: blockHash,
+			Offset:    offset,
+			Message:   strings.Join(messageParts, " "),
+		}
+	}
+
+	for _, voteState := range votingInfo.SortedValidatorVoteStates {
+		previousVoteState := previousVoteStates[voteState.Validator.Address]
+
+		if voteState.PreVoted && !previousVoteState.PreVoted {
+			events = append(events, voteEvent(EventTypePreVote, voteState, voteState.VotingBlockHash, voteState.PreVoteTime))
+		}
+		if voteState.PreCommitVoted && !previousVoteState.PreCommitVoted {
+			events = append(events, voteEvent(EventTypePreCommit, voteState, voteState.PreCommitBlockHash, voteState.PreCommitTime))
+		}
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time.Before(events[j].Time)
+	})
+
+	t.events = append(t.events, events...)
+	if exceeded := len(t.events) - t.maxEvents; exceeded > 0 {
+		t.events = append(t.events[:0], t.events[exceeded:]...)
+	}
+
+	return events
+}
+
+// Events returns the recent events, ordered by the time detected.
+func (t *Timeline) Events() []Event {
+	return append([]Event{}, t.events...)
+}
+
+// Export writes the recent events as JSON lines.
+func (t *Timeline) Export(writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	for _, event := range t.events {
+		if err := encoder.Encode(event); err != nil {
+			return errors.Wrap(err, "failed to encode event")
+		}
+	}
+	return nil
+}
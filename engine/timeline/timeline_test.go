@@ -0,0 +1,116 @@
+package timeline
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	enginetypes "github.com/bcdevtools/consvp/engine/types"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestTimeline(t *testing.T) {
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	validators := make([]enginetypes.LightValidator, 3)
+	for i := range validators {
+		validators[i] = enginetypes.LightValidator{
+			Index:   i,
+			Moniker: fmt.Sprintf("val%d", i),
+			Address: fmt.Sprintf("%040X", i),
+		}
+	}
+
+	information := func(heightRoundStep string, roundStartTime time.Time, voteStates ...enginetypes.ValidatorVoteState) *enginetypes.NextBlockVotingInformation {
+		return &enginetypes.NextBlockVotingInformation{
+			SortedValidatorVoteStates: voteStates,
+			HeightRoundStep:           heightRoundStep,
+			StartTimeUTC:              roundStartTime,
+		}
+	}
+	missing := func(index int) enginetypes.ValidatorVoteState {
+		return enginetypes.ValidatorVoteState{Validator: validators[index]}
+	}
+	preVoted := func(index int, blockHash string, preVoteTime time.Time) enginetypes.ValidatorVoteState {
+		voteState := missing(index)
+		voteState.PreVoted = true
+		voteState.VotingBlockHash = blockHash
+		voteState.VotedZeroes = blockHash == nilBlockHash
+		voteState.PreVoteTime = preVoteTime
+		return voteState
+	}
+	messagesOf := func(events []Event) []string {
+		var messages []string
+		for _, event := range events {
+			messages = append(messages, event.Message)
+		}
+		return messages
+	}
+
+	timeline := NewTimeline(5)
+
+	// baseline
+	require.Empty(t, timeline.Update(information("100/0/1", startTime, preVoted(0, "BEEF00000000", startTime.Add(time.Second)), missing(1), missing(2)), startTime.Add(time.Second)))
+
+	// votes ordered by time
+	precommitted := preVoted(0, "BEEF00000000", startTime.Add(time.Second))
+	precommitted.PreCommitVoted = true
+	precommitted.PreCommitTime = startTime.Add(2500 * time.Millisecond) // block hash not available
+	events := timeline.Update(information("100/0/6", startTime,
+		precommitted,
+		preVoted(1, nilBlockHash, startTime.Add(2100*time.Millisecond)),
+		preVoted(2, "C0FFEE000000", time.Time{}), // time not available, use the detected time
+	), startTime.Add(3*time.Second))
+	require.Equal(t, []string{
+		"val1 prevoted nil at +2.1s",
+		"val0 precommitted at +2.5s",
+		"val2 prevoted C0FF at +3.0s",
+	}, messagesOf(events))
+	require.Equal(t, EventTypePreVote, events[0].Type)
+	require.Equal(t, validators[1].Address, events[0].Address)
+	require.Equal(t, "nil", events[0].BlockHash)
+	require.Equal(t, 2100*time.Millisecond, events[0].Offset)
+	require.Equal(t, EventTypePreCommit, events[1].Type)
+
+	// new round, votes already present are reported
+	roundStartTime := startTime.Add(10 * time.Second)
+	events = timeline.Update(information("100/1/1", roundStartTime, preVoted(0, "BEEF00000000", roundStartTime.Add(time.Second)), missing(1), missing(2)), roundStartTime.Add(2*time.Second))
+	require.Equal(t, []string{"round 0→1", "val0 prevoted BEEF at +1.0s"}, messagesOf(events))
+	require.Equal(t, EventTypeNewRound, events[0].Type)
+	require.Equal(t, 1, events[0].Round)
+
+	// new height
+	events = timeline.Update(information("101/0/1", roundStartTime.Add(5*time.Second), missing(0), missing(1), missing(2)), roundStartTime.Add(6*time.Second))
+	require.Equal(t, []string{"height 100 committed"}, messagesOf(events))
+	require.Equal(t, EventTypeHeightCommitted, events[0].Type)
+	require.Equal(t, int64(101), events[0].Height)
+
+	events = timeline.Update(information("103/0/1", roundStartTime.Add(8*time.Second), missing(0), missing(1), missing(2)), roundStartTime.Add(9*time.Second))
+	require.Equal(t, []string{"heights 101-102 committed"}, messagesOf(events))
+
+	// lower height is treated as a new baseline
+	require.Empty(t, timeline.Update(information("90/0/1", startTime, preVoted(0, "BEEF00000000", startTime)), startTime))
+	require.Empty(t, timeline.Update(information("bad", startTime), startTime))
+
+	// keep the most recent events only
+	require.Equal(t, []string{
+		"val2 prevoted C0FF at +3.0s",
+		"round 0→1",
+		"val0 prevoted BEEF at +1.0s",
+		"height 100 committed",
+		"heights 101-102 committed",
+	}, messagesOf(timeline.Events()))
+
+	var buffer bytes.Buffer
+	require.NoError(t, timeline.Export(&buffer))
+	scanner := bufio.NewScanner(&buffer)
+	var exported []Event
+	for scanner.Scan() {
+		var event Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		exported = append(exported, event)
+	}
+	require.Equal(t, timeline.Events(), exported)
+}
@@ -39,5 +39,19 @@ func (re *BaseAbciQueryResponse) GetError() error {
 		return nil
 	}
 
-	return fmt.Errorf("code %d: %s", re.Result.Response.Code, re.Result.Response.Log)
+	return &AbciQueryError{
+		Code: re.Result.Response.Code,
+		Log:  re.Result.Response.Log,
+	}
+}
+
+// AbciQueryError is returned when the ABCI query was processed but responded with non-zero code,
+// eg: unknown query path because the module is not available on the chain. Retrying does not help.
+type AbciQueryError struct {
+	Code uint32
+	Log  string
+}
+
+func (e *AbciQueryError) Error() string {
+	return fmt.Sprintf("code %d: %s", e.Code, e.Log)
 }
@@ -1,6 +1,9 @@
 package types
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // LightValidator is a light version of Validator, it contains minimal information needed for application business logic.
 type LightValidator struct {
@@ -10,6 +13,49 @@ type LightValidator struct {
 	PubKey                    string
 	VotingPower               int64
 	VotingPowerDisplayPercent float64 // the value is rounded so only use for display purpose
+
+	// SigningInfo is the signing information from the slashing module, nil if not available.
+	SigningInfo *LightValidatorSigningInfo
+
+	// Details is the staking information of the validator, only used for display purpose, nil if not available.
+	Details *LightValidatorDetails
+}
+
+// LightValidatorDetails is the staking information of the validator from the staking module.
+type LightValidatorDetails struct {
+	OperatorAddress  string // bech32 validator operator address
+	ConsensusAddress string // bech32 validator consensus address, empty if could not be derived
+	Tokens           string // bonded tokens, in base denom
+
+	CommissionRate          float64
+	CommissionMaxRate       float64
+	CommissionMaxChangeRate float64
+}
+
+// downtimeJailWarningThresholdPercent is the percent of max missed blocks, reaching it means close to downtime jail.
+const downtimeJailWarningThresholdPercent = 80
+
+// LightValidatorSigningInfo is a light version of the signing information from the slashing module.
+type LightValidatorSigningInfo struct {
+	MissedBlocksCounter int64
+	// MaxMissedBlocks is the maximum number of blocks can be missed within the signed blocks window,
+	// missing more than this will cause the validator to be jailed for downtime. Zero if unknown.
+	MaxMissedBlocks int64
+	JailedUntil     time.Time
+	Tombstoned      bool
+}
+
+// IsCloseToDowntimeJail returns true if the missed blocks counter is close to the downtime jail threshold.
+func (si LightValidatorSigningInfo) IsCloseToDowntimeJail() bool {
+	if si.MaxMissedBlocks < 1 {
+		return false
+	}
+	return si.MissedBlocksCounter*100 >= si.MaxMissedBlocks*downtimeJailWarningThresholdPercent
+}
+
+// IsJailed returns true if the validator is still jailed at the given time.
+func (si LightValidatorSigningInfo) IsJailed(now time.Time) bool {
+	return si.JailedUntil.After(now)
 }
 
 // GetFingerPrintAddress returns the first 6 bytes of the address.
@@ -35,6 +81,27 @@ func (lvs LightValidators) TotalVotingPower() uint64 {
 	return sumVotingPower
 }
 
+// IsSameValidatorSet returns true if both lists contain the same validators at the same indexes.
+// Other information like voting power or signing information are not compared.
+func (lvs LightValidators) IsSameValidatorSet(other LightValidators) bool {
+	if len(lvs) != len(other) {
+		return false
+	}
+
+	addressesByIndex := make(map[int]string, len(lvs))
+	for _, lv := range lvs {
+		addressesByIndex[lv.Index] = lv.Address
+	}
+
+	for _, lv := range other {
+		if address, found := addressesByIndex[lv.Index]; !found || address != lv.Address {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (lvs LightValidators) GetLightValidatorByIndex(index int) LightValidator {
 	for _, lv := range lvs {
 		if lv.Index == index {
@@ -8,4 +8,12 @@ type NextBlockVotingInformation struct {
 	PreCommitPercent          float64
 	HeightRoundStep           string
 	StartTimeUTC              time.Time
+
+	// CapturedAtUTC is the time the consensus state was fetched, or the recorded time when replaying.
+	// Zero if unknown.
+	CapturedAtUTC time.Time
+
+	// SignedBlocksWindow is the signing status of validators over the most recent committed blocks.
+	// Nil when not requested.
+	SignedBlocksWindow *SignedBlocksWindow
 }
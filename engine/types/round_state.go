@@ -73,3 +73,32 @@ type RoundVotes struct {
 	PreCommits         []string `json:"precommits"`
 	PreCommitsBitArray string   `json:"precommits_bit_array"`
 }
+
+// ParseHeightRoundStep parses the 'height/round/step' string of the consensus state.
+func ParseHeightRoundStep(heightRoundStep string) (height int64, round int, step int, err error) {
+	spl := strings.Split(heightRoundStep, "/")
+	if len(spl) != 3 {
+		err = fmt.Errorf("invalid height/round/step format %s", heightRoundStep)
+		return
+	}
+
+	height, err = strconv.ParseInt(spl[0], 10, 64)
+	if err != nil {
+		err = errors.Wrap(err, fmt.Sprintf("failed to parse height %s", spl[0]))
+		return
+	}
+
+	round, err = strconv.Atoi(spl[1])
+	if err != nil {
+		err = errors.Wrap(err, fmt.Sprintf("failed to parse round %s", spl[1]))
+		return
+	}
+
+	step, err = strconv.Atoi(spl[2])
+	if err != nil {
+		err = errors.Wrap(err, fmt.Sprintf("failed to parse step %s", spl[2]))
+		return
+	}
+
+	return
+}
@@ -0,0 +1,38 @@
+package types
+
+// SignedBlocksWindow holds the signing status of validators over the most recent committed blocks.
+type SignedBlocksWindow struct {
+	FromHeight int64
+	ToHeight   int64
+
+	// SignedBlocks maps validator address to the signing status of each height within the window,
+	// ordered ascending by height.
+	SignedBlocks map[string][]bool
+}
+
+// Size returns number of blocks within the window.
+func (w SignedBlocksWindow) Size() int {
+	if w.ToHeight < w.FromHeight {
+		return 0
+	}
+	return int(w.ToHeight - w.FromHeight + 1)
+}
+
+// GetSignedBlocks returns the signing status of the validator over the window, ordered ascending by height.
+func (w SignedBlocksWindow) GetSignedBlocks(address string) []bool {
+	if signedBlocks, found := w.SignedBlocks[address]; found {
+		return signedBlocks
+	}
+	return make([]bool, w.Size())
+}
+
+// CountSignedBlocks returns number of blocks signed by the validator within the window.
+func (w SignedBlocksWindow) CountSignedBlocks(address string) int {
+	var count int
+	for _, signed := range w.GetSignedBlocks(address) {
+		if signed {
+			count++
+		}
+	}
+	return count
+}
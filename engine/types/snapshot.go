@@ -0,0 +1,62 @@
+package types
+
+import (
+	"github.com/bcdevtools/consvp/schema"
+	"github.com/pkg/errors"
+	"time"
+)
+
+// ToSnapshotV1 converts the voting information into schema.SnapshotV1, captured at the given time.
+// Returns error if the height/round/step is malformed, so no snapshot with zero height is ever emitted.
+func (nbvi NextBlockVotingInformation) ToSnapshotV1(capturedAt time.Time) (schema.SnapshotV1, error) {
+	height, round, step, err := ParseHeightRoundStep(nbvi.HeightRoundStep)
+	if err != nil {
+		return schema.SnapshotV1{}, errors.Wrap(err, "malformed height/round/step")
+	}
+
+	snapshot := schema.SnapshotV1{
+		Schema:           schema.SchemaSnapshotV1,
+		Time:             capturedAt.UTC(),
+		Height:           height,
+		Round:            round,
+		Step:             step,
+		StartTime:        nbvi.StartTimeUTC,
+		PreVotePercent:   nbvi.PreVotePercent,
+		PreCommitPercent: nbvi.PreCommitPercent,
+		Validators:       make([]schema.ValidatorSnapshotV1, 0, len(nbvi.SortedValidatorVoteStates)),
+	}
+
+	for _, voteState := range nbvi.SortedValidatorVoteStates {
+		validatorSnapshot := schema.ValidatorSnapshotV1{
+			Index:              voteState.Validator.Index,
+			Address:            voteState.Validator.Address,
+			Moniker:            voteState.Validator.Moniker,
+			VotingPower:        voteState.Validator.VotingPower,
+			VotingPowerPercent: voteState.Validator.VotingPowerDisplayPercent,
+			PreVote:            schema.VoteStateV1Missing,
+			PreCommit:          schema.VoteStateV1Missing,
+		}
+
+		if voteState.VotedZeroes {
+			validatorSnapshot.PreVote = schema.VoteStateV1VotedZeroes
+		} else if voteState.PreVoted {
+			validatorSnapshot.PreVote = schema.VoteStateV1Voted
+		}
+		if voteState.PreVoted {
+			validatorSnapshot.PreVoteBlockHash = voteState.VotingBlockHash
+		}
+
+		if voteState.PreCommitVotedZeroes() {
+			validatorSnapshot.PreCommit = schema.VoteStateV1VotedZeroes
+		} else if voteState.PreCommitVoted {
+			validatorSnapshot.PreCommit = schema.VoteStateV1Voted
+		}
+		if voteState.PreCommitVoted {
+			validatorSnapshot.PreCommitBlockHash = voteState.PreCommitBlockHash
+		}
+
+		snapshot.Validators = append(snapshot.Validators, validatorSnapshot)
+	}
+
+	return snapshot, nil
+}
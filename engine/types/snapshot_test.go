@@ -0,0 +1,80 @@
+package types
+
+import (
+	"github.com/bcdevtools/consvp/schema"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestNextBlockVotingInformation_ToSnapshotV1(t *testing.T) {
+	capturedAt := time.Date(2023, 12, 25, 3, 0, 5, 0, time.UTC)
+
+	//goland:noinspection SpellCheckingInspection
+	votingInfo := NextBlockVotingInformation{
+		HeightRoundStep: "100/2/6",
+		StartTimeUTC:    capturedAt.Add(-2 * time.Second),
+		SortedValidatorVoteStates: []ValidatorVoteState{
+			{
+				Validator:          LightValidator{Index: 0, Address: "AA", Moniker: "Val1"},
+				VotingBlockHash:    "C0FFEE000000",
+				PreVoted:           true,
+				PreCommitVoted:     true,
+				PreCommitBlockHash: "C0FFEE000000",
+			},
+			{
+				Validator:          LightValidator{Index: 1, Address: "BB", Moniker: "Val2"},
+				VotingBlockHash:    "000000000000",
+				PreVoted:           true,
+				VotedZeroes:        true,
+				PreCommitVoted:     true,
+				PreCommitBlockHash: "000000000000",
+			},
+			{
+				Validator:       LightValidator{Index: 2, Address: "CC", Moniker: "Val3"},
+				VotingBlockHash: "C0FFEE000000",
+				PreVoted:        true,
+				PreCommitVoted:  true, // block hash not available
+			},
+			{
+				Validator: LightValidator{Index: 3, Address: "DD", Moniker: "Val4"},
+			},
+		},
+	}
+
+	snapshot, err := votingInfo.ToSnapshotV1(capturedAt)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), snapshot.Height)
+	require.Len(t, snapshot.Validators, 4)
+
+	type voteStates struct {
+		preVote            schema.VoteStateV1
+		preVoteBlockHash   string
+		preCommit          schema.VoteStateV1
+		preCommitBlockHash string
+	}
+	var got []voteStates
+	for _, validator := range snapshot.Validators {
+		got = append(got, voteStates{
+			preVote:            validator.PreVote,
+			preVoteBlockHash:   validator.PreVoteBlockHash,
+			preCommit:          validator.PreCommit,
+			preCommitBlockHash: validator.PreCommitBlockHash,
+		})
+	}
+
+	//goland:noinspection SpellCheckingInspection
+	require.Equal(t, []voteStates{
+		{schema.VoteStateV1Voted, "C0FFEE000000", schema.VoteStateV1Voted, "C0FFEE000000"},
+		{schema.VoteStateV1VotedZeroes, "000000000000", schema.VoteStateV1VotedZeroes, "000000000000"},
+		{schema.VoteStateV1Voted, "C0FFEE000000", schema.VoteStateV1Voted, ""},
+		{schema.VoteStateV1Missing, "", schema.VoteStateV1Missing, ""},
+	}, got)
+}
+
+func TestNextBlockVotingInformation_ToSnapshotV1_malformedHeightRoundStep(t *testing.T) {
+	for _, heightRoundStep := range []string{"", "bad", "100/2"} {
+		_, err := NextBlockVotingInformation{HeightRoundStep: heightRoundStep}.ToSnapshotV1(time.Now())
+		require.Error(t, err, heightRoundStep)
+	}
+}
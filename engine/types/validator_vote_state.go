@@ -1,6 +1,7 @@
 package types
 
 //goland:noinspection SpellCheckingInspection
+import "time"
 
 type ValidatorVoteState struct {
 	Validator       LightValidator
@@ -8,4 +9,23 @@ type ValidatorVoteState struct {
 	PreVoted        bool
 	VotedZeroes     bool
 	PreCommitVoted  bool
+
+	// PreVoteTime is the timestamp of the pre-vote, zero if not pre-voted or not available.
+	PreVoteTime time.Time
+
+	// PreCommitBlockHash is the 6 bytes fingerprint of hash of the block that the validator pre-committed for,
+	// zeroes when pre-committed nil, empty if not pre-committed.
+	PreCommitBlockHash string
+
+	// PreCommitTime is the timestamp of the pre-commit, zero if not pre-committed or not available.
+	PreCommitTime time.Time
+
+	// PreVote and PreCommit are the raw votes of the current round as returned by RPC, eg: 'nil-Vote' if not voted.
+	PreVote   string
+	PreCommit string
+}
+
+// PreCommitVotedZeroes returns true if the validator pre-committed nil (zeroes block hash).
+func (s ValidatorVoteState) PreCommitVotedZeroes() bool {
+	return s.PreCommitVoted && s.PreCommitBlockHash == "000000000000"
 }
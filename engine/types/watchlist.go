@@ -0,0 +1,20 @@
+package types
+
+import "strings"
+
+// Watchlist is a list of validators to be watched, each entry is either a consensus address or a moniker.
+type Watchlist []string
+
+// Contains returns true if the given validator is in the watchlist, matching either the consensus address or the moniker, case-insensitive.
+func (w Watchlist) Contains(lv LightValidator) bool {
+	for _, entry := range w {
+		entry = strings.TrimSpace(entry)
+		if len(entry) < 1 {
+			continue
+		}
+		if strings.EqualFold(entry, lv.Address) || strings.EqualFold(entry, strings.TrimSpace(lv.Moniker)) {
+			return true
+		}
+	}
+	return false
+}
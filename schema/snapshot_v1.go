@@ -0,0 +1,73 @@
+// Package schema defines the stable, versioned data schemas of the machine-readable outputs,
+// so they can be imported by tooling consuming the outputs.
+package schema
+
+import "time"
+
+// SchemaSnapshotV1 is the value of SnapshotV1.Schema field.
+//
+//goland:noinspection GoNameStartsWithPackageName
+const SchemaSnapshotV1 = "cvp/snapshot/v1"
+
+// SnapshotV1 is the version 1 schema of a snapshot of the voting information of the next block.
+//
+// In JSON Lines output mode, each line is a SnapshotV1 object.
+// New fields might be added in the same version, existing fields will never be removed or changed.
+type SnapshotV1 struct {
+	// Schema is always SchemaSnapshotV1.
+	Schema string `json:"schema"`
+
+	// Time is the time when the snapshot was captured.
+	Time time.Time `json:"time"`
+
+	Height int64 `json:"height"`
+	Round  int   `json:"round"`
+	Step   int   `json:"step"`
+
+	// StartTime is the start time of the current round.
+	StartTime time.Time `json:"start_time"`
+
+	PreVotePercent   float64 `json:"prevote_percent"`
+	PreCommitPercent float64 `json:"precommit_percent"`
+
+	// Validators is sorted descending by voting power.
+	Validators []ValidatorSnapshotV1 `json:"validators"`
+}
+
+// ValidatorSnapshotV1 is the version 1 schema of the vote state of a validator.
+type ValidatorSnapshotV1 struct {
+	// Index is the index of the validator in the validator set.
+	Index int `json:"index"`
+
+	// Address is the upper-case hex consensus address.
+	Address string `json:"address"`
+
+	Moniker string `json:"moniker"`
+
+	VotingPower        int64   `json:"voting_power"`
+	VotingPowerPercent float64 `json:"voting_power_percent"`
+
+	PreVote   VoteStateV1 `json:"prevote"`
+	PreCommit VoteStateV1 `json:"precommit"`
+
+	// PreVoteBlockHash is the fingerprint (first 6 bytes, upper-case hex) of the block hash which the validator pre-voted on.
+	// Empty if not pre-voted.
+	PreVoteBlockHash string `json:"prevote_block_hash,omitempty"`
+
+	// PreCommitBlockHash is the fingerprint (first 6 bytes, upper-case hex) of the block hash which the validator pre-committed on.
+	// Empty if not pre-committed, or not available.
+	PreCommitBlockHash string `json:"precommit_block_hash,omitempty"`
+}
+
+// VoteStateV1 is the state of a vote.
+type VoteStateV1 string
+
+//goland:noinspection GoUnusedConst
+const (
+	// VoteStateV1Voted means voted for a block.
+	VoteStateV1Voted VoteStateV1 = "voted"
+	// VoteStateV1VotedZeroes means voted for nil block (zero block hash).
+	VoteStateV1VotedZeroes VoteStateV1 = "voted_zeroes"
+	// VoteStateV1Missing means not voted yet.
+	VoteStateV1Missing VoteStateV1 = "missing"
+)
@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"errors"
+	"net/url"
 	"strings"
 )
 
@@ -25,3 +27,45 @@ func ReplaceAnySchemeWithHttp(endpoint string) string {
 	}
 	return endpoint
 }
+
+// RedactUrl returns only the scheme and host of the URL,
+// path, query and user info may contain secrets (eg: token of webhook) so they must not be printed.
+func RedactUrl(rawUrl string) string {
+	parsedUrl, err := url.Parse(rawUrl)
+	if err != nil || len(parsedUrl.Host) < 1 {
+		return "<redacted>"
+	}
+	return parsedUrl.Scheme + "://" + parsedUrl.Host
+}
+
+// RedactUrlError redacts the URL of the error returned by http.Client, which contains the full request URL.
+func RedactUrlError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return &url.Error{
+			Op:  urlErr.Op,
+			URL: RedactUrl(urlErr.URL),
+			Err: urlErr.Err,
+		}
+	}
+	return err
+}
+
+// HttpResponseError is returned when the server responded with an un-expected HTTP status code.
+type HttpResponseError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *HttpResponseError) Error() string {
+	return e.Message
+}
+
+// GetHttpResponseStatusCode returns the HTTP status code if the given error is, or wraps, a HttpResponseError.
+func GetHttpResponseStatusCode(err error) (statusCode int, ok bool) {
+	var responseError *HttpResponseError
+	if errors.As(err, &responseError) {
+		return responseError.StatusCode, true
+	}
+	return 0, false
+}
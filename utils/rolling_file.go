@@ -0,0 +1,149 @@
+package utils
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"os"
+	"sync"
+)
+
+// RollingFile is an append-only file which is rolled when exceeds the max size:
+// file.(n-1) is renamed to file.n, ..., file to file.1, files beyond the max backups are removed.
+//
+// RollingFile is safe for concurrent use.
+type RollingFile struct {
+	mutex *sync.Mutex
+
+	filePath   string
+	perm       os.FileMode
+	maxSize    int64
+	maxBackups int
+
+	file   *os.File
+	size   int64
+	closed bool
+}
+
+// NewRollingFile opens the file for appending, creating it with the given permission if not exists.
+// The permission also applies to the new files created when rolling.
+func NewRollingFile(filePath string, perm os.FileMode, maxSize int64, maxBackups int) (*RollingFile, error) {
+	if len(filePath) < 1 {
+		return nil, fmt.Errorf("file path is required")
+	}
+	if maxSize < 1 {
+		return nil, fmt.Errorf("max size must be positive")
+	}
+	if maxBackups < 1 {
+		return nil, fmt.Errorf("max backups must be positive")
+	}
+
+	f := &RollingFile{
+		mutex:      &sync.Mutex{},
+		filePath:   filePath,
+		perm:       perm,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+
+	if err := f.openFile(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Write appends the data into the file, the file is rolled before writing if the data does not fit into the current file.
+// Returns os.ErrClosed if the file has been closed.
+func (f *RollingFile) Write(bz []byte) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.closed {
+		return 0, os.ErrClosed
+	}
+
+	if f.file == nil { // previous rolling failed
+		if err := f.openFile(); err != nil {
+			return 0, err
+		}
+	}
+
+	if f.size > 0 && f.size+int64(len(bz)) > f.maxSize {
+		if err := f.roll(); err != nil {
+			return 0, errors.Wrap(err, "failed to roll file")
+		}
+	}
+
+	n, err := f.file.Write(bz)
+	f.size += int64(n)
+	if err != nil {
+		return n, errors.Wrap(err, "failed to write file")
+	}
+
+	return n, nil
+}
+
+// Size returns the size of the current file, and the max size before rolled.
+func (f *RollingFile) Size() (size, maxSize int64) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	return f.size, f.maxSize
+}
+
+// Close closes the file, any further writing will fail.
+func (f *RollingFile) Close() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.closed = true
+	if f.file == nil {
+		return nil
+	}
+
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+// openFile opens the file for appending, creating it if not exists.
+func (f *RollingFile) openFile() error {
+	file, err := os.OpenFile(f.filePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, f.perm)
+	if err != nil {
+		return errors.Wrap(err, "failed to open file")
+	}
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return errors.Wrap(err, "failed to stat file")
+	}
+
+	f.file = file
+	f.size = fileInfo.Size()
+	return nil
+}
+
+// roll closes the current file, shifts the backups and opens a new file.
+func (f *RollingFile) roll() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	f.file = nil
+
+	_ = os.Remove(f.backupFilePath(f.maxBackups))
+	for i := f.maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(f.backupFilePath(i), f.backupFilePath(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(f.filePath, f.backupFilePath(1)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return f.openFile()
+}
+
+func (f *RollingFile) backupFilePath(n int) string {
+	return fmt.Sprintf("%s.%d", f.filePath, n)
+}
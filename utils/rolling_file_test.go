@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRollingFile(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "cvp.log")
+
+	file, err := NewRollingFile(filePath, 0o600, 10, 2)
+	require.NoError(t, err)
+	for _, line := range []string{"1111\n", "2222\n", "3333\n", "4444\n", "5555\n", "6666\n", "7777\n"} {
+		_, err = file.Write([]byte(line))
+		require.NoError(t, err)
+	}
+	size, maxSize := file.Size()
+	require.Equal(t, int64(5), size)
+	require.Equal(t, int64(10), maxSize)
+	require.NoError(t, file.Close())
+
+	readFile := func(filePath string) string {
+		bz, err := os.ReadFile(filePath)
+		require.NoError(t, err)
+		return string(bz)
+	}
+	require.Equal(t, "7777\n", readFile(filePath))
+	require.Equal(t, "5555\n6666\n", readFile(filePath+".1"))
+	require.Equal(t, "3333\n4444\n", readFile(filePath+".2"))
+	require.NoFileExists(t, filePath+".3")
+	for _, path := range []string{filePath, filePath + ".1", filePath + ".2"} {
+		fileInfo, err := os.Stat(path)
+		require.NoError(t, err)
+		require.Equal(t, os.FileMode(0o600), fileInfo.Mode().Perm())
+	}
+
+	_, err = file.Write([]byte("8888\n"))
+	require.ErrorIs(t, err, os.ErrClosed)
+
+	file, err = NewRollingFile(filePath, 0o600, 10, 2)
+	require.NoError(t, err)
+	size, _ = file.Size()
+	require.Equal(t, int64(5), size, "must append to the existing file")
+	require.NoError(t, file.Close())
+
+	_, err = NewRollingFile(filePath, 0o600, 0, 2)
+	require.Error(t, err)
+}
@@ -2,6 +2,8 @@ package utils
 
 import (
 	"fmt"
+	"io"
+	"strings"
 	"sync"
 	"time"
 )
@@ -11,18 +13,89 @@ type IStdHelper interface {
 	// Println prints a message to stdout or queue message if queue is enabled.
 	Println(message any)
 
-	// PrintlnStdErr prints a message to stderr or queue message if queue is enabled.
+	// PrintlnStdErr prints a message to stderr or queue message if queue is enabled, the message is logged as an error.
 	PrintlnStdErr(message any)
 
+	// PrintlnWarn prints a warning message, prefixed by "WARN:", followed by the error if not nil, to stderr
+	// or queue message if queue is enabled. The message and the error are logged as a single warning.
+	PrintlnWarn(message any, err error)
+
+	// PrintlnErr prints an error message, prefixed by "ERR:", followed by the error if not nil, to stderr
+	// or queue message if queue is enabled. The message and the error are logged as a single error.
+	PrintlnErr(message any, err error)
+
+	// PrintlnInfoStdErr prints an informational message to stderr or queue message if queue is enabled,
+	// to keep stdout machine-readable. Unlike PrintlnStdErr, the message is not considered as an error.
+	PrintlnInfoStdErr(message any)
+
 	// EnableQueue enables queue.
 	EnableQueue()
 
 	// PrintQueuedMessages prints queued messages.
 	PrintQueuedMessages()
+
+	// SubscribeLogs returns a channel receiving every message printed after subscribed, with severity level and time.
+	// Messages are dropped if the channel is full.
+	SubscribeLogs() <-chan LogEntry
+
+	// SetLogWriter mirrors every message, with time and severity level, into the writer. Nil to disable.
+	SetLogWriter(writer io.Writer)
+}
+
+// LogLevel is the severity level of a message printed via IStdHelper.
+type LogLevel int
+
+const (
+	LogLevelInfo LogLevel = iota
+	LogLevelWarn
+	LogLevelError
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LogLevelInfo:
+		return "INFO"
+	case LogLevelWarn:
+		return "WARN"
+	case LogLevelError:
+		return "ERROR"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// LogEntry is a message printed via IStdHelper.
+type LogEntry struct {
+	Time    time.Time
+	Level   LogLevel
+	Message string
+}
+
+// String returns the log entry as a single line, to be written into log file.
+func (e LogEntry) String() string {
+	return fmt.Sprintf("%s %-5s %s", e.Time.Format("2006-01-02T15:04:05.000Z07:00"), e.Level, e.Message)
+}
+
+// newLogEntry returns the log entry of the message, the error is appended to the message if not nil.
+func newLogEntry(now time.Time, level LogLevel, message any, err error) LogEntry {
+	msg := strings.TrimSpace(fmt.Sprint(message))
+	if err != nil {
+		msg = fmt.Sprintf("%s: %v", msg, err)
+	}
+
+	return LogEntry{
+		Time:    now,
+		Level:   level,
+		Message: msg,
+	}
 }
 
+// logsSubscriptionBufferSize is the capacity of the channels returned by SubscribeLogs.
+const logsSubscriptionBufferSize = 100
+
 // StdHelper implements IStdHelper, to be used to queue messages during T-UI active, then print them out when T-UI is closed.
 // This is to work around the issue that T-UI will clear the screen and error messages will be lost.
+// Messages can also be subscribed to be rendered in the T-UI as they happen, and mirrored into a log file.
 var StdHelper IStdHelper = &stdHelper{
 	mutex:          &sync.RWMutex{},
 	queuedMessages: nil,
@@ -38,9 +111,13 @@ type stdHelper struct {
 	mutex          *sync.RWMutex
 	enabledQueue   bool
 	queuedMessages []*queuedMessage
+	subscribers    []chan LogEntry
+	logWriter      io.Writer
 }
 
 func (h *stdHelper) Println(message any) {
+	h.log(LogLevelInfo, message, nil)
+
 	if !h.isQueueEnabled() {
 		fmt.Println(message)
 		return
@@ -50,6 +127,45 @@ func (h *stdHelper) Println(message any) {
 }
 
 func (h *stdHelper) PrintlnStdErr(message any) {
+	h.log(LogLevelError, message, nil)
+
+	if !h.isQueueEnabled() {
+		PrintlnStdErr(message)
+		return
+	}
+
+	h.queueMessage(message, true)
+}
+
+func (h *stdHelper) PrintlnWarn(message any, err error) {
+	h.log(LogLevelWarn, message, err)
+	h.printlnStdErrWithPrefix("WARN", message, err)
+}
+
+func (h *stdHelper) PrintlnErr(message any, err error) {
+	h.log(LogLevelError, message, err)
+	h.printlnStdErrWithPrefix("ERR", message, err)
+}
+
+// printlnStdErrWithPrefix prints the prefixed message to stderr, followed by the error on the next line if not nil,
+// or queue them as a single message if queue is enabled.
+func (h *stdHelper) printlnStdErrWithPrefix(prefix string, message any, err error) {
+	msg := fmt.Sprintf("%s: %v", prefix, message)
+	if err != nil {
+		msg = fmt.Sprintf("%s\n%v", msg, err)
+	}
+
+	if !h.isQueueEnabled() {
+		PrintlnStdErr(msg)
+		return
+	}
+
+	h.queueMessage(msg, true)
+}
+
+func (h *stdHelper) PrintlnInfoStdErr(message any) {
+	h.log(LogLevelInfo, message, nil)
+
 	if !h.isQueueEnabled() {
 		PrintlnStdErr(message)
 		return
@@ -98,3 +214,42 @@ func (h *stdHelper) PrintQueuedMessages() {
 
 	h.queuedMessages = nil
 }
+
+func (h *stdHelper) SubscribeLogs() <-chan LogEntry {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	subscriber := make(chan LogEntry, logsSubscriptionBufferSize)
+	h.subscribers = append(h.subscribers, subscriber)
+	return subscriber
+}
+
+func (h *stdHelper) SetLogWriter(writer io.Writer) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.logWriter = writer
+}
+
+// log sends the message to the subscribers and the log writer.
+func (h *stdHelper) log(level LogLevel, message any, err error) {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+
+	if len(h.subscribers) < 1 && h.logWriter == nil {
+		return
+	}
+
+	entry := newLogEntry(time.Now(), level, message, err)
+
+	for _, subscriber := range h.subscribers {
+		select {
+		case subscriber <- entry:
+		default: // drop if full
+		}
+	}
+
+	if h.logWriter != nil {
+		_, _ = io.WriteString(h.logWriter, entry.String()+"\n") // nowhere to report the error
+	}
+}
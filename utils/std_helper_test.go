@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func Test_newLogEntry(t *testing.T) {
+	now := time.Date(2024, 1, 1, 10, 20, 30, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		level       LogLevel
+		message     any
+		err         error
+		wantMessage string
+	}{
+		{
+			name:        "message is kept as is",
+			level:       LogLevelInfo,
+			message:     "ERR: not parsed",
+			wantMessage: "ERR: not parsed",
+		},
+		{
+			name:        "error message",
+			level:       LogLevelError,
+			message:     fmt.Errorf("connection refused"),
+			wantMessage: "connection refused",
+		},
+		{
+			name:        "error is appended to the message",
+			level:       LogLevelWarn,
+			message:     "failed to save renewed streaming session credentials",
+			err:         fmt.Errorf("permission denied"),
+			wantMessage: "failed to save renewed streaming session credentials: permission denied",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := newLogEntry(now, tt.level, tt.message, tt.err)
+			require.Equal(t, now, entry.Time)
+			require.Equal(t, tt.level, entry.Level)
+			require.Equal(t, tt.wantMessage, entry.Message)
+		})
+	}
+
+	require.Equal(t, "2024-01-01T10:20:30.000Z WARN  disk full", LogEntry{
+		Time:    now,
+		Level:   LogLevelWarn,
+		Message: "disk full",
+	}.String())
+}
+
+func Test_stdHelper_logs(t *testing.T) {
+	helper := &stdHelper{
+		mutex: &sync.RWMutex{},
+	}
+	helper.EnableQueue()
+
+	helper.Println("before subscribed")
+
+	logs := helper.SubscribeLogs()
+	logWriter := &bytes.Buffer{}
+	helper.SetLogWriter(logWriter)
+
+	helper.Println("ALERT [firing] low-prevote")
+	helper.PrintlnErr("failed to fetch light validators", fmt.Errorf("connection refused"))
+	helper.PrintlnWarn("failed to save renewed streaming session credentials", nil)
+
+	entry := <-logs
+	require.Equal(t, LogLevelInfo, entry.Level)
+	require.Equal(t, "ALERT [firing] low-prevote", entry.Message)
+	entry = <-logs
+	require.Equal(t, LogLevelError, entry.Level)
+	require.Equal(t, "failed to fetch light validators: connection refused", entry.Message, "message and error are logged as a single entry")
+	entry = <-logs
+	require.Equal(t, LogLevelWarn, entry.Level)
+	require.Equal(t, "failed to save renewed streaming session credentials", entry.Message)
+	require.Empty(t, logs)
+
+	lines := strings.Split(strings.TrimSpace(logWriter.String()), "\n")
+	require.Len(t, lines, 3)
+	require.True(t, strings.HasSuffix(lines[0], " INFO  ALERT [firing] low-prevote"), lines[0])
+	require.True(t, strings.HasSuffix(lines[1], " ERROR failed to fetch light validators: connection refused"), lines[1])
+	require.True(t, strings.HasSuffix(lines[2], " WARN  failed to save renewed streaming session credentials"), lines[2])
+
+	require.Equal(t, "ERR: failed to fetch light validators\nconnection refused", helper.queuedMessages[2].message, "error is printed on the next line")
+	require.True(t, helper.queuedMessages[2].error)
+	require.Equal(t, "WARN: failed to save renewed streaming session credentials", helper.queuedMessages[3].message)
+
+	helper.PrintlnInfoStdErr("*** New URL to share: https://cvp.example.com")
+	entry = <-logs
+	require.Equal(t, LogLevelInfo, entry.Level, "not an error")
+	require.True(t, helper.queuedMessages[len(helper.queuedMessages)-1].error, "must be printed to stderr")
+
+	require.Len(t, helper.queuedMessages, 5, "messages are still queued to be printed after T-UI closed")
+
+	for i := 0; i < logsSubscriptionBufferSize+1; i++ {
+		helper.Println(i) // must not block when the subscriber is full
+	}
+	require.Len(t, logs, logsSubscriptionBufferSize)
+}
@@ -0,0 +1,12 @@
+package utils
+
+import "os"
+
+// IsTerminal returns true if the given file is a terminal (TTY).
+func IsTerminal(file *os.File) bool {
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return false
+	}
+	return fileInfo.Mode()&os.ModeCharDevice != 0
+}